@@ -0,0 +1,188 @@
+package perf
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"testing"
+)
+
+// BloomFilter answers "possibly present" or "definitely absent" for uint64
+// keys. It never returns a false negative; the false positive rate is set
+// when the filter is sized.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    uint64 // probes per key
+}
+
+// NewBloomFilter sizes a filter for n keys at false positive rate p:
+// m = -n·ln(p)/ln(2)² bits and k = m/n·ln(2) probes.
+func NewBloomFilter(n int, p float64) *BloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: max(k, 1)}
+}
+
+// bloomHash mixes the key (the splitmix64 finalizer) so that sequential or
+// clustered IDs spread over the whole bit array.
+func bloomHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// probe returns the i-th bit position for hash h. The k positions are
+// derived from one hash by double hashing, h1 + i·h2, and mapped onto [0, m)
+// with a multiply instead of a division.
+func (f *BloomFilter) probe(h, i uint64) uint64 {
+	h1, h2 := h, h>>32|h<<32|1
+	pos, _ := bits.Mul64(h1+i*h2, f.m)
+	return pos
+}
+
+func (f *BloomFilter) Add(x uint64) {
+	h := bloomHash(x)
+	for i := range f.k {
+		pos := f.probe(h, i)
+		f.bits[pos>>6] |= 1 << (pos & 63)
+	}
+}
+
+func (f *BloomFilter) Contains(x uint64) bool {
+	h := bloomHash(x)
+	for i := range f.k {
+		pos := f.probe(h, i)
+		if f.bits[pos>>6]&(1<<(pos&63)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type uint64Set interface {
+	Add(x uint64)
+	Contains(x uint64) bool
+}
+
+type Uint64MapSet map[uint64]struct{}
+
+func (s Uint64MapSet) Add(x uint64) { s[x] = struct{}{} }
+
+func (s Uint64MapSet) Contains(x uint64) bool {
+	_, ok := s[x]
+	return ok
+}
+
+var bloomCardinalities = []struct {
+	name string
+	n    int
+}{{"100K", 100_000}, {"1M", 1_000_000}, {"4M", 4_000_000}}
+
+var bloomBuilders = []struct {
+	name    string
+	newSet  func(n int) uint64Set
+	isBloom bool
+}{
+	{"Bloom1pct", func(n int) uint64Set { return NewBloomFilter(n, 0.01) }, true},
+	{"Bloom0.1pct", func(n int) uint64Set { return NewBloomFilter(n, 0.001) }, true},
+	{"Map", func(n int) uint64Set { return make(Uint64MapSet, n) }, false},
+}
+
+// bloomKeys returns n random keys; the same seed gives the same keys
+func bloomKeys(n int, seed uint64) []uint64 {
+	rng := rand.New(rand.NewPCG(seed, seed+1))
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = rng.Uint64()
+	}
+	return keys
+}
+
+// bloomHeapInUse returns the live heap after a full collection
+func bloomHeapInUse() uint64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+var sinkBloomHits int
+
+// buildSet returns a set holding keys and the heap it occupies
+func buildSet(newSet func(n int) uint64Set, keys []uint64) (uint64Set, uint64) {
+	before := bloomHeapInUse()
+	set := newSet(len(keys))
+	for _, k := range keys {
+		set.Add(k)
+	}
+	return set, bloomHeapInUse() - before
+}
+
+// BenchmarkBloomAdd builds each set from n keys; each op is one full build,
+// reported per key as ns/key.
+func BenchmarkBloomAdd(b *testing.B) {
+	for _, c := range bloomCardinalities {
+		keys := bloomKeys(c.n, 1)
+		for _, sb := range bloomBuilders {
+			b.Run(fmt.Sprintf("Keys%s/%s", c.name, sb.name), func(b *testing.B) {
+				var set uint64Set
+				for b.Loop() {
+					set = sb.newSet(c.n)
+					for _, k := range keys {
+						set.Add(k)
+					}
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/float64(c.n), "ns/key")
+				runtime.KeepAlive(set)
+			})
+		}
+	}
+}
+
+// BenchmarkBloomContains looks up a mix of members and absent keys, half of
+// each; each op is one lookup. B/key is the memory the set holds per member,
+// and fp-pct the share of 100K absent keys a Bloom filter claims to contain.
+func BenchmarkBloomContains(b *testing.B) {
+	for _, c := range bloomCardinalities {
+		keys := bloomKeys(c.n, 1)
+		// random 64-bit keys from another seed are absent in practice
+		absent := bloomKeys(100_000, 99)
+		queries := make([]uint64, 0, 8192)
+		for i, x := range absent[:4096] {
+			queries = append(queries, keys[i*(c.n/4096)], x)
+		}
+
+		for _, sb := range bloomBuilders {
+			b.Run(fmt.Sprintf("Keys%s/%s", c.name, sb.name), func(b *testing.B) {
+				set, setBytes := buildSet(sb.newSet, keys)
+
+				falsePositives := 0
+				for _, x := range absent {
+					if set.Contains(x) {
+						falsePositives++
+					}
+				}
+
+				hits, i := 0, 0
+				for b.Loop() {
+					if set.Contains(queries[i%len(queries)]) {
+						hits++
+					}
+					i++
+				}
+				sinkBloomHits = hits
+				b.ReportMetric(float64(setBytes), "set-B")
+				b.ReportMetric(float64(setBytes)/float64(c.n), "B/key")
+				if sb.isBloom {
+					b.ReportMetric(100*float64(falsePositives)/float64(len(absent)), "fp-pct")
+				}
+				runtime.KeepAlive(set)
+			})
+		}
+	}
+}
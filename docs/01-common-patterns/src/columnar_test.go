@@ -0,0 +1,312 @@
+package perf
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+const salesRows = 250_000
+
+var salesRegions = []string{"EU", "US", "APAC", "LATAM"}
+
+// salesCSV returns a sales table as CSV text: id,price,qty,region.
+func salesCSV(rows int) []byte {
+	rng := rand.New(rand.NewPCG(5, 6))
+	var buf bytes.Buffer
+	buf.WriteString("id,price,qty,region\n")
+	for i := range rows {
+		fmt.Fprintf(&buf, "%d,%.2f,%d,%s\n", i, 1+rng.Float64()*99, 1+rng.IntN(20), salesRegions[rng.IntN(len(salesRegions))])
+	}
+	return buf.Bytes()
+}
+
+// AnyTable stores each row as a slice of interface{} cells, the shape a
+// generic CSV or SQL loader without a schema produces.
+type AnyTable struct {
+	Header []string
+	Rows   [][]any
+}
+
+// parseCell stores a field as int64, float64 or string, whichever parses.
+func parseCell(field string) any {
+	if v, err := strconv.ParseInt(field, 10, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseFloat(field, 64); err == nil {
+		return v
+	}
+	return field
+}
+
+func LoadAnyTable(r io.Reader) (*AnyTable, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	t := &AnyTable{Header: append([]string(nil), header...)}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return t, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make([]any, len(rec))
+		for i, field := range rec {
+			row[i] = parseCell(field)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+}
+
+// Sale is one typed row.
+type Sale struct {
+	ID     int64
+	Price  float64
+	Qty    int64
+	Region string
+}
+
+func LoadSales(r io.Reader) ([]Sale, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	if _, err := cr.Read(); err != nil {
+		return nil, err
+	}
+	var sales []Sale
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return sales, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var s Sale
+		if s.ID, err = strconv.ParseInt(rec[0], 10, 64); err != nil {
+			return nil, err
+		}
+		if s.Price, err = strconv.ParseFloat(rec[1], 64); err != nil {
+			return nil, err
+		}
+		if s.Qty, err = strconv.ParseInt(rec[2], 10, 64); err != nil {
+			return nil, err
+		}
+		s.Region = rec[3]
+		sales = append(sales, s)
+	}
+}
+
+// SalesColumns stores every column in its own typed slice. Region is
+// dictionary-encoded: a small code per row indexing the distinct names.
+type SalesColumns struct {
+	ID      []int64
+	Price   []float64
+	Qty     []int64
+	Region  []uint8
+	Regions []string
+}
+
+func LoadSalesColumns(r io.Reader) (*SalesColumns, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	if _, err := cr.Read(); err != nil {
+		return nil, err
+	}
+	c := &SalesColumns{}
+	codes := make(map[string]uint8)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.ParseInt(rec[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		price, err := strconv.ParseFloat(rec[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		qty, err := strconv.ParseInt(rec[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		code, ok := codes[rec[3]]
+		if !ok {
+			if len(c.Regions) == 256 {
+				return nil, fmt.Errorf("more than 256 regions")
+			}
+			code = uint8(len(c.Regions))
+			codes[rec[3]] = code
+			c.Regions = append(c.Regions, rec[3])
+		}
+		c.ID = append(c.ID, id)
+		c.Price = append(c.Price, price)
+		c.Qty = append(c.Qty, qty)
+		c.Region = append(c.Region, code)
+	}
+}
+
+// Each query is implemented once per layout. SumPrice reads one column;
+// Revenue filters on region and multiplies two columns.
+
+func (t *AnyTable) SumPrice() float64 {
+	var sum float64
+	for _, row := range t.Rows {
+		sum += row[1].(float64)
+	}
+	return sum
+}
+
+func (t *AnyTable) Revenue(region string) float64 {
+	var sum float64
+	for _, row := range t.Rows {
+		if row[3].(string) == region {
+			sum += row[1].(float64) * float64(row[2].(int64))
+		}
+	}
+	return sum
+}
+
+func sumPrice(sales []Sale) float64 {
+	var sum float64
+	for i := range sales {
+		sum += sales[i].Price
+	}
+	return sum
+}
+
+func revenue(sales []Sale, region string) float64 {
+	var sum float64
+	for i := range sales {
+		if sales[i].Region == region {
+			sum += sales[i].Price * float64(sales[i].Qty)
+		}
+	}
+	return sum
+}
+
+func (c *SalesColumns) SumPrice() float64 {
+	var sum float64
+	for _, p := range c.Price {
+		sum += p
+	}
+	return sum
+}
+
+func (c *SalesColumns) Revenue(region string) float64 {
+	code := -1
+	for i, name := range c.Regions {
+		if name == region {
+			code = i
+		}
+	}
+	var sum float64
+	for i, r := range c.Region {
+		if int(r) == code {
+			sum += c.Price[i] * float64(c.Qty[i])
+		}
+	}
+	return sum
+}
+
+var sinkSum float64
+
+// salesHeapInUse returns the live heap after a full collection.
+func salesHeapInUse() uint64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// loadMeasured loads data and returns the table with the heap it holds.
+func loadMeasured[T any](data []byte, load func(io.Reader) (T, error)) (T, uint64, error) {
+	before := salesHeapInUse()
+	table, err := load(bytes.NewReader(data))
+	after := salesHeapInUse()
+	// data must not be collected between the two readings
+	runtime.KeepAlive(data)
+	return table, after - before, err
+}
+
+// BenchmarkSalesLoad parses the CSV into each layout; MB/s is CSV input.
+func BenchmarkSalesLoad(b *testing.B) {
+	data := salesCSV(salesRows)
+	loaders := []struct {
+		name string
+		load func(io.Reader) (any, error)
+	}{
+		{"AnyRows", func(r io.Reader) (any, error) { return LoadAnyTable(r) }},
+		{"StructRows", func(r io.Reader) (any, error) { return LoadSales(r) }},
+		{"Columns", func(r io.Reader) (any, error) { return LoadSalesColumns(r) }},
+	}
+	for _, l := range loaders {
+		b.Run(l.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := l.load(bytes.NewReader(data)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSalesQuery runs each query over the loaded table. Each op is a
+// full scan; rows/s and table-B, the heap the table holds, make the layouts
+// comparable.
+func BenchmarkSalesQuery(b *testing.B) {
+	data := salesCSV(salesRows)
+	anyTable, anyBytes, err1 := loadMeasured(data, LoadAnyTable)
+	sales, salesBytes, err2 := loadMeasured(data, LoadSales)
+	columns, columnBytes, err3 := loadMeasured(data, LoadSalesColumns)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		b.Fatal(err)
+	}
+
+	layouts := []struct {
+		name     string
+		bytes    uint64
+		sumPrice func() float64
+		revenue  func(region string) float64
+	}{
+		{"AnyRows", anyBytes, anyTable.SumPrice, anyTable.Revenue},
+		{"StructRows", salesBytes, func() float64 { return sumPrice(sales) }, func(r string) float64 { return revenue(sales, r) }},
+		{"Columns", columnBytes, columns.SumPrice, columns.Revenue},
+	}
+	for _, l := range layouts {
+		b.Run("SumPrice/"+l.name, func(b *testing.B) {
+			benchmarkScan(b, l.bytes, l.sumPrice)
+		})
+	}
+	for _, l := range layouts {
+		b.Run("Revenue/"+l.name, func(b *testing.B) {
+			benchmarkScan(b, l.bytes, func() float64 { return l.revenue("EU") })
+		})
+	}
+}
+
+func benchmarkScan(b *testing.B, tableBytes uint64, scan func() float64) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkSum = scan()
+	}
+	b.ReportMetric(float64(salesRows)*float64(b.N)/b.Elapsed().Seconds(), "rows/s")
+	b.ReportMetric(float64(tableBytes), "table-B")
+}
@@ -0,0 +1,167 @@
+package perf
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	fanOutTasks = 64
+	// A long task is taskChunks chunks of work and checks for cancellation
+	// between them, like a loop over rows or a paged download would.
+	taskChunks = 50
+	chunkWork  = 2000
+)
+
+var errTaskFailed = errors.New("task failed")
+
+var sinkSpin atomic.Uint64
+
+// spin does n iterations of a data-dependent LCG, so it cannot be elided
+func spin(n int) {
+	x := uint64(n)
+	for range n {
+		x = x*6364136223846793005 + 1442695040888963407
+	}
+	sinkSpin.Add(x & 1)
+}
+
+type task func(ctx context.Context, i int) error
+
+// runErrGroup: errgroup.Group without a context. Wait returns the first
+// error, but nothing tells the other tasks to stop.
+func runErrGroup(n int, t task) error {
+	var g errgroup.Group
+	for i := range n {
+		g.Go(func() error { return t(context.Background(), i) })
+	}
+	return g.Wait()
+}
+
+// runErrGroupCtx: errgroup.WithContext cancels ctx on the first error.
+func runErrGroupCtx(n int, t task) error {
+	g, ctx := errgroup.WithContext(context.Background())
+	for i := range n {
+		g.Go(func() error { return t(ctx, i) })
+	}
+	return g.Wait()
+}
+
+// runWaitGroup: the hand-written equivalent of errgroup.WithContext, a
+// WaitGroup plus a one-slot error channel that keeps the first error.
+func runWaitGroup(n int, t task) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errc := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := t(ctx, i); err != nil {
+				select {
+				case errc <- err:
+					cancel()
+				default:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case err := <-errc:
+		return err
+	default:
+		return nil
+	}
+}
+
+// runResultsChannel: every task sends its result and the caller returns on
+// the first error without waiting for the rest. The buffer lets the
+// cancelled tasks finish their send and exit with no one receiving.
+func runResultsChannel(n int, t task) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan error, n)
+	for i := range n {
+		go func() { results <- t(ctx, i) }()
+	}
+	for range n {
+		if err := <-results; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var fanOutStrategies = []struct {
+	name string
+	run  func(n int, t task) error
+}{
+	{"ErrGroup", runErrGroup},
+	{"ErrGroupCtx", runErrGroupCtx},
+	{"WaitGroup", runWaitGroup},
+	{"ResultsChannel", runResultsChannel},
+}
+
+// BenchmarkFanOutNoError measures the bookkeeping of each strategy: tasks
+// that do almost nothing and never fail.
+func BenchmarkFanOutNoError(b *testing.B) {
+	quick := func(ctx context.Context, i int) error {
+		spin(100)
+		return nil
+	}
+	for _, s := range fanOutStrategies {
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := s.run(fanOutTasks, quick); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFanOutFirstFails runs long tasks of which one fails immediately.
+// err-latency-us is the time from that failure to the caller getting the
+// error: without cancellation it includes every other task running to
+// completion.
+func BenchmarkFanOutFirstFails(b *testing.B) {
+	for _, s := range fanOutStrategies {
+		b.Run(s.name, func(b *testing.B) {
+			var failedAt atomic.Int64
+			long := func(ctx context.Context, i int) error {
+				if i == fanOutTasks/2 {
+					failedAt.Store(time.Now().UnixNano())
+					return errTaskFailed
+				}
+				for range taskChunks {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					spin(chunkWork)
+				}
+				return nil
+			}
+
+			var latency time.Duration
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := s.run(fanOutTasks, long); !errors.Is(err, errTaskFailed) {
+					b.Fatalf("got %v, want %v", err, errTaskFailed)
+				}
+				latency += time.Since(time.Unix(0, failedAt.Load()))
+			}
+			b.ReportMetric(float64(latency.Microseconds())/float64(b.N), "err-latency-us")
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package perf
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type Config struct {
+	LogLevel string
+	Timeout  time.Duration
+	Features map[string]bool
+}
+
+func NewConfig(logLevel string, timeout time.Duration, features map[string]bool) *Config {
+	copiedFeatures := make(map[string]bool, len(features))
+	for k, v := range features {
+		copiedFeatures[k] = v
+	}
+	return &Config{
+		LogLevel: logLevel,
+		Timeout:  timeout,
+		Features: copiedFeatures,
+	}
+}
+
+// ConfigStore is the shared configuration as request handlers see it.
+type ConfigStore interface {
+	// Lookup returns the request timeout and whether feature is enabled.
+	Lookup(feature string) (time.Duration, bool)
+	Reload(cfg *Config)
+}
+
+// AtomicConfig swaps an immutable *Config; readers never block.
+type AtomicConfig struct{ p atomic.Pointer[Config] }
+
+func (c *AtomicConfig) Lookup(feature string) (time.Duration, bool) {
+	cfg := c.p.Load()
+	return cfg.Timeout, cfg.Features[feature]
+}
+
+func (c *AtomicConfig) Reload(cfg *Config) { c.p.Store(cfg) }
+
+// MutexConfig guards the current *Config with a mutex.
+type MutexConfig struct {
+	mu  sync.Mutex
+	cfg *Config
+}
+
+func (c *MutexConfig) Lookup(feature string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cfg.Timeout, c.cfg.Features[feature]
+}
+
+func (c *MutexConfig) Reload(cfg *Config) {
+	c.mu.Lock()
+	c.cfg = cfg
+	c.mu.Unlock()
+}
+
+// RWMutexConfig lets readers share the lock.
+type RWMutexConfig struct {
+	mu  sync.RWMutex
+	cfg *Config
+}
+
+func (c *RWMutexConfig) Lookup(feature string) (time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cfg.Timeout, c.cfg.Features[feature]
+}
+
+func (c *RWMutexConfig) Reload(cfg *Config) {
+	c.mu.Lock()
+	c.cfg = cfg
+	c.mu.Unlock()
+}
+
+// SyncMapConfig keeps every setting under its own key. A reload updates the
+// keys one by one, so unlike the others a reader can see a mix of the old
+// and the new config.
+type SyncMapConfig struct{ m sync.Map }
+
+func (c *SyncMapConfig) Lookup(feature string) (time.Duration, bool) {
+	timeout, _ := c.m.Load("timeout")
+	enabled, _ := c.m.Load("feature:" + feature)
+	on, _ := enabled.(bool)
+	return timeout.(time.Duration), on
+}
+
+func (c *SyncMapConfig) Reload(cfg *Config) {
+	c.m.Store("log_level", cfg.LogLevel)
+	c.m.Store("timeout", cfg.Timeout)
+	for name, on := range cfg.Features {
+		c.m.Store("feature:"+name, on)
+	}
+}
+
+var configStores = []struct {
+	name     string
+	newStore func() ConfigStore
+}{
+	{"AtomicPointer", func() ConfigStore { return &AtomicConfig{} }},
+	{"Mutex", func() ConfigStore { return &MutexConfig{} }},
+	{"RWMutex", func() ConfigStore { return &RWMutexConfig{} }},
+	{"SyncMap", func() ConfigStore { return &SyncMapConfig{} }},
+}
+
+// testFeatures is a config with 32 feature flags, "beta" among them
+func testFeatures(on bool) map[string]bool {
+	features := map[string]bool{"beta": on}
+	for i := range 31 {
+		features[fmt.Sprintf("flag-%d", i)] = on
+	}
+	return features
+}
+
+var sinkTimeout time.Duration
+
+// benchmarkLookups runs Lookup from every CPU; each op is one lookup.
+func benchmarkLookups(b *testing.B, store ConfigStore) {
+	b.RunParallel(func(pb *testing.PB) {
+		var total time.Duration
+		for pb.Next() {
+			timeout, beta := store.Lookup("beta")
+			if beta {
+				total += timeout
+			}
+		}
+		sinkTimeout = total
+	})
+}
+
+// BenchmarkConfigRead measures the read path with no reloads.
+func BenchmarkConfigRead(b *testing.B) {
+	for _, s := range configStores {
+		b.Run(s.name, func(b *testing.B) {
+			store := s.newStore()
+			store.Reload(NewConfig("info", 5*time.Second, testFeatures(true)))
+			benchmarkLookups(b, store)
+		})
+	}
+}
+
+// BenchmarkConfigReloadStorm measures the same reads while another goroutine
+// reloads the config back to back, building a fresh copy each time as a
+// reload from disk would. reloads/s shows how much reload work got done
+// alongside the readers.
+func BenchmarkConfigReloadStorm(b *testing.B) {
+	features := []map[string]bool{testFeatures(true), testFeatures(false)}
+	for _, s := range configStores {
+		b.Run(s.name, func(b *testing.B) {
+			store := s.newStore()
+			store.Reload(NewConfig("info", 5*time.Second, features[0]))
+
+			stop := make(chan struct{})
+			done := make(chan int)
+			go func() {
+				reloads := 0
+				for {
+					select {
+					case <-stop:
+						done <- reloads
+						return
+					default:
+					}
+					store.Reload(NewConfig("debug", time.Duration(reloads%2+1)*time.Second, features[reloads%2]))
+					reloads++
+				}
+			}()
+
+			start := time.Now()
+			benchmarkLookups(b, store)
+			elapsed := time.Since(start)
+			close(stop)
+			b.ReportMetric(float64(<-done)/elapsed.Seconds(), "reloads/s")
+		})
+	}
+}
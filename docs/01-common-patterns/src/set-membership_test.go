@@ -0,0 +1,121 @@
+package perf
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// Bitset is a set of integers in [0, n) with one bit per possible member.
+type Bitset []uint64
+
+func NewBitset(n int) Bitset { return make(Bitset, (n+63)/64) }
+
+func (s Bitset) Add(x uint32)           { s[x>>6] |= 1 << (x & 63) }
+func (s Bitset) Contains(x uint32) bool { return s[x>>6]&(1<<(x&63)) != 0 }
+
+// SortedSet is a sorted slice searched with binary search.
+type SortedSet []uint32
+
+func (s SortedSet) Contains(x uint32) bool {
+	_, found := slices.BinarySearch(s, x)
+	return found
+}
+
+type MapSet map[uint32]struct{}
+
+func (s MapSet) Contains(x uint32) bool {
+	_, ok := s[x]
+	return ok
+}
+
+type membershipSet interface{ Contains(x uint32) bool }
+
+// setMembers returns a random density share of the integers in [0, universe).
+func setMembers(universe int, density float64) []uint32 {
+	rng := rand.New(rand.NewPCG(1, 2))
+	var members []uint32
+	for x := range universe {
+		if rng.Float64() < density {
+			members = append(members, uint32(x))
+		}
+	}
+	return members
+}
+
+var setBuilders = []struct {
+	name  string
+	build func(universe int, members []uint32) membershipSet
+}{
+	{"Bitset", func(universe int, members []uint32) membershipSet {
+		s := NewBitset(universe)
+		for _, x := range members {
+			s.Add(x)
+		}
+		return s
+	}},
+	{"Map", func(universe int, members []uint32) membershipSet {
+		s := make(MapSet, len(members))
+		for _, x := range members {
+			s[x] = struct{}{}
+		}
+		return s
+	}},
+	{"SortedSlice", func(universe int, members []uint32) membershipSet {
+		// members are generated in order; a real set would sort here
+		return SortedSet(slices.Clone(members))
+	}},
+}
+
+// heapInUse returns the live heap after a full collection.
+func heapInUse() uint64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+var sinkHits int
+
+// BenchmarkSetContains looks up random integers from the whole universe, so
+// the hit rate equals the density. Each op is one lookup; set-B is the
+// memory the set holds.
+func BenchmarkSetContains(b *testing.B) {
+	universes := []struct {
+		name string
+		size int
+	}{{"1K", 1 << 10}, {"64K", 1 << 16}, {"1M", 1 << 20}}
+	for _, u := range universes {
+		universe := u.size
+		for _, density := range []float64{0.01, 0.10, 0.50} {
+			members := setMembers(universe, density)
+			queries := make([]uint32, 4096)
+			rng := rand.New(rand.NewPCG(3, 4))
+			for i := range queries {
+				queries[i] = uint32(rng.IntN(universe))
+			}
+
+			for _, sb := range setBuilders {
+				name := fmt.Sprintf("Universe%s/Density%.0fpct/%s", u.name, density*100, sb.name)
+				b.Run(name, func(b *testing.B) {
+					before := heapInUse()
+					set := sb.build(universe, members)
+					setBytes := heapInUse() - before
+
+					hits, i := 0, 0
+					for b.Loop() {
+						if set.Contains(queries[i%len(queries)]) {
+							hits++
+						}
+						i++
+					}
+					sinkHits = hits
+					b.ReportMetric(float64(setBytes), "set-B")
+					runtime.KeepAlive(set)
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package perf
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// Slab hands out objects of type T carved from large []byte chunks and
+// frees them all at once with Reset. T must not contain pointers: the GC
+// does not scan []byte memory, so anything referenced only from a slab
+// object could be collected while still in use.
+type Slab[T any] struct {
+	chunks    [][]byte
+	chunk     int // index of the chunk being carved
+	off       int // next free byte in it
+	chunkSize int
+}
+
+// NewSlab returns a slab that allocates perChunk objects at a time.
+func NewSlab[T any](perChunk int) *Slab[T] {
+	var zero T
+	return &Slab[T]{chunkSize: perChunk * int(unsafe.Sizeof(zero))}
+}
+
+// New returns a zeroed *T that stays valid until the next Reset.
+func (s *Slab[T]) New() *T {
+	size := int(unsafe.Sizeof(*new(T)))
+	if s.chunk == len(s.chunks) || s.off+size > s.chunkSize {
+		if s.chunk < len(s.chunks) {
+			s.chunk++
+		}
+		if s.chunk == len(s.chunks) {
+			// Large allocations are at least 8-byte aligned, and a type's
+			// size is a multiple of its alignment, so every object lands
+			// aligned.
+			s.chunks = append(s.chunks, make([]byte, s.chunkSize))
+		}
+		s.off = 0
+	}
+	p := (*T)(unsafe.Pointer(&s.chunks[s.chunk][s.off]))
+	s.off += size
+	*p = *new(T)
+	return p
+}
+
+// Reset frees every object at once. The chunks are kept for reuse, so a
+// slab that has seen its peak load stops allocating.
+func (s *Slab[T]) Reset() {
+	s.chunk, s.off = 0, 0
+}
+
+// Span is a pointer-free record, as a tracer or a query executor would
+// create dozens of per request.
+type Span struct {
+	ID       uint64
+	ParentID uint64
+	Start    int64
+	End      int64
+	Status   uint16
+	Tags     [32]byte
+}
+
+const spansPerRequest = 64
+
+// handleRequest fills spansPerRequest spans from alloc and keeps them in
+// spans until the request ends, then sums their durations.
+func handleRequest(spans []*Span, alloc func() *Span) int64 {
+	for i := range spans {
+		s := alloc()
+		s.ID = uint64(i)
+		s.ParentID = uint64(i / 8)
+		s.Start = int64(i) * 100
+		s.End = s.Start + int64(i%7)*10
+		s.Status = 200
+		spans[i] = s
+	}
+	var total int64
+	for _, s := range spans {
+		total += s.End - s.Start
+	}
+	return total
+}
+
+var sinkTotal int64
+
+// BenchmarkSpanHeap allocates every span on the heap and leaves them to the GC.
+func BenchmarkSpanHeap(b *testing.B) {
+	spans := make([]*Span, spansPerRequest)
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkTotal += handleRequest(spans, func() *Span { return new(Span) })
+	}
+}
+
+var spanPool = sync.Pool{New: func() any { return new(Span) }}
+
+// BenchmarkSpanPool takes every span from a sync.Pool and returns each one
+// when the request ends.
+func BenchmarkSpanPool(b *testing.B) {
+	spans := make([]*Span, spansPerRequest)
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkTotal += handleRequest(spans, func() *Span {
+			s := spanPool.Get().(*Span)
+			*s = Span{}
+			return s
+		})
+		for i, s := range spans {
+			spanPool.Put(s)
+			spans[i] = nil
+		}
+	}
+}
+
+// BenchmarkSpanSlab carves the spans from a slab and frees them all with
+// one Reset when the request ends.
+func BenchmarkSpanSlab(b *testing.B) {
+	spans := make([]*Span, spansPerRequest)
+	slab := NewSlab[Span](256)
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkTotal += handleRequest(spans, slab.New)
+		clear(spans)
+		slab.Reset()
+	}
+}
@@ -1,8 +1,13 @@
 package perf
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"io"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"crypto/sha256"
@@ -188,3 +193,134 @@ func BenchmarkMmapNoCopyXXHash(b *testing.B) {
 	}
 // bench-hash-mmap-end
 }
+
+// recordCount length-prefixed records of 16-512 bytes, about 17MB in total
+const recordCount = 64 * 1024
+
+// writeRecordFile writes recordCount records, each a little-endian uint32
+// length followed by that many payload bytes.
+func writeRecordFile(path string) error {
+	rng := rand.New(rand.NewPCG(7, 8))
+	w := bytes.NewBuffer(nil)
+	payload := make([]byte, 512)
+	for range recordCount {
+		n := 16 + rng.IntN(512-16+1)
+		for i := range payload[:n] {
+			payload[i] = byte(rng.Uint32())
+		}
+		w.Write(binary.LittleEndian.AppendUint32(nil, uint32(n)))
+		w.Write(payload[:n])
+	}
+	return os.WriteFile(path, w.Bytes(), 0o644)
+}
+
+// bench-index-start
+// recordRef locates one record's payload in the file.
+type recordRef struct {
+	off int64
+	len int32
+}
+
+// buildRecordIndex walks the length prefixes of a mapped record file once
+// and returns where every payload lives. Nothing is copied: the walk only
+// reads the 4-byte headers from the mapped pages.
+func buildRecordIndex(data []byte) ([]recordRef, error) {
+	var index []recordRef
+	for off := 0; off < len(data); {
+		if off+4 > len(data) {
+			return nil, fmt.Errorf("truncated header at %d", off)
+		}
+		n := int(binary.LittleEndian.Uint32(data[off:]))
+		if off+4+n > len(data) {
+			return nil, fmt.Errorf("truncated record at %d", off)
+		}
+		index = append(index, recordRef{off: int64(off + 4), len: int32(n)})
+		off += 4 + n
+	}
+	return index, nil
+}
+
+// bench-index-end
+
+// openRecordFile writes a record file, maps it and indexes it. The file
+// stays open so ReadAt lookups can use it too.
+func openRecordFile(b *testing.B) (*os.File, []byte, []recordRef) {
+	path := filepath.Join(b.TempDir(), "records.bin")
+	if err := writeRecordFile(path); err != nil {
+		b.Fatalf("write records: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		b.Fatalf("open: %v", err)
+	}
+	b.Cleanup(func() { f.Close() })
+	st, err := f.Stat()
+	if err != nil {
+		b.Fatalf("stat: %v", err)
+	}
+	data, err := unix.Mmap(int(f.Fd()), 0, int(st.Size()), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		b.Fatalf("mmap: %v", err)
+	}
+	b.Cleanup(func() {
+		if err := unix.Munmap(data); err != nil {
+			b.Errorf("munmap: %v", err)
+		}
+	})
+	index, err := buildRecordIndex(data)
+	if err != nil {
+		b.Fatalf("index: %v", err)
+	}
+	return f, data, index
+}
+
+// recordLookups is a fixed sequence of random record numbers.
+func recordLookups() []int {
+	rng := rand.New(rand.NewPCG(9, 10))
+	lookups := make([]int, 4096)
+	for i := range lookups {
+		lookups[i] = rng.IntN(recordCount)
+	}
+	return lookups
+}
+
+// BenchmarkRecordLookupReadAt fetches random records with one pread(2)
+// each, copying the payload into a reused buffer before hashing it.
+func BenchmarkRecordLookupReadAt(b *testing.B) {
+	f, _, index := openRecordFile(b)
+	lookups := recordLookups()
+
+// bench-lookup-readat-start
+	buf := make([]byte, 512)
+	var sum uint64
+	i := 0
+	for b.Loop() {
+		ref := index[lookups[i%len(lookups)]]
+		if _, err := f.ReadAt(buf[:ref.len], ref.off); err != nil {
+			b.Fatal(err)
+		}
+		sum += xxhash.Sum64(buf[:ref.len])
+		i++
+	}
+// bench-lookup-readat-end
+	_ = sum
+}
+
+// BenchmarkRecordLookupMmap hashes random records in place: each lookup is
+// a subslice of the mapping, with no syscall and no copy.
+func BenchmarkRecordLookupMmap(b *testing.B) {
+	_, data, index := openRecordFile(b)
+	lookups := recordLookups()
+
+// bench-lookup-mmap-start
+	var sum uint64
+	i := 0
+	for b.Loop() {
+		ref := index[lookups[i%len(lookups)]]
+		record := data[ref.off : ref.off+int64(ref.len)] // no copy
+		sum += xxhash.Sum64(record)
+		i++
+	}
+// bench-lookup-mmap-end
+	_ = sum
+}
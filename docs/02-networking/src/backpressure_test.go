@@ -0,0 +1,226 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	queueCapacity = 64
+	// consumerWork is the per-item cost of the consumer, in spin iterations.
+	// Producers only enqueue, so the consumer is always the bottleneck.
+	consumerWork = 2000
+)
+
+// Queue is a bounded queue with an overload policy. Put never fails: when
+// the queue is full it blocks or discards an item, depending on the policy.
+type Queue interface {
+	Put(item int)
+	Get() (item int, ok bool) // ok is false once the queue is closed and drained
+	Close()
+	Dropped() int64
+}
+
+// BlockingQueue blocks producers while the queue is full.
+type BlockingQueue struct{ ch chan int }
+
+func NewBlockingQueue(capacity int) *BlockingQueue {
+	return &BlockingQueue{ch: make(chan int, capacity)}
+}
+
+func (q *BlockingQueue) Put(item int) { q.ch <- item }
+
+func (q *BlockingQueue) Get() (int, bool) {
+	item, ok := <-q.ch
+	return item, ok
+}
+
+func (q *BlockingQueue) Close()         { close(q.ch) }
+func (q *BlockingQueue) Dropped() int64 { return 0 }
+
+// DropNewestQueue discards the item being put while the queue is full.
+type DropNewestQueue struct {
+	BlockingQueue
+	dropped atomic.Int64
+}
+
+func NewDropNewestQueue(capacity int) *DropNewestQueue {
+	return &DropNewestQueue{BlockingQueue: BlockingQueue{ch: make(chan int, capacity)}}
+}
+
+func (q *DropNewestQueue) Put(item int) {
+	select {
+	case q.ch <- item:
+	default:
+		q.dropped.Add(1)
+	}
+}
+
+func (q *DropNewestQueue) Dropped() int64 { return q.dropped.Load() }
+
+// DropOldestQueue makes room for the new item by discarding the oldest
+// queued one, so consumers always see the most recent items.
+type DropOldestQueue struct {
+	BlockingQueue
+	dropped atomic.Int64
+}
+
+func NewDropOldestQueue(capacity int) *DropOldestQueue {
+	return &DropOldestQueue{BlockingQueue: BlockingQueue{ch: make(chan int, capacity)}}
+}
+
+func (q *DropOldestQueue) Put(item int) {
+	for {
+		select {
+		case q.ch <- item:
+			return
+		default:
+		}
+		// Full: discard the oldest item unless a consumer got to it first
+		select {
+		case <-q.ch:
+			q.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+func (q *DropOldestQueue) Dropped() int64 { return q.dropped.Load() }
+
+// RingQueue is a fixed ring buffer that overwrites the oldest item when
+// full. Unlike DropOldestQueue it does it in place under one lock, with no
+// channel operations.
+type RingQueue struct {
+	mu         sync.Mutex
+	notEmpty   *sync.Cond
+	items      []int
+	head, size int
+	closed     bool
+	dropped    int64
+}
+
+func NewRingQueue(capacity int) *RingQueue {
+	q := &RingQueue{items: make([]int, capacity)}
+	q.notEmpty = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *RingQueue) Put(item int) {
+	q.mu.Lock()
+	if q.size == len(q.items) {
+		// Overwrite the oldest item, which becomes the newest
+		q.items[q.head] = item
+		q.head = (q.head + 1) % len(q.items)
+		q.dropped++
+	} else {
+		q.items[(q.head+q.size)%len(q.items)] = item
+		q.size++
+	}
+	q.mu.Unlock()
+	q.notEmpty.Signal()
+}
+
+func (q *RingQueue) Get() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.size == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if q.size == 0 {
+		return 0, false
+	}
+	item := q.items[q.head]
+	q.head = (q.head + 1) % len(q.items)
+	q.size--
+	return item, true
+}
+
+func (q *RingQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.notEmpty.Broadcast()
+}
+
+func (q *RingQueue) Dropped() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+var consumed atomic.Uint64
+
+// consume simulates processing an item
+func consume(item int) {
+	x := uint64(item)
+	for range consumerWork {
+		x = x*6364136223846793005 + 1442695040888963407
+	}
+	consumed.Add(x & 1)
+}
+
+// BenchmarkBackpressure overloads each queue with producers on every CPU
+// putting as fast as they can into one slow consumer. Each op is one Put.
+// It reports delivered/s (items the consumer processed per second),
+// drop-pct (share of puts discarded) and p99-put-ns, the producer-side
+// latency of a Put.
+func BenchmarkBackpressure(b *testing.B) {
+	policies := []struct {
+		name     string
+		newQueue func() Queue
+	}{
+		{"Block", func() Queue { return NewBlockingQueue(queueCapacity) }},
+		{"DropNewest", func() Queue { return NewDropNewestQueue(queueCapacity) }},
+		{"DropOldest", func() Queue { return NewDropOldestQueue(queueCapacity) }},
+		{"RingOverwrite", func() Queue { return NewRingQueue(queueCapacity) }},
+	}
+	for _, p := range policies {
+		b.Run(p.name, func(b *testing.B) {
+			q := p.newQueue()
+			var delivered atomic.Int64
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for {
+					item, ok := q.Get()
+					if !ok {
+						return
+					}
+					consume(item)
+					delivered.Add(1)
+				}
+			}()
+
+			var (
+				mu        sync.Mutex
+				latencies []time.Duration
+			)
+			start := time.Now()
+			b.RunParallel(func(pb *testing.PB) {
+				var local []time.Duration
+				for i := 0; pb.Next(); i++ {
+					t := time.Now()
+					q.Put(i)
+					local = append(local, time.Since(t))
+				}
+				mu.Lock()
+				latencies = append(latencies, local...)
+				mu.Unlock()
+			})
+			// Throughput counts only what the consumer finished under load,
+			// not the backlog it drains after the producers stop
+			elapsed := time.Since(start)
+			processed := delivered.Load()
+			q.Close()
+			<-done
+
+			slices.Sort(latencies)
+			b.ReportMetric(float64(processed)/elapsed.Seconds(), "delivered/s")
+			b.ReportMetric(float64(q.Dropped())/float64(len(latencies))*100, "drop-pct")
+			b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-put-ns")
+		})
+	}
+}
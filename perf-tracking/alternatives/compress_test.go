@@ -0,0 +1,106 @@
+package alternatives
+
+import (
+	"bytes"
+	stdgzip "compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	kgzip "github.com/klauspost/compress/gzip"
+)
+
+// gzipInput is 64KB of log-like text, compressible the way real payloads are.
+var gzipInput = []byte(strings.Repeat(
+	`{"level":"info","ts":"2024-01-20T12:00:00Z","msg":"request served","path":"/api/v1/users","status":200,"duration_ms":3}`+"\n",
+	560))[:64<<10]
+
+// gzipWriter is the writer API shared by both gzip implementations.
+type gzipWriter interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
+type gzipImpl struct {
+	name      string
+	newWriter func(io.Writer) gzipWriter
+	decode    func([]byte) ([]byte, error)
+}
+
+var gzipImpls = []gzipImpl{
+	{
+		name:      "Stdlib",
+		newWriter: func(w io.Writer) gzipWriter { return stdgzip.NewWriter(w) },
+		decode: func(data []byte) ([]byte, error) {
+			r, err := stdgzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(r)
+		},
+	},
+	{
+		name:      "Klauspost",
+		newWriter: func(w io.Writer) gzipWriter { return kgzip.NewWriter(w) },
+		decode: func(data []byte) ([]byte, error) {
+			r, err := kgzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(r)
+		},
+	},
+}
+
+// BenchmarkAltGzip compares compress/gzip with klauspost/compress/gzip at
+// the default level. Writers are reused via Reset, as servers do.
+func BenchmarkAltGzip(b *testing.B) {
+	b.Run("Compress", func(b *testing.B) {
+		for _, impl := range gzipImpls {
+			b.Run(impl.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(gzipInput)))
+				var buf bytes.Buffer
+				zw := impl.newWriter(&buf)
+				for b.Loop() {
+					buf.Reset()
+					zw.Reset(&buf)
+					if _, err := zw.Write(gzipInput); err != nil {
+						b.Fatal(err)
+					}
+					if err := zw.Close(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	})
+
+	b.Run("Decompress", func(b *testing.B) {
+		// Both readers decode the same stdlib-produced stream
+		var compressed bytes.Buffer
+		zw := stdgzip.NewWriter(&compressed)
+		if _, err := zw.Write(gzipInput); err != nil {
+			b.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			b.Fatal(err)
+		}
+
+		for _, impl := range gzipImpls {
+			b.Run(impl.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(gzipInput)))
+				for b.Loop() {
+					out, err := impl.decode(compressed.Bytes())
+					if err != nil {
+						b.Fatal(err)
+					}
+					if len(out) != len(gzipInput) {
+						b.Fatalf("decoded %d bytes, want %d", len(out), len(gzipInput))
+					}
+				}
+			})
+		}
+	})
+}
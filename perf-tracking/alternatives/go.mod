@@ -0,0 +1,15 @@
+module github.com/astavonin/go-optimization-guide/alternatives
+
+go 1.24.0
+
+require (
+	github.com/go-chi/chi/v5 v5.3.2
+	github.com/goccy/go-json v0.11.1
+	github.com/json-iterator/go v1.1.12
+	github.com/klauspost/compress v1.19.2
+)
+
+require (
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+)
@@ -0,0 +1,59 @@
+package alternatives
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// routes is a small REST API; requests hit the last, parameterized route so
+// routers that scan linearly pay for it.
+var routes = []string{
+	"/api/v1/health",
+	"/api/v1/users",
+	"/api/v1/users/{id}",
+	"/api/v1/users/{id}/orders",
+	"/api/v1/orders/{id}",
+	"/api/v1/orders/{id}/items/{item}",
+}
+
+const routedPath = "/api/v1/orders/42/items/7"
+
+func noopHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BenchmarkAltHTTPRouter compares pattern routing in net/http.ServeMux
+// (Go 1.22+ wildcards) with chi, measuring route matching and dispatch only.
+func BenchmarkAltHTTPRouter(b *testing.B) {
+	mux := http.NewServeMux()
+	r := chi.NewRouter()
+	for _, route := range routes {
+		mux.HandleFunc("GET "+route, noopHandler)
+		r.Get(route, noopHandler)
+	}
+
+	routers := []struct {
+		name    string
+		handler http.Handler
+	}{
+		{"ServeMux", mux},
+		{"Chi", r},
+	}
+
+	for _, rt := range routers {
+		b.Run(rt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			req := httptest.NewRequest(http.MethodGet, routedPath, nil)
+			for b.Loop() {
+				w := httptest.NewRecorder()
+				rt.handler.ServeHTTP(w, req)
+				if w.Code != http.StatusNoContent {
+					b.Fatalf("unexpected status %d", w.Code)
+				}
+			}
+		})
+	}
+}
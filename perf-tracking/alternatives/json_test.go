@@ -0,0 +1,50 @@
+package alternatives
+
+import (
+	"encoding/json"
+	"testing"
+
+	gojson "github.com/goccy/go-json"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// APIResponse mirrors the payload used by the stdlib JSON benchmarks so the
+// numbers line up with BenchmarkJSONDecode.
+type APIResponse struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Email     string         `json:"email"`
+	Tags      []string       `json:"tags"`
+	Metadata  map[string]any `json:"metadata"`
+	CreatedAt string         `json:"created_at"`
+	Active    bool           `json:"active"`
+}
+
+var jsonPayload = []byte(`{"id":123456789,"name":"Extended Test User Profile","email":"extended.user@example.com","tags":["go","performance","benchmark","optimization","stdlib","testing","validation","production"],"metadata":{"score":95.5,"verified":true,"level":"premium","tier":"enterprise","region":"us-west","datacenter":"pdx-1","version":"2.1.0","features":["api","websocket","graphql"],"limits":{"rate":1000,"burst":100,"concurrent":50},"timestamps":{"created":"2024-01-01T00:00:00Z","updated":"2024-01-20T12:00:00Z","expires":"2025-01-20T12:00:00Z"},"contact":{"phone":"+1-555-0100","address":"123 Main St","city":"Portland","state":"OR","zip":"97201"},"preferences":{"notifications":true,"marketing":false,"analytics":true}},"created_at":"2024-01-20T12:00:00Z","active":true}`)
+
+// BenchmarkAltJSONDecode compares encoding/json against popular drop-in
+// decoders on the same typed payload. sonic is left out: it depends on
+// runtime internals and routinely fails to build on new Go releases.
+func BenchmarkAltJSONDecode(b *testing.B) {
+	decoders := []struct {
+		name      string
+		unmarshal func([]byte, any) error
+	}{
+		{"Stdlib", json.Unmarshal},
+		{"Jsoniter", jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal},
+		{"GoccyJSON", gojson.Unmarshal},
+	}
+
+	for _, d := range decoders {
+		b.Run(d.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(jsonPayload)))
+			for b.Loop() {
+				var resp APIResponse
+				if err := d.unmarshal(jsonPayload, &resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
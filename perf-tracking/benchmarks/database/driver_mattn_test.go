@@ -0,0 +1,15 @@
+//go:build mattn
+
+package database
+
+import (
+	_ "github.com/mattn/go-sqlite3" // cgo driver, selected with -tags mattn
+)
+
+const driverName = "sqlite3"
+
+// dsn opens path in WAL mode so pooled connections don't serialize on the
+// rollback journal.
+func dsn(path string) string {
+	return "file:" + path + "?_journal_mode=WAL&_busy_timeout=5000"
+}
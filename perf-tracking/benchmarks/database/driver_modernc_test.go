@@ -0,0 +1,15 @@
+//go:build !mattn
+
+package database
+
+import (
+	_ "modernc.org/sqlite" // pure Go, no cgo: the default so runs stay hermetic
+)
+
+const driverName = "sqlite"
+
+// dsn opens path in WAL mode so pooled connections don't serialize on the
+// rollback journal.
+func dsn(path string) string {
+	return "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+}
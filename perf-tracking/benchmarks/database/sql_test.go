@@ -0,0 +1,194 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+const userRows = 1000
+
+// User is the row type scanned by the benchmarks.
+type User struct {
+	ID    int64
+	Name  string
+	Email string
+	Score float64
+}
+
+// openDB creates a fresh SQLite database in a temp dir with userRows users.
+// A file (not :memory:) is used so every pooled connection sees the same data.
+func openDB(b *testing.B) *sql.DB {
+	b.Helper()
+	db, err := sql.Open(driverName, dsn(filepath.Join(b.TempDir(), "bench.db")))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE users (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		score REAL NOT NULL
+	)`); err != nil {
+		b.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO users (id, name, email, score) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := range userRows {
+		if _, err := stmt.Exec(i, fmt.Sprintf("user-%d", i), fmt.Sprintf("user%d@example.com", i), float64(i)*1.5); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		b.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+	return db
+}
+
+// BenchmarkSQLPrepared compares a prepared statement reused across queries
+// with preparing the same query on every call via db.QueryRow.
+func BenchmarkSQLPrepared(b *testing.B) {
+	const query = `SELECT id, name, email, score FROM users WHERE id = ?`
+	db := openDB(b)
+
+	b.Run("Prepared", func(b *testing.B) {
+		b.ReportAllocs()
+		stmt, err := db.Prepare(query)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer func() { _ = stmt.Close() }()
+
+		var i int
+		for b.Loop() {
+			var u User
+			if err := stmt.QueryRow(i%userRows).Scan(&u.ID, &u.Name, &u.Email, &u.Score); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+
+	b.Run("Unprepared", func(b *testing.B) {
+		b.ReportAllocs()
+		var i int
+		for b.Loop() {
+			var u User
+			if err := db.QueryRow(query, i%userRows).Scan(&u.ID, &u.Name, &u.Email, &u.Score); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkSQLPoolAcquire measures taking a connection from the database/sql
+// pool and returning it, alone and with goroutines contending for 4 slots.
+func BenchmarkSQLPoolAcquire(b *testing.B) {
+	db := openDB(b)
+	db.SetMaxOpenConns(4)
+	db.SetMaxIdleConns(4)
+	ctx := context.Background()
+
+	b.Run("Serial", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			conn, err := db.Conn(ctx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := conn.Close(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				conn, err := db.Conn(ctx)
+				if err != nil {
+					b.Error(err)
+					return
+				}
+				if err := conn.Close(); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkSQLScan reads 100 rows per query, scanning into typed struct
+// fields versus sql.RawBytes, which skips the copy and conversion.
+func BenchmarkSQLScan(b *testing.B) {
+	const query = `SELECT id, name, email, score FROM users WHERE id < 100`
+	db := openDB(b)
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = stmt.Close() })
+
+	b.Run("Struct", func(b *testing.B) {
+		b.ReportAllocs()
+		users := make([]User, 0, 100)
+		for b.Loop() {
+			rows, err := stmt.Query()
+			if err != nil {
+				b.Fatal(err)
+			}
+			users = users[:0]
+			for rows.Next() {
+				var u User
+				if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Score); err != nil {
+					b.Fatal(err)
+				}
+				users = append(users, u)
+			}
+			if err := rows.Err(); err != nil {
+				b.Fatal(err)
+			}
+			_ = rows.Close()
+		}
+	})
+
+	b.Run("RawBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		var id, name, email, score sql.RawBytes
+		for b.Loop() {
+			rows, err := stmt.Query()
+			if err != nil {
+				b.Fatal(err)
+			}
+			var total int
+			for rows.Next() {
+				if err := rows.Scan(&id, &name, &email, &score); err != nil {
+					b.Fatal(err)
+				}
+				total += len(name) + len(email)
+			}
+			if err := rows.Err(); err != nil {
+				b.Fatal(err)
+			}
+			_ = rows.Close()
+			_ = total
+		}
+	})
+}
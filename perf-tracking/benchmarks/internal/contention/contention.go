@@ -0,0 +1,87 @@
+// Package contention measures the time benchmarks spend contending on locks
+// and blocked on synchronization, from the runtime's mutex and block
+// profiles. It is shared by the runtime and networking benchmarks.
+package contention
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Env turns on mutex and block profiling in benchmarks that use Profile;
+// collect_benchmarks.py --contention-profile sets it and records
+// contention_profile in the run metadata. Profiling every event slows
+// contended paths, so ns/op from such runs is not comparable with normal
+// runs.
+const Env = "BENCH_CONTENTION_PROFILE"
+
+// Profile measures the time goroutines spend contending on
+// sync.Mutex/RWMutex (mutex profile) and blocked in channel operations,
+// select, sync.Cond and WaitGroup (block profile) during a benchmark.
+type Profile struct {
+	enabled      bool
+	prevFraction int
+	mutex, block time.Duration
+}
+
+// Start enables both profiles at full sampling when Env is set and
+// snapshots their totals; otherwise the profile stays off.
+func (p *Profile) Start() {
+	if os.Getenv(Env) == "" {
+		return
+	}
+	p.enabled = true
+	p.prevFraction = runtime.SetMutexProfileFraction(1)
+	runtime.SetBlockProfileRate(1)
+	p.mutex = profileDelay("mutex")
+	p.block = profileDelay("block")
+}
+
+// Report adds mutex-contention-ns/op and block-ns/op and restores the
+// profiling rates. It does nothing unless Start enabled profiling.
+func (p *Profile) Report(b *testing.B) {
+	if !p.enabled {
+		return
+	}
+	mutex := profileDelay("mutex") - p.mutex
+	block := profileDelay("block") - p.block
+	runtime.SetMutexProfileFraction(p.prevFraction)
+	runtime.SetBlockProfileRate(0)
+	b.ReportMetric(float64(mutex)/float64(b.N), "mutex-contention-ns/op")
+	b.ReportMetric(float64(block)/float64(b.N), "block-ns/op")
+}
+
+// profileDelay returns the total delay recorded in a contention profile
+// ("mutex" or "block") since process start. The debug=1 text format lists
+// "<cycles> <count> @ <pcs>" per stack after a cycles/second header.
+func profileDelay(name string) time.Duration {
+	var buf bytes.Buffer
+	if err := pprof.Lookup(name).WriteTo(&buf, 1); err != nil {
+		return 0
+	}
+	var cyclesPerSecond, cycles float64
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if v, ok := strings.CutPrefix(line, "cycles/second="); ok {
+			cyclesPerSecond, _ = strconv.ParseFloat(v, 64)
+			continue
+		}
+		if !strings.Contains(line, " @") {
+			continue
+		}
+		if v, _, ok := strings.Cut(line, " "); ok {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				cycles += n
+			}
+		}
+	}
+	if cyclesPerSecond == 0 {
+		return 0
+	}
+	return time.Duration(cycles / cyclesPerSecond * float64(time.Second))
+}
@@ -0,0 +1,75 @@
+package networking
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reuseControl sets SO_REUSEADDR and SO_REUSEPORT on dialed sockets.
+var reuseControl = func(network, address string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
+		if sockErr == nil {
+			sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// ephemeralPortRange reads net.ipv4.ip_local_port_range.
+func ephemeralPortRange() (lo, hi int, ok bool) {
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_port_range")
+	if err != nil {
+		return 0, 0, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	lo, err1 := strconv.Atoi(fields[0])
+	hi, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || hi < lo {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+// ephemeralPortsInUse counts TCP sockets, including TIME_WAIT, whose local
+// port falls in [lo, hi].
+func ephemeralPortsInUse(lo, hi int) int {
+	n := 0
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		sc.Scan() // header
+		for sc.Scan() {
+			// "  0: 0100007F:A1B2 0100007F:1F90 06 ..."
+			fields := strings.Fields(sc.Text())
+			if len(fields) < 2 {
+				continue
+			}
+			_, portHex, found := strings.Cut(fields[1], ":")
+			if !found {
+				continue
+			}
+			port, err := strconv.ParseUint(portHex, 16, 16)
+			if err == nil && int(port) >= lo && int(port) <= hi {
+				n++
+			}
+		}
+	}
+	return n
+}
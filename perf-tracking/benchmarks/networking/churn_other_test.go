@@ -0,0 +1,18 @@
+//go:build !linux
+
+package networking
+
+import "syscall"
+
+// Socket reuse options are only applied on Linux
+var reuseControl func(network, address string, c syscall.RawConn) error
+
+// Port usage is not observable without /proc; churnDialer falls back to
+// retrying EADDRNOTAVAIL with backoff.
+func ephemeralPortRange() (lo, hi int, ok bool) {
+	return 0, 0, false
+}
+
+func ephemeralPortsInUse(lo, hi int) int {
+	return 0
+}
@@ -0,0 +1,172 @@
+package networking
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const (
+	// Dialing pauses when this fraction of the ephemeral port range is held
+	// and resumes once usage drops below portPressureLow
+	portPressureHigh = 0.8
+	portPressureLow  = 0.5
+
+	// How often port usage is sampled
+	portCheckInterval = 250 * time.Millisecond
+
+	// Give up when ports stay exhausted this long (TIME_WAIT lasts 60s on Linux)
+	portExhaustedTimeout = 90 * time.Second
+)
+
+// churnDialer dials short-lived loopback connections for connection-churn
+// benchmarks without running out of ephemeral ports at high -count.
+//
+// Each connection is closed with SO_LINGER 0 so the client sends RST instead
+// of entering TIME_WAIT. Where port usage is observable (Linux) a background
+// goroutine samples it and dialing is paced when the range nears exhaustion;
+// elsewhere (macOS) EADDRNOTAVAIL is retried with backoff. On Linux sockets
+// also set SO_REUSEADDR/SO_REUSEPORT. Pauses are counted and reported as the
+// port-stalls metric.
+type churnDialer struct {
+	addr   string
+	dialer *net.Dialer
+
+	// timer, when set, is stopped while Dial waits for ports, so waits stay
+	// out of ns/op. Set it only when Dial is called from the benchmark
+	// goroutine: StopTimer is not safe from RunParallel.
+	timer *testing.B
+
+	portLo, portHi int
+	monitored      bool
+	throttled      atomic.Bool
+	stalls         atomic.Int64
+	waited         atomic.Int64 // ns spent waiting for ports with the timer running
+
+	stop, done chan struct{}
+}
+
+// newChurnDialer returns a churnDialer for addr using dialer's settings.
+// Close stops its port usage sampler.
+func newChurnDialer(addr string, dialer *net.Dialer) *churnDialer {
+	if dialer.Control == nil {
+		dialer.Control = reuseControl
+	}
+	d := &churnDialer{addr: addr, dialer: dialer, stop: make(chan struct{}), done: make(chan struct{})}
+	d.portLo, d.portHi, d.monitored = ephemeralPortRange()
+	if !d.monitored {
+		close(d.done)
+		return d
+	}
+	d.throttled.Store(d.portUsage() > portPressureHigh)
+	go d.sample()
+	return d
+}
+
+// Close stops the port usage sampler; it does not close dialed connections.
+func (d *churnDialer) Close() {
+	close(d.stop)
+	<-d.done
+}
+
+// sample updates throttled every portCheckInterval, or every 50ms while
+// throttled: it is set above the high-water mark and cleared below the low
+// one. Reading /proc/net/tcp is too slow to do per dial.
+func (d *churnDialer) sample() {
+	defer close(d.done)
+	for {
+		interval := portCheckInterval
+		if d.throttled.Load() {
+			interval = 50 * time.Millisecond
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(interval):
+		}
+		usage := d.portUsage()
+		switch {
+		case usage > portPressureHigh:
+			d.throttled.Store(true)
+		case usage < portPressureLow:
+			d.throttled.Store(false)
+		}
+	}
+}
+
+// Dial opens a TCP connection that releases its port on Close.
+// It is safe for concurrent use from RunParallel.
+func (d *churnDialer) Dial() (net.Conn, error) {
+	if err := d.pace(); err != nil {
+		return nil, err
+	}
+
+	backoff := time.Millisecond
+	deadline := time.Now().Add(portExhaustedTimeout)
+	for {
+		conn, err := d.dialer.Dial("tcp", d.addr)
+		if err == nil {
+			// RST on close avoids TIME_WAIT buildup on the client side
+			if err := conn.(*net.TCPConn).SetLinger(0); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return conn, nil
+		}
+		if !errors.Is(err, syscall.EADDRNOTAVAIL) || time.Now().After(deadline) {
+			return nil, err
+		}
+		d.stalls.Add(1)
+		d.wait(backoff)
+		backoff = min(2*backoff, 100*time.Millisecond)
+	}
+}
+
+// pace blocks while the sampler reports usage above the high-water mark.
+func (d *churnDialer) pace() error {
+	if !d.throttled.Load() {
+		return nil
+	}
+
+	d.stalls.Add(1)
+	deadline := time.Now().Add(portExhaustedTimeout)
+	for d.throttled.Load() {
+		if time.Now().After(deadline) {
+			return errors.New("ephemeral ports exhausted")
+		}
+		d.wait(50 * time.Millisecond)
+	}
+	return nil
+}
+
+// wait sleeps for dur with the timer stopped, or records the wait when
+// there is no timer to stop.
+func (d *churnDialer) wait(dur time.Duration) {
+	if d.timer != nil {
+		d.timer.StopTimer()
+		defer d.timer.StartTimer()
+	} else {
+		defer d.waited.Add(int64(dur))
+	}
+	time.Sleep(dur)
+}
+
+// portUsage returns the fraction of the ephemeral port range in use.
+func (d *churnDialer) portUsage() float64 {
+	return float64(ephemeralPortsInUse(d.portLo, d.portHi)) / float64(d.portHi-d.portLo+1)
+}
+
+// reportStalls adds the port-stalls metric when dialing had to wait for
+// ports, and port-wait-ns/op for the part of ns/op spent waiting when there
+// was no timer to stop.
+func (d *churnDialer) reportStalls(b *testing.B) {
+	if n := d.stalls.Load(); n > 0 {
+		b.ReportMetric(float64(n), "port-stalls")
+	}
+	if ns := d.waited.Load(); ns > 0 {
+		b.ReportMetric(float64(ns)/float64(b.N), "port-wait-ns/op")
+	}
+}
@@ -0,0 +1,106 @@
+package networking
+
+import (
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// goroutineSettleTimeout bounds how long a sub-benchmark waits for server
+// handlers and connection goroutines to exit before counting them as leaked.
+const goroutineSettleTimeout = 2 * time.Second
+
+// benchServer owns a listener, its accept loop and the per-connection handler
+// goroutines, so benchmarks can shut a server down without leaving goroutines
+// behind to skew later measurements.
+type benchServer struct {
+	ln     net.Listener
+	handle func(net.Conn)
+
+	wg    sync.WaitGroup // accept loop and running handlers
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// serve starts accepting on ln. Each connection is passed to handle in its
+// own goroutine and closed when handle returns; a nil handle closes accepted
+// connections immediately in the accept loop. The server is closed when b
+// finishes.
+func serve(b *testing.B, ln net.Listener, handle func(net.Conn)) *benchServer {
+	b.Helper()
+	s := &benchServer{ln: ln, handle: handle, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	b.Cleanup(s.Close)
+	return s
+}
+
+func (s *benchServer) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		if s.handle == nil {
+			conn.Close()
+			continue
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+			}()
+			s.handle(conn)
+		}()
+	}
+}
+
+// Addr returns the listener address for dialing.
+func (s *benchServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops accepting, closes connections still being handled and waits
+// for the accept loop and all handlers to return. It is safe to call twice.
+func (s *benchServer) Close() {
+	s.ln.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// trackGoroutines records the goroutine count at the start of a sub-benchmark.
+// The returned function, deferred by the caller, waits for the count to return
+// to that baseline and reports the excess as the leaked-goroutines metric when
+// it does not. Connections the sub-benchmark opened must be closed before it
+// runs, so defer it first.
+func trackGoroutines(b *testing.B) func() {
+	baseline := runtime.NumGoroutine()
+	return func() {
+		b.StopTimer()
+		deadline := time.Now().Add(goroutineSettleTimeout)
+		n := runtime.NumGoroutine()
+		for n > baseline && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+			n = runtime.NumGoroutine()
+		}
+		if leaked := n - baseline; leaked > 0 {
+			b.Logf("%d goroutine(s) still running after %v", leaked, goroutineSettleTimeout)
+			b.ReportMetric(float64(leaked), "leaked-goroutines")
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build go1.26
+
+package networking
+
+import "net/http"
+
+func init() {
+	strictMaxConcurrentRequests = func(c *http.HTTP2Config) {
+		c.StrictMaxConcurrentRequests = true
+	}
+}
@@ -2,14 +2,22 @@ package networking
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/astavonin/go-optimization-guide/benchmarks/internal/contention"
 )
 
 // BenchmarkHTTPRequest measures HTTP request/response cycle time.
-// Provides baseline for HTTP/1.1 performance.
+// Provides baseline for HTTP/1.1 performance. Reports p50/p99/p999 request
+// latency alongside the mean, and the per-layer split from httpPhases.
 func BenchmarkHTTPRequest(b *testing.B) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -19,12 +27,16 @@ func BenchmarkHTTPRequest(b *testing.B) {
 		}
 	})
 
-	server := httptest.NewServer(handler)
+	var phases httpPhases
+	server := httptest.NewServer(phases.middleware(handler))
 	defer server.Close()
 
 	client := server.Client()
 
 	b.Run("GET_Small", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		defer client.CloseIdleConnections()
+
 		// Warm-up requests to establish connection pool
 		for i := 0; i < 3; i++ {
 			resp, err := client.Get(server.URL)
@@ -35,21 +47,32 @@ func BenchmarkHTTPRequest(b *testing.B) {
 			resp.Body.Close()
 		}
 
-		b.ResetTimer()
-		for b.Loop() {
-			resp, err := client.Get(server.URL)
+		get := func() error {
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
 			if err != nil {
-				b.Fatal(err)
+				return err
 			}
-			_, err = io.Copy(io.Discard, resp.Body)
-			if err != nil {
+			_, err = phases.do(client, req)
+			return err
+		}
+
+		var latencies latencyHistogram
+		b.ResetTimer()
+		for b.Loop() {
+			start := time.Now()
+			if err := get(); err != nil {
 				b.Fatal(err)
 			}
-			resp.Body.Close()
+			latencies.record(time.Since(start))
 		}
+		latencies.report(b)
+		phases.measure(b, 1, get)
 	})
 
 	b.Run("POST_1KB", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		defer client.CloseIdleConnections()
+
 		data := bytes.NewReader(make([]byte, 1024))
 		// Warm-up requests to establish connection pool
 		for i := 0; i < 3; i++ {
@@ -68,27 +91,38 @@ func BenchmarkHTTPRequest(b *testing.B) {
 			resp.Body.Close()
 		}
 
-		b.ResetTimer()
-		for b.Loop() {
-			_, err := data.Seek(0, io.SeekStart)
-			if err != nil {
-				b.Fatal(err)
+		post := func() error {
+			if _, err := data.Seek(0, io.SeekStart); err != nil {
+				return err
 			}
-			resp, err := client.Post(server.URL, "application/octet-stream", data)
+			req, err := http.NewRequest(http.MethodPost, server.URL, data)
 			if err != nil {
-				b.Fatal(err)
+				return err
 			}
-			_, err = io.Copy(io.Discard, resp.Body)
-			if err != nil {
+			req.Header.Set("Content-Type", "application/octet-stream")
+			_, err = phases.do(client, req)
+			return err
+		}
+
+		var latencies latencyHistogram
+		b.ResetTimer()
+		for b.Loop() {
+			start := time.Now()
+			if err := post(); err != nil {
 				b.Fatal(err)
 			}
-			resp.Body.Close()
+			latencies.record(time.Since(start))
 		}
+		latencies.report(b)
+		phases.measure(b, 1, post)
 	})
 }
 
 // BenchmarkHTTP2 measures HTTP/2 multiplexing and stream performance.
 // Go 1.24: New HTTP2Config API; Go 1.26: StrictMaxConcurrentRequests option.
+// Reports the per-layer split from httpPhases. Streams_N sets the server's
+// MaxConcurrentStreams through HTTP2Config and runs N streams over one
+// connection (strictly limited on Go 1.26+).
 func BenchmarkHTTP2(b *testing.B) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -99,7 +133,8 @@ func BenchmarkHTTP2(b *testing.B) {
 	})
 
 	// Create TLS server with HTTP/2 enabled
-	server := httptest.NewUnstartedServer(handler)
+	var phases httpPhases
+	server := httptest.NewUnstartedServer(phases.middleware(handler))
 	server.EnableHTTP2 = true
 	server.StartTLS()
 	defer server.Close()
@@ -110,7 +145,26 @@ func BenchmarkHTTP2(b *testing.B) {
 		transport.MaxConnsPerHost = 1 // Force connection reuse/multiplexing
 	}
 
+	// get sends one request and verifies HTTP/2 was actually negotiated
+	get := func() error {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := phases.do(client, req)
+		if err != nil {
+			return err
+		}
+		if resp.ProtoMajor != 2 {
+			return fmt.Errorf("expected HTTP/2, got HTTP/%d.%d", resp.ProtoMajor, resp.ProtoMinor)
+		}
+		return nil
+	}
+
 	b.Run("Sequential", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		defer client.CloseIdleConnections()
+
 		// Warm-up requests to establish HTTP/2 connection
 		for i := 0; i < 3; i++ {
 			resp, err := client.Get(server.URL)
@@ -123,23 +177,17 @@ func BenchmarkHTTP2(b *testing.B) {
 
 		b.ResetTimer()
 		for b.Loop() {
-			resp, err := client.Get(server.URL)
-			if err != nil {
-				b.Fatal(err)
-			}
-			// Verify HTTP/2 was actually negotiated
-			if resp.ProtoMajor != 2 {
-				b.Fatalf("expected HTTP/2, got HTTP/%d.%d", resp.ProtoMajor, resp.ProtoMinor)
-			}
-			_, err = io.Copy(io.Discard, resp.Body)
-			if err != nil {
+			if err := get(); err != nil {
 				b.Fatal(err)
 			}
-			resp.Body.Close()
 		}
+		phases.measure(b, 1, get)
 	})
 
 	b.Run("Parallel_10", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		defer client.CloseIdleConnections()
+
 		// Warm-up requests to establish HTTP/2 connection
 		for i := 0; i < 3; i++ {
 			resp, err := client.Get(server.URL)
@@ -151,26 +199,25 @@ func BenchmarkHTTP2(b *testing.B) {
 		}
 
 		b.SetParallelism(10)
+		var prof contention.Profile
+		prof.Start()
+		sched := startSchedSampler()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
-				resp, err := client.Get(server.URL)
-				if err != nil {
+				if err := get(); err != nil {
 					b.Fatal(err)
 				}
-				// Verify HTTP/2
-				if resp.ProtoMajor != 2 {
-					b.Fatalf("expected HTTP/2, got HTTP/%d.%d", resp.ProtoMajor, resp.ProtoMinor)
-				}
-				_, err = io.Copy(io.Discard, resp.Body)
-				if err != nil {
-					b.Fatal(err)
-				}
-				resp.Body.Close()
 			}
 		})
+		sched.report(b)
+		prof.Report(b)
+		phases.measure(b, 10*runtime.GOMAXPROCS(0), get)
 	})
 
 	b.Run("Parallel_30", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		defer client.CloseIdleConnections()
+
 		// Warm-up requests to establish HTTP/2 connection
 		for i := 0; i < 3; i++ {
 			resp, err := client.Get(server.URL)
@@ -182,22 +229,104 @@ func BenchmarkHTTP2(b *testing.B) {
 		}
 
 		b.SetParallelism(30)
+		var prof contention.Profile
+		prof.Start()
+		sched := startSchedSampler()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
-				resp, err := client.Get(server.URL)
-				if err != nil {
-					b.Fatal(err)
-				}
-				// Verify HTTP/2
-				if resp.ProtoMajor != 2 {
-					b.Fatalf("expected HTTP/2, got HTTP/%d.%d", resp.ProtoMajor, resp.ProtoMinor)
-				}
-				_, err = io.Copy(io.Discard, resp.Body)
-				if err != nil {
+				if err := get(); err != nil {
 					b.Fatal(err)
 				}
-				resp.Body.Close()
 			}
 		})
+		sched.report(b)
+		prof.Report(b)
+		phases.measure(b, 30*runtime.GOMAXPROCS(0), get)
 	})
+
+	// One connection limited to n concurrent streams, driven by n requesters
+	for _, streams := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("Streams_%d", streams), func(b *testing.B) {
+			defer trackGoroutines(b)()
+			benchmarkHTTP2Streams(b, handler, streams)
+		})
+	}
+}
+
+// strictMaxConcurrentRequests makes the client queue requests at the
+// server's stream limit instead of dialing more connections. Set on Go 1.26+,
+// where HTTP2Config.StrictMaxConcurrentRequests exists.
+var strictMaxConcurrentRequests func(*http.HTTP2Config)
+
+// benchmarkHTTP2Streams runs streams concurrent requesters against a server
+// whose HTTP2Config allows exactly that many concurrent streams, over a
+// single client connection. Reports per-stream p50/p99/p999 latency; new
+// connections after warm-up are reported as extra-conns.
+func benchmarkHTTP2Streams(b *testing.B, handler http.Handler, streams int) {
+	var phases httpPhases
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ConnState = phases.connState
+	server.EnableHTTP2 = true
+	server.Config.HTTP2 = &http.HTTP2Config{MaxConcurrentStreams: streams}
+	server.StartTLS()
+	defer server.Close()
+
+	client := server.Client()
+	transport := client.Transport.(*http.Transport)
+	transport.MaxConnsPerHost = 1
+	transport.HTTP2 = &http.HTTP2Config{}
+	if strictMaxConcurrentRequests != nil {
+		strictMaxConcurrentRequests(transport.HTTP2)
+	}
+	defer client.CloseIdleConnections()
+
+	// Warm-up request to establish the HTTP/2 connection
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	resp, err := phases.do(client, req)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if resp.ProtoMajor != 2 {
+		b.Fatalf("expected HTTP/2, got HTTP/%d.%d", resp.ProtoMajor, resp.ProtoMinor)
+	}
+
+	var (
+		latencies parallelLatencies
+		next      atomic.Int64
+		wg        sync.WaitGroup
+	)
+	phases.reset()
+	b.ResetTimer()
+	for range streams {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local latencyHistogram
+			defer latencies.add(&local)
+			for next.Add(1) <= int64(b.N) {
+				start := time.Now()
+				req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+				if err != nil {
+					b.Error(err)
+					return
+				}
+				if _, err := phases.do(client, req); err != nil {
+					b.Error(err)
+					return
+				}
+				local.record(time.Since(start))
+			}
+		}()
+	}
+	wg.Wait()
+	b.StopTimer()
+
+	latencies.report(b)
+	if n := phases.accepted.Load(); n > 0 {
+		b.Logf("client opened %d connection(s) beyond the first", n)
+		b.ReportMetric(float64(n), "extra-conns")
+	}
 }
@@ -0,0 +1,192 @@
+package networking
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// phaseSamples is how many traced requests measure sends after the timed
+// loop to split request time into layers
+const phaseSamples = 200
+
+// httpPhases splits HTTP request time into layers so a regression can be
+// attributed to the right one: dialing, the TLS handshake, the server
+// handler, and transfer (both HTTP stacks moving the request and response
+// over loopback). Client-side phases come from httptrace, server processing
+// from a handler middleware. Tracing adds a ClientTrace, its context and
+// timestamps to every request, so it only runs in measure, after the timed
+// loop, and never shows in ns/op or allocs/op. Totals are summed
+// atomically, so one httpPhases can be shared by concurrent requests and
+// the server's handlers.
+type httpPhases struct {
+	tracing atomic.Bool
+
+	ops       atomic.Int64
+	connect   atomic.Int64 // ns in ConnectStart..ConnectDone
+	tls       atomic.Int64 // ns in TLSHandshakeStart..TLSHandshakeDone
+	server    atomic.Int64 // ns inside the handler
+	handled   atomic.Int64 // traced requests whose handler time is in server
+	afterConn atomic.Int64 // ns from GotConn until the body was read
+
+	// Connections the server accepted, counted by connState without
+	// touching the client, so it is accurate for timed loops too
+	accepted atomic.Int64
+}
+
+// middleware times the wrapped handler while measure traces requests.
+// Untraced requests pass through after one atomic load.
+func (p *httpPhases) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.tracing.Load() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		p.server.Add(int64(time.Since(start)))
+		p.handled.Add(1)
+	})
+}
+
+// connState counts the connections a server accepts; install it as the
+// server's http.Server.ConnState before starting it.
+func (p *httpPhases) connState(_ net.Conn, state http.ConnState) {
+	if state == http.StateNew {
+		p.accepted.Add(1)
+	}
+}
+
+// do sends req with client and drains and closes the response body. While
+// measure runs it also traces the request and records its phases. The
+// response is returned for status checks.
+func (p *httpPhases) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if !p.tracing.Load() {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp, err
+	}
+
+	var connectStart, tlsStart, gotConn time.Time
+	trace := &httptrace.ClientTrace{
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(string, string, error) {
+			p.connect.Add(int64(time.Since(connectStart)))
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			p.tls.Add(int64(time.Since(tlsStart)))
+		},
+		GotConn: func(httptrace.GotConnInfo) { gotConn = time.Now() },
+	}
+
+	resp, err := client.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	p.afterConn.Add(int64(time.Since(gotConn)))
+	p.ops.Add(1)
+	return resp, nil
+}
+
+// reset clears totals, e.g. after warm-up requests.
+func (p *httpPhases) reset() {
+	p.resetPhases()
+	p.accepted.Store(0)
+}
+
+func (p *httpPhases) resetPhases() {
+	p.ops.Store(0)
+	p.connect.Store(0)
+	p.tls.Store(0)
+	p.server.Store(0)
+	p.handled.Store(0)
+	p.afterConn.Store(0)
+}
+
+// measure stops the timer and sends phaseSamples traced requests, each one
+// call of request, from workers goroutines, then reports their split. Pass
+// the request the timed loop sends, and as many workers as it runs
+// concurrently: the phases are per-request latencies, so in parallel
+// variants they add up to more than ns/op. Report reuse and check accepted
+// before measure, which may open connections of its own.
+func (p *httpPhases) measure(b *testing.B, workers int, request func() error) {
+	b.StopTimer()
+	p.resetPhases()
+	p.tracing.Store(true)
+	defer p.tracing.Store(false)
+
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	total := int64(max(phaseSamples, workers))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next.Add(1) <= total {
+				if err := request(); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if b.Failed() {
+		return
+	}
+	p.awaitHandlers()
+	p.report(b)
+}
+
+// awaitHandlers waits until the middleware has recorded the server time of
+// every traced request. A client can read a response before its handler
+// returns and adds its time, e.g. once the body was flushed.
+func (p *httpPhases) awaitHandlers() {
+	deadline := time.Now().Add(time.Second)
+	for p.handled.Load() < p.ops.Load() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Microsecond)
+	}
+}
+
+// report adds connect-ns/op, tls-ns/op, server-ns/op and transfer-ns/op.
+// Transfer is the time after obtaining a connection that was not spent in
+// the handler.
+func (p *httpPhases) report(b *testing.B) {
+	ops := float64(p.ops.Load())
+	if ops == 0 {
+		return
+	}
+	server := float64(p.server.Load())
+	b.ReportMetric(float64(p.connect.Load())/ops, "connect-ns/op")
+	b.ReportMetric(float64(p.tls.Load())/ops, "tls-ns/op")
+	b.ReportMetric(server/ops, "server-ns/op")
+	b.ReportMetric(max(float64(p.afterConn.Load())-server, 0)/ops, "transfer-ns/op")
+}
+
+// reportReuse adds the reuse-% metric: the share of requests, of the
+// requests sent since reset, served on a connection the server had
+// already accepted.
+func (p *httpPhases) reportReuse(b *testing.B, requests int) {
+	if requests > 0 {
+		fresh := min(p.accepted.Load(), int64(requests))
+		b.ReportMetric(100*float64(int64(requests)-fresh)/float64(requests), "reuse-%")
+	}
+}
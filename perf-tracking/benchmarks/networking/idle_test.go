@@ -0,0 +1,141 @@
+package networking
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime/metrics"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Idle timeout for the Scavenged variants; short so each iteration only
+// sleeps briefly while the pool expires
+const scavengeIdleTimeout = 20 * time.Millisecond
+
+// mutexWaitMetric is the cumulative time goroutines spent blocked on
+// sync.Mutex and sync.RWMutex, process-wide.
+const mutexWaitMetric = "/sync/mutex/wait/total:seconds"
+
+// mutexWait returns the current value of mutexWaitMetric.
+func mutexWait() time.Duration {
+	sample := []metrics.Sample{{Name: mutexWaitMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return time.Duration(sample[0].Value.Float64() * float64(time.Second))
+}
+
+// BenchmarkIdleConnScavenge measures how idle keep-alive connection expiry
+// affects the requests that follow it. Each op is a burst of N concurrent
+// requests through a transport that keeps N idle connections per host.
+//
+// Warm serves every burst from the idle pool. Scavenged sleeps for
+// IdleConnTimeout between bursts, so the next burst starts while the
+// transport's idle timers fire and close all N connections: requests redial
+// and contend with the closers for the transport's idle-pool lock. Reports
+// per-request p50/p99/p999, reuse-% and mutex-wait-ns/op, the process-wide
+// sync.Mutex wait during the timed bursts, which covers the transport locks
+// but also the server's.
+func BenchmarkIdleConnScavenge(b *testing.B) {
+	for _, conns := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("Warm/Conns_%d", conns), func(b *testing.B) {
+			benchmarkIdleConns(b, conns, 90*time.Second, false)
+		})
+		b.Run(fmt.Sprintf("Scavenged/Conns_%d", conns), func(b *testing.B) {
+			benchmarkIdleConns(b, conns, scavengeIdleTimeout, true)
+		})
+	}
+}
+
+func benchmarkIdleConns(b *testing.B, conns int, idleTimeout time.Duration, expire bool) {
+	defer trackGoroutines(b)()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	var phases httpPhases
+	server := httptest.NewUnstartedServer(phases.middleware(handler))
+	server.Config.ConnState = phases.connState
+	server.Start()
+	defer server.Close()
+
+	// Scavenged redials every connection each op; churnDialer keeps the
+	// closed client sockets out of TIME_WAIT
+	dialer := newChurnDialer(server.Listener.Addr().String(), &net.Dialer{Timeout: 5 * time.Second})
+	defer dialer.Close()
+	transport := &http.Transport{
+		DialContext: func(context.Context, string, string) (net.Conn, error) {
+			return dialer.Dial()
+		},
+		MaxIdleConns:        conns,
+		MaxIdleConnsPerHost: conns,
+		IdleConnTimeout:     idleTimeout,
+	}
+	client := &http.Client{Transport: transport}
+	defer transport.CloseIdleConnections()
+
+	latencies := new(parallelLatencies)
+	burst := func() error {
+		var (
+			wg       sync.WaitGroup
+			errOnce  sync.Once
+			firstErr error
+		)
+		start := make(chan struct{})
+		for range conns {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var h latencyHistogram
+				defer latencies.add(&h)
+				<-start
+				req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+				if err == nil {
+					t := time.Now()
+					_, err = phases.do(client, req)
+					h.record(time.Since(t))
+				}
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		return firstErr
+	}
+
+	// Fill the idle pool with conns connections
+	if err := burst(); err != nil {
+		b.Fatal(err)
+	}
+	latencies = new(parallelLatencies)
+	phases.reset()
+
+	var waited time.Duration
+	b.ResetTimer()
+	for b.Loop() {
+		if expire {
+			b.StopTimer()
+			time.Sleep(idleTimeout)
+			b.StartTimer()
+		}
+		before := mutexWait()
+		if err := burst(); err != nil {
+			b.Fatal(err)
+		}
+		waited += mutexWait() - before
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(waited)/float64(b.N), "mutex-wait-ns/op")
+	latencies.report(b)
+	phases.reportReuse(b, b.N*conns)
+	dialer.reportStalls(b)
+}
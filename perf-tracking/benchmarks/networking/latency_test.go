@@ -0,0 +1,126 @@
+package networking
+
+import (
+	"math/bits"
+	"sync"
+	"testing"
+	"time"
+)
+
+// HDR-style bucketing: values below 2^latencySubBits ns are counted exactly,
+// larger values in 2^latencySubBits linear sub-buckets per power of two, so
+// every recorded latency is within 1/128 (<0.8%) of its bucket.
+const (
+	latencySubBits    = 7
+	latencySubBuckets = 1 << latencySubBits
+	latencyBuckets    = (64 - latencySubBits + 1) * latencySubBuckets
+)
+
+// latencyHistogram records per-operation latencies so benchmarks can report
+// tail percentiles that the mean ns/op hides. It is not safe for concurrent
+// use; parallel benchmarks record into one histogram per goroutine and merge.
+type latencyHistogram struct {
+	counts [latencyBuckets]uint64
+	total  uint64
+}
+
+func latencyBucket(ns uint64) int {
+	if ns < latencySubBuckets {
+		return int(ns)
+	}
+	shift := bits.Len64(ns) - latencySubBits - 1
+	return (shift+1)*latencySubBuckets + int(ns>>shift) - latencySubBuckets
+}
+
+// latencyBucketValue returns the midpoint of bucket i in ns.
+func latencyBucketValue(i int) float64 {
+	if i < latencySubBuckets {
+		return float64(i)
+	}
+	shift := i/latencySubBuckets - 1
+	lo := uint64(latencySubBuckets+i%latencySubBuckets) << shift
+	return float64(lo) + float64(uint64(1)<<shift)/2
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	h.counts[latencyBucket(uint64(max(d, 0)))]++
+	h.total++
+}
+
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	h.total += other.total
+}
+
+// quantile returns the latency in ns at quantile q (0..1).
+func (h *latencyHistogram) quantile(q float64) float64 {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(q*float64(h.total-1)) + 1
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			return latencyBucketValue(i)
+		}
+	}
+	return latencyBucketValue(latencyBuckets - 1)
+}
+
+// report adds the p50-ns, p99-ns and p999-ns metrics.
+func (h *latencyHistogram) report(b *testing.B) {
+	b.ReportMetric(h.quantile(0.50), "p50-ns")
+	b.ReportMetric(h.quantile(0.99), "p99-ns")
+	b.ReportMetric(h.quantile(0.999), "p999-ns")
+}
+
+// parallelLatencies collects per-goroutine histograms from RunParallel.
+type parallelLatencies struct {
+	mu  sync.Mutex
+	all latencyHistogram
+}
+
+// add merges one goroutine's histogram; call it once the goroutine is done.
+func (p *parallelLatencies) add(h *latencyHistogram) {
+	p.mu.Lock()
+	p.all.merge(h)
+	p.mu.Unlock()
+}
+
+func (p *parallelLatencies) report(b *testing.B) {
+	p.all.report(b)
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Microsecond)
+	}
+
+	for _, tc := range []struct {
+		q    float64
+		want float64
+	}{
+		{0.50, 500e3},
+		{0.99, 990e3},
+		{0.999, 999e3},
+	} {
+		got := h.quantile(tc.q)
+		if rel := (got - tc.want) / tc.want; rel < -0.01 || rel > 0.01 {
+			t.Errorf("quantile(%v) = %.0f, want %.0f ±1%%", tc.q, got, tc.want)
+		}
+	}
+
+	for _, ns := range []uint64{0, 1, 127, 128, 129, 255, 256, 1 << 20, 1<<40 + 12345} {
+		v := latencyBucketValue(latencyBucket(ns))
+		if ns >= latencySubBuckets && (v < float64(ns)*0.99 || v > float64(ns)*1.01) {
+			t.Errorf("bucket value for %d = %.0f, outside 1%%", ns, v)
+		}
+		if ns < latencySubBuckets && v != float64(ns) {
+			t.Errorf("bucket value for %d = %.0f, want exact", ns, v)
+		}
+	}
+}
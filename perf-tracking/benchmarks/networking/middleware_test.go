@@ -0,0 +1,181 @@
+package networking
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Response body for middleware benchmarks, large enough for gzip to matter
+var middlewareBody = []byte(strings.Repeat(`{"id":1,"name":"item","tags":["a","b","c"]},`, 20))
+
+type requestIDKey struct{}
+
+var (
+	middlewareLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	requestIDs       atomic.Uint64
+	gzipWriters      = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+)
+
+// statusWriter records the status code for access logging.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// gzipWriter compresses the body written through it.
+type gzipWriter struct {
+	http.ResponseWriter
+	zw *gzip.Writer
+}
+
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	return w.zw.Write(p)
+}
+
+func acceptsGzip(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+}
+
+func withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		middlewareLogger.Info("request", "method", r.Method, "path", r.URL.Path,
+			"status", sw.status, "duration", time.Since(start))
+	})
+}
+
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strconv.FormatUint(requestIDs.Add(1), 16)
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+func withGzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		zw := gzipWriters.Get().(*gzip.Writer)
+		zw.Reset(w)
+		defer gzipWriters.Put(zw)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		next.ServeHTTP(&gzipWriter{ResponseWriter: w, zw: zw}, r)
+		zw.Close()
+	})
+}
+
+func middlewareEndpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Context().Value(requestIDKey{}) == nil {
+		panic("missing request ID")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(middlewareBody)
+}
+
+// flattenedHandler does the work of the nested stack in one handler: one
+// deferred recover, one wrapper around the ResponseWriter, one context.
+func flattenedHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		if err := recover(); err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			sw.status = http.StatusInternalServerError
+		}
+		middlewareLogger.Info("request", "method", r.Method, "path", r.URL.Path,
+			"status", sw.status, "duration", time.Since(start))
+	}()
+
+	id := strconv.FormatUint(requestIDs.Add(1), 16)
+	w.Header().Set("X-Request-Id", id)
+	r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+
+	var out http.ResponseWriter = sw
+	if acceptsGzip(r) {
+		zw := gzipWriters.Get().(*gzip.Writer)
+		zw.Reset(sw)
+		defer gzipWriters.Put(zw)
+		defer zw.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		out = &gzipWriter{ResponseWriter: sw, zw: zw}
+	}
+	middlewareEndpoint(out, r)
+}
+
+// BenchmarkHTTPMiddleware measures the per-request cost of a typical
+// middleware stack (logging, recovery, request ID, gzip) composed from
+// nested handlers versus the same work in one flattened handler. Handlers
+// are called in-process with ServeHTTP so network time does not hide the
+// wrapper overhead; Bare is the endpoint alone. NoGzip variants isolate
+// the wrapping from compression cost.
+func BenchmarkHTTPMiddleware(b *testing.B) {
+	nested := withLogging(withRecovery(withRequestID(withGzip(http.HandlerFunc(middlewareEndpoint)))))
+
+	bare := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(middlewareBody)
+	})
+
+	cases := []struct {
+		name    string
+		handler http.Handler
+		gzip    bool
+	}{
+		{"Bare", bare, false},
+		{"Nested/NoGzip", nested, false},
+		{"Flattened/NoGzip", http.HandlerFunc(flattenedHandler), false},
+		{"Nested/Gzip", nested, true},
+		{"Flattened/Gzip", http.HandlerFunc(flattenedHandler), true},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tc.gzip {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				rec := httptest.NewRecorder()
+				tc.handler.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					b.Fatalf("unexpected status %d", rec.Code)
+				}
+			}
+		})
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/astavonin/go-optimization-guide/benchmarks/internal/contention"
 )
 
 // BenchmarkConnectionPool measures HTTP client connection pool efficiency.
 // Compares request latency with cold (no pooling) vs warm (with pooling) configurations.
+// The per-layer split from httpPhases shows the dial cost that pooling saves;
+// reuse-% verifies that the warm variants actually reuse pooled connections.
 func BenchmarkConnectionPool(b *testing.B) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -19,32 +24,43 @@ func BenchmarkConnectionPool(b *testing.B) {
 		}
 	})
 
-	server := httptest.NewServer(handler)
+	var phases httpPhases
+	server := httptest.NewUnstartedServer(phases.middleware(handler))
+	server.Config.ConnState = phases.connState
+	server.Start()
 	defer server.Close()
 
 	b.Run("ColdPool", func(b *testing.B) {
-		b.ResetTimer()
-		for b.Loop() {
-			// Create new transport per iteration (no pooling)
+		defer trackGoroutines(b)()
+		get := func() error {
+			// Create new transport per request (no pooling)
 			client := &http.Client{
 				Transport: &http.Transport{
 					DisableKeepAlives: true,
 				},
 			}
-			resp, err := client.Get(server.URL)
+			defer client.CloseIdleConnections()
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
 			if err != nil {
-				b.Fatal(err)
+				return err
 			}
-			_, err = io.Copy(io.Discard, resp.Body)
-			if err != nil {
+			_, err = phases.do(client, req)
+			return err
+		}
+
+		phases.reset()
+		b.ResetTimer()
+		for b.Loop() {
+			if err := get(); err != nil {
 				b.Fatal(err)
 			}
-			resp.Body.Close()
-			client.CloseIdleConnections()
 		}
+		phases.reportReuse(b, b.N)
+		phases.measure(b, 1, get)
 	})
 
 	b.Run("WarmPool", func(b *testing.B) {
+		defer trackGoroutines(b)()
 		transport := &http.Transport{
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 100,
@@ -53,6 +69,15 @@ func BenchmarkConnectionPool(b *testing.B) {
 		client := &http.Client{Transport: transport}
 		defer transport.CloseIdleConnections()
 
+		get := func() error {
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				return err
+			}
+			_, err = phases.do(client, req)
+			return err
+		}
+
 		// Warm up the pool
 		for i := 0; i < 10; i++ {
 			resp, err := client.Get(server.URL)
@@ -66,21 +91,23 @@ func BenchmarkConnectionPool(b *testing.B) {
 			resp.Body.Close()
 		}
 
+		phases.reset()
 		b.ResetTimer()
 		for b.Loop() {
-			resp, err := client.Get(server.URL)
-			if err != nil {
-				b.Fatal(err)
-			}
-			_, err = io.Copy(io.Discard, resp.Body)
-			if err != nil {
+			if err := get(); err != nil {
 				b.Fatal(err)
 			}
-			resp.Body.Close()
 		}
+		phases.reportReuse(b, b.N)
+		// Every request after warm-up must reuse the pooled connection
+		if n := phases.accepted.Load(); n > 0 {
+			b.Fatalf("warm pool opened %d new connection(s)", n)
+		}
+		phases.measure(b, 1, get)
 	})
 
 	b.Run("WarmPool_Parallel", func(b *testing.B) {
+		defer trackGoroutines(b)()
 		transport := &http.Transport{
 			MaxIdleConns:        100,
 			MaxIdleConnsPerHost: 100,
@@ -89,6 +116,15 @@ func BenchmarkConnectionPool(b *testing.B) {
 		client := &http.Client{Transport: transport}
 		defer transport.CloseIdleConnections()
 
+		get := func() error {
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				return err
+			}
+			_, err = phases.do(client, req)
+			return err
+		}
+
 		// Warm up the pool
 		for i := 0; i < 10; i++ {
 			resp, err := client.Get(server.URL)
@@ -102,19 +138,27 @@ func BenchmarkConnectionPool(b *testing.B) {
 			resp.Body.Close()
 		}
 
+		phases.reset()
+		var prof contention.Profile
+		prof.Start()
+		sched := startSchedSampler()
 		b.ResetTimer()
 		b.RunParallel(func(pb *testing.PB) {
 			for pb.Next() {
-				resp, err := client.Get(server.URL)
-				if err != nil {
+				if err := get(); err != nil {
 					b.Fatal(err)
 				}
-				_, err = io.Copy(io.Discard, resp.Body)
-				if err != nil {
-					b.Fatal(err)
-				}
-				resp.Body.Close()
 			}
 		})
+		sched.report(b)
+		prof.Report(b)
+		phases.reportReuse(b, b.N)
+		// Each goroutine may need its own connection once; more than that
+		// means connections are not going back to the pool
+		limit := runtime.GOMAXPROCS(0)
+		if n := phases.accepted.Load(); n > int64(limit) {
+			b.Fatalf("warm pool opened %d new connection(s), want at most %d", n, limit)
+		}
+		phases.measure(b, limit, get)
 	})
 }
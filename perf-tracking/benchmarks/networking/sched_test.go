@@ -0,0 +1,86 @@
+package networking
+
+import (
+	"runtime/metrics"
+	"testing"
+	"time"
+)
+
+// schedSamplePeriod is how often schedSampler reads the scheduler metrics
+const schedSamplePeriod = time.Millisecond
+
+const (
+	goroutinesMetric = "/sched/goroutines:goroutines"
+	// Go 1.26+; older versions report only the goroutine metrics
+	runnableMetric = "/sched/goroutines/runnable:goroutines"
+)
+
+// schedSampler samples the live goroutine count and the number of runnable
+// goroutines waiting for a P while a parallel benchmark runs, to catch
+// versions where the client, server or net/http spawn more (or fewer)
+// goroutines than the workload needs. Its own goroutine is excluded.
+type schedSampler struct {
+	stop, done chan struct{}
+	samples    []metrics.Sample
+
+	n                    uint64
+	goroutines, runnable struct{ sum, max uint64 }
+	runnableSupported    bool
+}
+
+// startSchedSampler starts sampling; call report after the timed loop.
+func startSchedSampler() *schedSampler {
+	s := &schedSampler{
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		samples: []metrics.Sample{{Name: goroutinesMetric}, {Name: runnableMetric}},
+	}
+	s.sample(0)
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(schedSamplePeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.sample(1)
+			}
+		}
+	}()
+	return s
+}
+
+// sample records one reading, not counting the sampler's own goroutines.
+func (s *schedSampler) sample(own uint64) {
+	metrics.Read(s.samples)
+	if s.samples[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	s.n++
+	g := s.samples[0].Value.Uint64() - own
+	s.goroutines.sum += g
+	s.goroutines.max = max(s.goroutines.max, g)
+	if s.samples[1].Value.Kind() == metrics.KindUint64 {
+		s.runnableSupported = true
+		r := s.samples[1].Value.Uint64()
+		s.runnable.sum += r
+		s.runnable.max = max(s.runnable.max, r)
+	}
+}
+
+// report stops the sampler and adds goroutines-max and goroutines-mean,
+// plus runnable-max and runnable-mean where the runtime exports them.
+func (s *schedSampler) report(b *testing.B) {
+	close(s.stop)
+	<-s.done
+	s.sample(0)
+
+	b.ReportMetric(float64(s.goroutines.max), "goroutines-max")
+	b.ReportMetric(float64(s.goroutines.sum)/float64(s.n), "goroutines-mean")
+	if s.runnableSupported {
+		b.ReportMetric(float64(s.runnable.max), "runnable-max")
+		b.ReportMetric(float64(s.runnable.sum)/float64(s.n), "runnable-mean")
+	}
+}
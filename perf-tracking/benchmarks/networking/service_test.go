@@ -0,0 +1,272 @@
+package networking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/astavonin/go-optimization-guide/benchmarks/internal/contention"
+)
+
+// Catalog entries and request shape for the service benchmark, sized like a
+// typical internal API: a handful of fields, a short list, a nested object.
+type serviceItem struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Price     float64           `json:"price"`
+	Tags      []string          `json:"tags"`
+	Attrs     map[string]string `json:"attrs"`
+	InStock   bool              `json:"in_stock"`
+	Updated   time.Time         `json:"updated"`
+	Warehouse struct {
+		Code string `json:"code"`
+		Zone int    `json:"zone"`
+	} `json:"warehouse"`
+}
+
+type serviceRequest struct {
+	IDs      []int  `json:"ids"`
+	Currency string `json:"currency"`
+	ClientID string `json:"client_id"`
+}
+
+type serviceResponse struct {
+	Items    []*serviceItem `json:"items"`
+	Missing  []int          `json:"missing,omitempty"`
+	Currency string         `json:"currency"`
+}
+
+const serviceCatalogSize = 10000
+
+// Concurrent clients per step of the HTTPService load ramp
+var serviceRampClients = []int{1, 4, 16, 64}
+
+func newServiceCatalog() map[int]*serviceItem {
+	catalog := make(map[int]*serviceItem, serviceCatalogSize)
+	updated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range serviceCatalogSize {
+		item := &serviceItem{
+			ID:      i,
+			Name:    fmt.Sprintf("item-%05d", i),
+			Price:   float64(i%1000) + 0.99,
+			Tags:    []string{"catalog", fmt.Sprintf("group-%d", i%50)},
+			Attrs:   map[string]string{"color": "blue", "size": "m"},
+			InStock: i%7 != 0,
+			Updated: updated.Add(time.Duration(i) * time.Minute),
+		}
+		item.Warehouse.Code = fmt.Sprintf("WH%02d", i%20)
+		item.Warehouse.Zone = i % 4
+		catalog[i] = item
+	}
+	return catalog
+}
+
+// serviceHandler decodes a serviceRequest, looks the IDs up in catalog and
+// encodes a serviceResponse.
+func serviceHandler(catalog map[int]*serviceItem) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req serviceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp := serviceResponse{Currency: req.Currency, Items: make([]*serviceItem, 0, len(req.IDs))}
+		for _, id := range req.IDs {
+			if item, ok := catalog[id]; ok {
+				resp.Items = append(resp.Items, item)
+			} else {
+				resp.Missing = append(resp.Missing, id)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// BenchmarkHTTPService is a macro benchmark of a small JSON API: the client
+// encodes a request for 10 catalog items, the handler decodes it, looks the
+// items up in a map and encodes the response, and the client decodes it.
+// It runs end to end over loopback with a pooled client so GC, JSON and
+// net/http costs interact as they do in a service. Reports p50/p99/p999
+// request latency and gc-cycles/op alongside the usual allocation metrics.
+// Ramp/Clients_N steps add req/s for charting saturation per Go version.
+// Parallel and Ramp also sample goroutine and run-queue counts.
+func BenchmarkHTTPService(b *testing.B) {
+	server := httptest.NewServer(serviceHandler(newServiceCatalog()))
+	defer server.Close()
+
+	newClient := func() *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		}}
+	}
+
+	// call performs one request for the IDs starting at first.
+	call := func(client *http.Client, first int) error {
+		req := serviceRequest{Currency: "EUR", ClientID: "bench"}
+		for i := range 10 {
+			req.IDs = append(req.IDs, (first+i*997)%serviceCatalogSize)
+		}
+		body, err := json.Marshal(&req)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Post(server.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		var out serviceResponse
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			return err
+		}
+		io.Copy(io.Discard, resp.Body)
+		if len(out.Items) != len(req.IDs) {
+			return fmt.Errorf("got %d items, want %d", len(out.Items), len(req.IDs))
+		}
+		return nil
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		client := newClient()
+		defer client.CloseIdleConnections()
+		if err := call(client, 0); err != nil {
+			b.Fatal(err)
+		}
+
+		var latencies latencyHistogram
+		var gc gcCycles
+		b.ReportAllocs()
+		gc.start()
+		b.ResetTimer()
+		i := 0
+		for b.Loop() {
+			start := time.Now()
+			if err := call(client, i); err != nil {
+				b.Fatal(err)
+			}
+			latencies.record(time.Since(start))
+			i++
+		}
+		gc.report(b)
+		latencies.report(b)
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		client := newClient()
+		defer client.CloseIdleConnections()
+		if err := call(client, 0); err != nil {
+			b.Fatal(err)
+		}
+
+		var latencies parallelLatencies
+		var gc gcCycles
+		var prof contention.Profile
+		b.ReportAllocs()
+		gc.start()
+		prof.Start()
+		sched := startSchedSampler()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			var local latencyHistogram
+			defer latencies.add(&local)
+			i := 0
+			for pb.Next() {
+				start := time.Now()
+				if err := call(client, i); err != nil {
+					b.Fatal(err)
+				}
+				local.record(time.Since(start))
+				i++
+			}
+		})
+		sched.report(b)
+		prof.Report(b)
+		gc.report(b)
+		latencies.report(b)
+	})
+
+	// Load ramp: each step runs a fixed number of closed-loop clients, so
+	// the steps' req/s and latency metrics form a throughput-vs-latency
+	// curve. Throughput flattens while latency keeps rising once the
+	// service saturates.
+	for _, clients := range serviceRampClients {
+		b.Run(fmt.Sprintf("Ramp/Clients_%d", clients), func(b *testing.B) {
+			defer trackGoroutines(b)()
+			client := newClient()
+			defer client.CloseIdleConnections()
+			if err := call(client, 0); err != nil {
+				b.Fatal(err)
+			}
+
+			var (
+				latencies parallelLatencies
+				next      atomic.Int64
+				wg        sync.WaitGroup
+			)
+			sched := startSchedSampler()
+			b.ResetTimer()
+			for range clients {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					var local latencyHistogram
+					defer latencies.add(&local)
+					for {
+						i := next.Add(1)
+						if i > int64(b.N) {
+							return
+						}
+						start := time.Now()
+						if err := call(client, int(i)); err != nil {
+							b.Error(err)
+							return
+						}
+						local.record(time.Since(start))
+					}
+				}()
+			}
+			wg.Wait()
+			b.StopTimer()
+
+			sched.report(b)
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "req/s")
+			latencies.report(b)
+		})
+	}
+}
+
+// gcCycles counts garbage collections during a benchmark.
+type gcCycles struct {
+	before uint32
+}
+
+func (g *gcCycles) start() {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	g.before = ms.NumGC
+}
+
+// report adds the gc-cycles/op metric.
+func (g *gcCycles) report(b *testing.B) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	b.ReportMetric(float64(ms.NumGC-g.before)/float64(b.N), "gc-cycles/op")
+}
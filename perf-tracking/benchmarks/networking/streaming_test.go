@@ -0,0 +1,157 @@
+package networking
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	// Body size for streaming benchmarks, large enough that transfer dominates
+	// request overhead
+	streamBodySize = 10 << 20
+
+	// Size of each Write in download handlers
+	streamChunkSize = 32 << 10
+)
+
+// zeroReader is an endless source of zero bytes for request bodies.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// BenchmarkHTTPStreaming measures HTTP/1.1 transfer of 10MB bodies using
+// chunked transfer encoding in both directions.
+// Upload compares handler read buffer sizes and the cost of wrapping the body
+// in http.MaxBytesReader; Download compares letting the server buffer chunks
+// against flushing each one with http.ResponseController.
+func BenchmarkHTTPStreaming(b *testing.B) {
+	uploads := []struct {
+		name     string
+		bufSize  int
+		maxBytes bool
+	}{
+		{"Buf4KB", 4 << 10, false},
+		{"Buf256KB", 256 << 10, false},
+		{"MaxBytesReader", 256 << 10, true},
+	}
+
+	for _, u := range uploads {
+		b.Run(fmt.Sprintf("Upload/%s", u.name), func(b *testing.B) {
+			defer trackGoroutines(b)()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body := r.Body
+				if u.maxBytes {
+					body = http.MaxBytesReader(w, r.Body, streamBodySize)
+				}
+				// Read directly rather than io.Copy to io.Discard, which would
+				// pick its own buffer size via ReadFrom
+				buf := make([]byte, u.bufSize)
+				var total int64
+				for {
+					n, err := body.Read(buf)
+					total += int64(n)
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+						return
+					}
+				}
+				if total != streamBodySize {
+					http.Error(w, fmt.Sprintf("read %d bytes", total), http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			client := server.Client()
+			defer client.CloseIdleConnections()
+
+			b.SetBytes(streamBodySize)
+			b.ResetTimer()
+			for b.Loop() {
+				req, err := http.NewRequest(http.MethodPost, server.URL,
+					io.LimitReader(zeroReader{}, streamBodySize))
+				if err != nil {
+					b.Fatal(err)
+				}
+				// Unknown length forces chunked transfer encoding
+				req.ContentLength = -1
+				req.Header.Set("Content-Type", "application/octet-stream")
+				resp, err := client.Do(req)
+				if err != nil {
+					b.Fatal(err)
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusNoContent {
+					b.Fatalf("unexpected status %d", resp.StatusCode)
+				}
+			}
+		})
+	}
+
+	downloads := []struct {
+		name  string
+		flush bool
+	}{
+		{"Chunked", false},
+		{"Flush", true},
+	}
+
+	for _, d := range downloads {
+		b.Run(fmt.Sprintf("Download/%s", d.name), func(b *testing.B) {
+			defer trackGoroutines(b)()
+
+			chunk := make([]byte, streamChunkSize)
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// No Content-Length, so the response is sent chunked
+				w.Header().Set("Content-Type", "application/octet-stream")
+				rc := http.NewResponseController(w)
+				for sent := 0; sent < streamBodySize; sent += len(chunk) {
+					if _, err := w.Write(chunk); err != nil {
+						return
+					}
+					if d.flush {
+						if err := rc.Flush(); err != nil {
+							return
+						}
+					}
+				}
+			})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			client := server.Client()
+			defer client.CloseIdleConnections()
+
+			b.SetBytes(streamBodySize)
+			b.ResetTimer()
+			for b.Loop() {
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					b.Fatal(err)
+				}
+				n, err := io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if n != streamBodySize {
+					b.Fatalf("received %d bytes, want %d", n, streamBodySize)
+				}
+				if len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+					b.Fatalf("response not chunked: %v", resp.TransferEncoding)
+				}
+			}
+		})
+	}
+}
@@ -4,55 +4,78 @@ import (
 	"io"
 	"net"
 	"testing"
+	"time"
+
+	"github.com/astavonin/go-optimization-guide/benchmarks/internal/contention"
 )
 
+// echo writes back everything read from c until the peer closes it
+func echo(c net.Conn) {
+	io.Copy(c, c)
+}
+
 // BenchmarkTCPConnect measures TCP connection establishment time to localhost.
-// Provides baseline for connection handling performance.
+// Provides baseline for connection handling performance. Reports p50/p99/p999
+// dial latency alongside the mean.
 func BenchmarkTCPConnect(b *testing.B) {
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		b.Fatal(err)
 	}
-	defer ln.Close()
-
-	// Accept loop to drain connections
-	go func() {
-		for {
-			conn, err := ln.Accept()
-			if err != nil {
-				return
-			}
-			conn.Close()
-		}
-	}()
-
-	addr := ln.Addr().String()
+	// Accepted connections are closed immediately
+	addr := serve(b, ln, nil).Addr()
 
 	b.Run("Sequential", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		dialer := newChurnDialer(addr, &net.Dialer{})
+		defer dialer.Close()
+		dialer.timer = b
+		var latencies latencyHistogram
 		b.ResetTimer()
 		for b.Loop() {
-			conn, err := net.Dial("tcp", addr)
+			stalls, start := dialer.stalls.Load(), time.Now()
+			conn, err := dialer.Dial()
 			if err != nil {
 				b.Fatal(err)
 			}
-			// SetLinger(0) sends RST on close, avoiding TIME_WAIT buildup
-			// that exhausts ephemeral ports on macOS during high-iteration runs.
-			conn.(*net.TCPConn).SetLinger(0)
+			// Dials that waited for ports would skew the tail
+			if dialer.stalls.Load() == stalls {
+				latencies.record(time.Since(start))
+			}
 			conn.Close()
 		}
+		latencies.report(b)
+		dialer.reportStalls(b)
 	})
 
 	b.Run("Parallel", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		dialer := newChurnDialer(addr, &net.Dialer{})
+		defer dialer.Close()
+		var latencies parallelLatencies
+		var prof contention.Profile
+		prof.Start()
+		sched := startSchedSampler()
 		b.RunParallel(func(pb *testing.PB) {
+			var local latencyHistogram
+			defer latencies.add(&local)
 			for pb.Next() {
-				conn, err := net.Dial("tcp", addr)
+				stalls, start := dialer.stalls.Load(), time.Now()
+				conn, err := dialer.Dial()
 				if err != nil {
 					b.Fatal(err)
 				}
-				conn.(*net.TCPConn).SetLinger(0)
+				// Dials that waited for ports would skew the tail
+				if dialer.stalls.Load() == stalls {
+					local.record(time.Since(start))
+				}
 				conn.Close()
 			}
 		})
+		sched.report(b)
+		prof.Report(b)
+		latencies.report(b)
+		dialer.reportStalls(b)
 	})
 }
 
@@ -63,47 +86,41 @@ func BenchmarkTCPKeepAlive(b *testing.B) {
 	if err != nil {
 		b.Fatal(err)
 	}
-	defer ln.Close()
-
-	// Accept loop to drain connections
-	go func() {
-		for {
-			conn, err := ln.Accept()
-			if err != nil {
-				return
-			}
-			conn.Close()
-		}
-	}()
-
-	addr := ln.Addr().String()
+	// Accepted connections are closed immediately
+	addr := serve(b, ln, nil).Addr()
 
 	b.Run("Default", func(b *testing.B) {
-		dialer := &net.Dialer{}
+		defer trackGoroutines(b)()
+		dialer := newChurnDialer(addr, &net.Dialer{})
+		defer dialer.Close()
+		dialer.timer = b
 		b.ResetTimer()
 		for b.Loop() {
-			conn, err := dialer.Dial("tcp", addr)
+			conn, err := dialer.Dial()
 			if err != nil {
 				b.Fatal(err)
 			}
-			conn.(*net.TCPConn).SetLinger(0)
 			conn.Close()
 		}
+		dialer.reportStalls(b)
 	})
 
 	b.Run("WithConfig", func(b *testing.B) {
-		dialer := &net.Dialer{
+		defer trackGoroutines(b)()
+		dialer := newChurnDialer(addr, &net.Dialer{
 			KeepAlive: 30000000000, // 30 seconds in nanoseconds
-		}
+		})
+		defer dialer.Close()
+		dialer.timer = b
 		b.ResetTimer()
 		for b.Loop() {
-			conn, err := dialer.Dial("tcp", addr)
+			conn, err := dialer.Dial()
 			if err != nil {
 				b.Fatal(err)
 			}
-			conn.(*net.TCPConn).SetLinger(0)
 			conn.Close()
 		}
+		dialer.reportStalls(b)
 	})
 }
 
@@ -121,29 +138,18 @@ func BenchmarkTCPThroughput(b *testing.B) {
 
 	for _, s := range sizes {
 		b.Run(s.name, func(b *testing.B) {
+			defer trackGoroutines(b)()
+
 			// Setup echo server
 			ln, err := net.Listen("tcp", "127.0.0.1:0")
 			if err != nil {
 				b.Fatal(err)
 			}
-			defer ln.Close()
-
-			// Echo server goroutine
-			go func() {
-				for {
-					conn, err := ln.Accept()
-					if err != nil {
-						return
-					}
-					go func(c net.Conn) {
-						defer c.Close()
-						io.Copy(c, c)
-					}(conn)
-				}
-			}()
+			srv := serve(b, ln, echo)
+			defer srv.Close()
 
 			// Connect to server
-			conn, err := net.Dial("tcp", ln.Addr().String())
+			conn, err := net.Dial("tcp", srv.Addr())
 			if err != nil {
 				b.Fatal(err)
 			}
@@ -12,8 +12,11 @@ import (
 	"io"
 	"math/big"
 	"net"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/astavonin/go-optimization-guide/benchmarks/internal/contention"
 )
 
 var (
@@ -57,7 +60,17 @@ func init() {
 	}
 }
 
+// completeHandshake finishes the server side of a TLS handshake. Errors are
+// expected when the client disconnects early and are ignored.
+func completeHandshake(c net.Conn) {
+	if tlsConn, ok := c.(*tls.Conn); ok {
+		_ = tlsConn.Handshake()
+	}
+}
+
 // BenchmarkTLSHandshake measures TLS handshake time with various configurations.
+// Reports p50/p99/p999 handshake latency, since occasional slow handshakes
+// don't show in the mean.
 // Go 1.24: X25519MLKEM768 default; Go 1.25: SHA-1 disabled; Go 1.26: Post-quantum default.
 func BenchmarkTLSHandshake(b *testing.B) {
 	serverConfig := &tls.Config{
@@ -69,114 +82,176 @@ func BenchmarkTLSHandshake(b *testing.B) {
 	if err != nil {
 		b.Fatal(err)
 	}
-	defer ln.Close()
-
-	// Accept loop to complete TLS handshakes
-	go func() {
-		for {
-			conn, err := ln.Accept()
-			if err != nil {
-				return
-			}
-			go func(c net.Conn) {
-				defer c.Close()
-				if tlsConn, ok := c.(*tls.Conn); ok {
-					if err := tlsConn.Handshake(); err != nil {
-						// Server-side handshake errors expected when client disconnects early
-						return
-					}
-				}
-			}(conn)
-		}
-	}()
-
-	addr := ln.Addr().String()
+	addr := serve(b, ln, completeHandshake).Addr()
 
 	b.Run("TLS12", func(b *testing.B) {
+		defer trackGoroutines(b)()
 		clientConfig := &tls.Config{
 			InsecureSkipVerify: true,
 			MinVersion:         tls.VersionTLS12,
 			MaxVersion:         tls.VersionTLS12,
 		}
+		var latencies latencyHistogram
 		b.ResetTimer()
 		for b.Loop() {
+			start := time.Now()
 			conn, err := tls.Dial("tcp", addr, clientConfig)
 			if err != nil {
 				b.Fatal(err)
 			}
+			latencies.record(time.Since(start))
 			conn.Close()
 		}
+		latencies.report(b)
 	})
 
 	b.Run("TLS13", func(b *testing.B) {
+		defer trackGoroutines(b)()
 		clientConfig := &tls.Config{
 			InsecureSkipVerify: true,
 			MinVersion:         tls.VersionTLS13,
 		}
+		var latencies latencyHistogram
 		b.ResetTimer()
 		for b.Loop() {
+			start := time.Now()
 			conn, err := tls.Dial("tcp", addr, clientConfig)
 			if err != nil {
 				b.Fatal(err)
 			}
+			latencies.record(time.Since(start))
 			conn.Close()
 		}
+		latencies.report(b)
 	})
 
 	b.Run("TLS13_ECDHE", func(b *testing.B) {
+		defer trackGoroutines(b)()
 		clientConfig := &tls.Config{
 			InsecureSkipVerify: true,
 			MinVersion:         tls.VersionTLS13,
 			CurvePreferences:   []tls.CurveID{tls.X25519},
 		}
+		var latencies latencyHistogram
 		b.ResetTimer()
 		for b.Loop() {
+			start := time.Now()
 			conn, err := tls.Dial("tcp", addr, clientConfig)
 			if err != nil {
 				b.Fatal(err)
 			}
+			latencies.record(time.Since(start))
 			conn.Close()
 		}
+		latencies.report(b)
+	})
+
+	// Saturates a dedicated server with concurrent TLS 1.3 handshakes to show
+	// how server-side crypto scales with cores, which the sequential loops
+	// above cannot reach. Reports handshakes/sec and server-goroutines, the
+	// peak number of server handshakes in flight.
+	b.Run("TLS13_Parallel", func(b *testing.B) {
+		defer trackGoroutines(b)()
+		ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var active, peak atomic.Int64
+		server := serve(b, ln, func(c net.Conn) {
+			n := active.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			completeHandshake(c)
+			active.Add(-1)
+		})
+		defer server.Close()
+
+		dialer := newChurnDialer(server.Addr(), &net.Dialer{})
+		defer dialer.Close()
+		clientConfig := &tls.Config{
+			InsecureSkipVerify: true,
+			MinVersion:         tls.VersionTLS13,
+		}
+		var latencies parallelLatencies
+		var prof contention.Profile
+		prof.Start()
+		sched := startSchedSampler()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			var local latencyHistogram
+			defer latencies.add(&local)
+			for pb.Next() {
+				start := time.Now()
+				raw, err := dialer.Dial()
+				if err != nil {
+					b.Fatal(err)
+				}
+				conn := tls.Client(raw, clientConfig)
+				if err := conn.Handshake(); err != nil {
+					raw.Close()
+					b.Fatal(err)
+				}
+				local.record(time.Since(start))
+				conn.Close()
+			}
+		})
+		sched.report(b)
+		prof.Report(b)
+		b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "handshakes/sec")
+		b.ReportMetric(float64(peak.Load()), "server-goroutines")
+		latencies.report(b)
+		dialer.reportStalls(b)
 	})
 }
 
+// newTicketKey returns a random session ticket key.
+func newTicketKey(b *testing.B) [32]byte {
+	var key [32]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		b.Fatal(err)
+	}
+	return key
+}
+
+// primeSessionCache performs a full handshake with addr and reads until the
+// server closes the connection. TLS 1.3 tickets arrive after the handshake
+// and are only processed, and stored in the client cache, on Read.
+func primeSessionCache(b *testing.B, addr string, config *tls.Config) {
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	io.Copy(io.Discard, conn)
+	// tls.Dial caches sessions under the host name, not host:port
+	host, _, _ := net.SplitHostPort(addr)
+	if _, ok := config.ClientSessionCache.Get(host); !ok {
+		b.Fatal("no session ticket received")
+	}
+}
+
 // BenchmarkTLSResume measures TLS session resumption performance.
 // Compares full handshake vs resumed handshake (tickets/PSK).
+// The server uses fixed session ticket keys instead of automatic rotation
+// and rotates them once before the timed loop, keeping the previous key for
+// decryption, so resumption is deterministic and exercises a rotated key.
 func BenchmarkTLSResume(b *testing.B) {
 	serverConfig := &tls.Config{
-		Certificates:           []tls.Certificate{tlsTestCert},
-		MinVersion:             tls.VersionTLS13,
-		SessionTicketsDisabled: false, // Explicitly enable session tickets
+		Certificates: []tls.Certificate{tlsTestCert},
+		MinVersion:   tls.VersionTLS13,
 	}
+	previousKey, currentKey := newTicketKey(b), newTicketKey(b)
+	serverConfig.SetSessionTicketKeys([][32]byte{previousKey})
 
 	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
 	if err != nil {
 		b.Fatal(err)
 	}
-	defer ln.Close()
-
-	// Accept loop to complete TLS handshakes
-	go func() {
-		for {
-			conn, err := ln.Accept()
-			if err != nil {
-				return
-			}
-			go func(c net.Conn) {
-				defer c.Close()
-				if tlsConn, ok := c.(*tls.Conn); ok {
-					if err := tlsConn.Handshake(); err != nil {
-						// Server-side handshake errors expected when client disconnects early
-						return
-					}
-				}
-			}(conn)
-		}
-	}()
-
-	addr := ln.Addr().String()
+	addr := serve(b, ln, completeHandshake).Addr()
 
 	b.Run("FullHandshake", func(b *testing.B) {
+		defer trackGoroutines(b)()
 		clientConfig := &tls.Config{
 			InsecureSkipVerify: true,
 			MinVersion:         tls.VersionTLS13,
@@ -196,39 +271,29 @@ func BenchmarkTLSResume(b *testing.B) {
 	})
 
 	b.Run("Resumed", func(b *testing.B) {
-		cache := tls.NewLRUClientSessionCache(1)
+		defer trackGoroutines(b)()
 		clientConfig := &tls.Config{
 			InsecureSkipVerify: true,
 			MinVersion:         tls.VersionTLS13,
-			ClientSessionCache: cache,
+			ClientSessionCache: tls.NewLRUClientSessionCache(64),
 		}
+		// The function runs once per b.N probe, so start each run from the
+		// pre-rotation keys
+		serverConfig.SetSessionTicketKeys([][32]byte{previousKey})
+		primeSessionCache(b, addr, clientConfig)
 
-		// Establish multiple initial connections to populate cache
-		// TLS 1.3 sends session tickets after handshake
-		for i := 0; i < 3; i++ {
-			conn, err := tls.Dial("tcp", addr, clientConfig)
-			if err != nil {
-				b.Fatal(err)
-			}
-			// Force handshake and wait for session ticket
-			err = conn.Handshake()
-			if err != nil {
-				b.Fatal(err)
-			}
-			time.Sleep(50 * time.Millisecond)
-			conn.Close()
-		}
+		// Rotate: new tickets are issued under currentKey while the cached
+		// ticket, issued under previousKey, must still be accepted
+		serverConfig.SetSessionTicketKeys([][32]byte{currentKey, previousKey})
 
-		// Verify resumption works
 		testConn, err := tls.Dial("tcp", addr, clientConfig)
 		if err != nil {
 			b.Fatal(err)
 		}
-		testState := testConn.ConnectionState()
+		didResume := testConn.ConnectionState().DidResume
 		testConn.Close()
-
-		if !testState.DidResume {
-			b.Skip("session resumption not working, skipping resumed benchmark")
+		if !didResume {
+			b.Fatal("session not resumed after ticket key rotation")
 		}
 
 		resumedCount := 0
@@ -249,8 +314,29 @@ func BenchmarkTLSResume(b *testing.B) {
 	})
 }
 
+// Bytes sent per op by the TLSThroughput WriteSize variants
+const tlsStreamPayload = 256 << 10
+
+// sinkAck reads tlsStreamPayload bytes at a time from c and acknowledges
+// each payload with one byte, until the peer closes.
+func sinkAck(c net.Conn) {
+	completeHandshake(c)
+	ack := []byte{1}
+	for {
+		if _, err := io.CopyN(io.Discard, c, tlsStreamPayload); err != nil {
+			return
+		}
+		if _, err := c.Write(ack); err != nil {
+			return
+		}
+	}
+}
+
 // BenchmarkTLSThroughput measures TLS encrypted data transfer throughput.
-// Shows overhead of encryption vs plain TCP.
+// Shows overhead of encryption vs plain TCP. WriteSize variants send 256KB
+// per op in 256B, 1400B (one MTU-sized segment) or 16KB (one full record)
+// writes; each Write becomes at least one TLS record, so small writes pay
+// per-record framing, AEAD and syscall costs.
 func BenchmarkTLSThroughput(b *testing.B) {
 	serverConfig := &tls.Config{
 		Certificates: []tls.Certificate{tlsTestCert},
@@ -261,21 +347,8 @@ func BenchmarkTLSThroughput(b *testing.B) {
 	if err != nil {
 		b.Fatal(err)
 	}
-	defer ln.Close()
-
 	// TLS echo server
-	go func() {
-		for {
-			conn, err := ln.Accept()
-			if err != nil {
-				return
-			}
-			go func(c net.Conn) {
-				defer c.Close()
-				io.Copy(c, c)
-			}(conn)
-		}
-	}()
+	addr := serve(b, ln, echo).Addr()
 
 	sizes := []struct {
 		name string
@@ -287,12 +360,13 @@ func BenchmarkTLSThroughput(b *testing.B) {
 
 	for _, s := range sizes {
 		b.Run(s.name, func(b *testing.B) {
+			defer trackGoroutines(b)()
 			clientConfig := &tls.Config{
 				InsecureSkipVerify: true,
 				MinVersion:         tls.VersionTLS13,
 			}
 
-			conn, err := tls.Dial("tcp", ln.Addr().String(), clientConfig)
+			conn, err := tls.Dial("tcp", addr, clientConfig)
 			if err != nil {
 				b.Fatal(err)
 			}
@@ -316,4 +390,53 @@ func BenchmarkTLSThroughput(b *testing.B) {
 			}
 		})
 	}
+
+	sinkLn, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		b.Fatal(err)
+	}
+	sinkAddr := serve(b, sinkLn, sinkAck).Addr()
+
+	writeSizes := []struct {
+		name string
+		size int
+	}{
+		{"256B", 256},
+		{"1400B", 1400},
+		{"16KB", 16 * 1024},
+	}
+
+	for _, w := range writeSizes {
+		b.Run("WriteSize/"+w.name, func(b *testing.B) {
+			defer trackGoroutines(b)()
+			clientConfig := &tls.Config{
+				InsecureSkipVerify: true,
+				MinVersion:         tls.VersionTLS13,
+			}
+
+			conn, err := tls.Dial("tcp", sinkAddr, clientConfig)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer conn.Close()
+
+			chunk := make([]byte, w.size)
+			ack := make([]byte, 1)
+
+			b.SetBytes(tlsStreamPayload)
+			b.ResetTimer()
+
+			for b.Loop() {
+				for sent := 0; sent < tlsStreamPayload; sent += len(chunk) {
+					n := min(len(chunk), tlsStreamPayload-sent)
+					if _, err := conn.Write(chunk[:n]); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if _, err := io.ReadFull(conn, ack); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
 }
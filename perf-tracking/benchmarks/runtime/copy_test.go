@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+var sinkCopy uint64
+
+// Fixed-size values of 16B to 4KB for BenchmarkValueVsPointer.
+type (
+	copy16   [16 / 8]uint64
+	copy64   [64 / 8]uint64
+	copy256  [256 / 8]uint64
+	copy1024 [1024 / 8]uint64
+	copy4096 [4096 / 8]uint64
+)
+
+// Each type has the same method on a value and on a pointer receiver. They
+// read the first and last word so the whole struct has to be passed in.
+
+//go:noinline
+func (c copy16) valueSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c *copy16) pointerSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c copy64) valueSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c *copy64) pointerSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c copy256) valueSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c *copy256) pointerSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c copy1024) valueSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c *copy1024) pointerSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c copy4096) valueSum() uint64 { return c[0] + c[len(c)-1] }
+
+//go:noinline
+func (c *copy4096) pointerSum() uint64 { return c[0] + c[len(c)-1] }
+
+// passValue takes and returns v by value, copying it in both directions.
+//
+//go:noinline
+func passValue[T any](v T) T { return v }
+
+// passPointer takes and returns a pointer; nothing is copied.
+//
+//go:noinline
+func passPointer[T any](p *T) *T { return p }
+
+// copyCase is one struct size of BenchmarkValueVsPointer. Each function does
+// one operation on the same struct variable.
+type copyCase struct {
+	size                       int
+	passValue, passPointer     func() uint64
+	methodValue, methodPointer func() uint64
+}
+
+// newCopyCase builds the copyCase for T; valueSum and pointerSum call T's
+// methods, which generic code cannot do without an indirect call.
+func newCopyCase[T any](v *T, valueSum func() uint64, pointerSum func() uint64) copyCase {
+	return copyCase{
+		size: int(unsafe.Sizeof(*v)),
+		passValue: func() uint64 {
+			*v = passValue(*v)
+			return 0
+		},
+		passPointer: func() uint64 {
+			v = passPointer(v)
+			return 0
+		},
+		methodValue:   valueSum,
+		methodPointer: pointerSum,
+	}
+}
+
+// BenchmarkValueVsPointer passes and returns structs of 16B to 4KB by value
+// and by pointer, and calls a method on each with a value and a pointer
+// receiver, to measure where copying starts to cost more than indirection
+// on each Go version. Register-based calling passes small structs in
+// registers; larger ones are copied through memory.
+func BenchmarkValueVsPointer(b *testing.B) {
+	var (
+		v16   copy16
+		v64   copy64
+		v256  copy256
+		v1024 copy1024
+		v4096 copy4096
+	)
+	cases := []copyCase{
+		newCopyCase(&v16, func() uint64 { return v16.valueSum() }, func() uint64 { return v16.pointerSum() }),
+		newCopyCase(&v64, func() uint64 { return v64.valueSum() }, func() uint64 { return v64.pointerSum() }),
+		newCopyCase(&v256, func() uint64 { return v256.valueSum() }, func() uint64 { return v256.pointerSum() }),
+		newCopyCase(&v1024, func() uint64 { return v1024.valueSum() }, func() uint64 { return v1024.pointerSum() }),
+		newCopyCase(&v4096, func() uint64 { return v4096.valueSum() }, func() uint64 { return v4096.pointerSum() }),
+	}
+
+	for _, c := range cases {
+		for _, op := range []struct {
+			name string
+			fn   func() uint64
+		}{
+			{"Pass/Value", c.passValue},
+			{"Pass/Pointer", c.passPointer},
+			{"Method/Value", c.methodValue},
+			{"Method/Pointer", c.methodPointer},
+		} {
+			b.Run(fmt.Sprintf("%s/Size%d", op.name, c.size), func(b *testing.B) {
+				b.ReportAllocs()
+				var sum uint64
+				for b.Loop() {
+					sum += op.fn()
+				}
+				sinkCopy = sum
+			})
+		}
+	}
+}
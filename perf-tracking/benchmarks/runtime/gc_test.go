@@ -5,7 +5,14 @@ import (
 	"testing"
 )
 
-var sinkData []*Data
+// Sinks for the per-iteration allocations: each iteration's objects replace
+// the previous ones, so they escape to the heap without growing the live
+// heap beyond one iteration's worth.
+var (
+	sinkData      []*Data
+	sinkSmallData []*SmallData
+	sinkBuffers   [][]byte
+)
 
 // Data represents a test allocation with payload.
 type Data struct {
@@ -19,24 +26,28 @@ type SmallData struct {
 
 // BenchmarkGCThroughput measures allocation throughput under GC pressure.
 // Green Tea GC shows 10-40% improvement in Go 1.25/1.26.
+//
+// Every GC benchmark runs against a fixed liveHeap of gcLiveHeapBytes built
+// before the measured loop, and reports the heap it ran against (see
+// heapWatch). The loop's own allocations die after one iteration.
 func BenchmarkGCThroughput(b *testing.B) {
 	b.ReportAllocs()
 	b.SetBytes(128 * 1000)
-	var sink []*Data // Live heap across iterations
+	live := newLiveHeap(gcLiveHeapBytes, 0.5)
 
+	var heap heapWatch
+	heap.start()
 	for b.Loop() {
 		objects := make([]*Data, 1000)
 		for j := range 1000 {
 			objects[j] = &Data{payload: make([]byte, 128)}
 		}
-		// Retain some objects to maintain live heap
-		sink = append(sink, objects[0:100]...)
-		if len(sink) > 10000 {
-			sink = sink[1000:] // Keep heap live but bounded
-		}
+		sinkData = objects
 	}
+	heap.report(b)
 
-	sinkData = sink // Prevent DCE
+	sinkData = nil
+	runtime.KeepAlive(live)
 }
 
 // BenchmarkGCLatency measures garbage collection pause times.
@@ -44,15 +55,14 @@ func BenchmarkGCThroughput(b *testing.B) {
 func BenchmarkGCLatency(b *testing.B) {
 	b.ReportAllocs()
 	var ms runtime.MemStats
-	var sink [][]byte // Retain live heap
 
 	// Warmup and setup
 	b.StopTimer()
-	for range 100 {
-		sink = append(sink, make([]byte, 1024))
-	}
+	live := newLiveHeap(gcLiveHeapBytes, 0.5)
 	runtime.ReadMemStats(&ms)
 	basePauseNs := ms.PauseTotalNs
+	var heap heapWatch
+	heap.start()
 	b.StartTimer()
 
 	var n int
@@ -62,10 +72,7 @@ func BenchmarkGCLatency(b *testing.B) {
 		for j := range 1000 {
 			burst[j] = make([]byte, 1024)
 		}
-		sink = append(sink, burst[0]) // Retain some
-		if len(sink) > 1000 {
-			sink = sink[100:] // Keep heap live but bounded
-		}
+		sinkBuffers = burst
 
 		// Force GC and measure pause
 		runtime.GC()
@@ -78,59 +85,50 @@ func BenchmarkGCLatency(b *testing.B) {
 	if n > 0 {
 		b.ReportMetric(float64(pauseNs)/float64(n), "pause-ns/gc")
 	}
-	_ = sink // Prevent DCE
+	heap.report(b)
+
+	sinkBuffers = nil
+	runtime.KeepAlive(live)
 }
 
 // BenchmarkGCSmallObjects measures GC performance scanning small objects.
 // Go 1.26 uses vector instructions for improved scanning on modern CPUs.
+// Its live heap is all pointers, so every cycle is dominated by scanning.
 func BenchmarkGCSmallObjects(b *testing.B) {
 	b.ReportAllocs()
-	var sink []*SmallData // Retain live heap, use concrete type to avoid interface boxing
+	live := newLiveHeap(gcLiveHeapBytes, 1.0)
 
-	var i int
+	var heap heapWatch
+	heap.start()
 	for b.Loop() {
 		objects := make([]*SmallData, 10000)
 		for j := range 10000 {
 			objects[j] = &SmallData{value: int64(j)}
 		}
-		// Retain some objects
-		if i%10 == 0 {
-			sink = append(sink, objects[0:100]...)
-			if len(sink) > 1000 {
-				sink = sink[100:]
-			}
-		}
-		i++
+		sinkSmallData = objects
 	}
+	heap.report(b)
 
-	_ = sink // Prevent DCE
+	sinkSmallData = nil
+	runtime.KeepAlive(live)
 }
 
 // BenchmarkGCMixedWorkload measures realistic mixed allocation patterns.
 // Tests overall GC behavior with small, medium, and large objects.
 func BenchmarkGCMixedWorkload(b *testing.B) {
 	b.ReportAllocs()
-	var sink [][]byte // Retain live heap
+	live := newLiveHeap(gcLiveHeapBytes, 0.5)
 
-	var i int
+	var heap heapWatch
+	heap.start()
 	for b.Loop() {
 		small := make([]byte, 32)
 		medium := make([]byte, 4096)
 		large := make([]byte, 1<<20)
-
-		// Retain some allocations including large to create realistic GC pressure
-		if i%100 == 0 {
-			sink = append(sink, small, medium, large)
-			if len(sink) > 300 {
-				sink = sink[30:]
-			}
-		}
-
-		_ = small
-		_ = medium
-		_ = large
-		i++
+		sinkBuffers = [][]byte{small, medium, large}
 	}
+	heap.report(b)
 
-	_ = sink // Prevent DCE
+	sinkBuffers = nil
+	runtime.KeepAlive(live)
 }
@@ -0,0 +1,129 @@
+package runtime
+
+import (
+	"math/rand"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"testing"
+)
+
+const (
+	// Bytes of heap objects, live or not yet swept (MemStats.HeapAlloc)
+	heapAllocMetric = "/memory/classes/heap/objects:bytes"
+	// Heap marked live by the most recently completed GC cycle
+	heapLiveMetric = "/gc/heap/live:bytes"
+)
+
+func readHeap() (alloc, live uint64) {
+	samples := []metrics.Sample{{Name: heapAllocMetric}, {Name: heapLiveMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		alloc = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		live = samples[1].Value.Uint64()
+	}
+	return alloc, live
+}
+
+// heapSentinel is large enough to bypass the tiny allocator, whose blocks
+// may never be finalized.
+type heapSentinel struct{ _ [32]byte }
+
+// heapWatch records the heap size a GC benchmark runs against. GC cost scales
+// with the live heap, so ns/op is only comparable across versions together
+// with it. Besides readings before and after the benchmark it takes the live
+// heap once per GC cycle, from the finalizer of an object that is dropped
+// and re-armed every cycle, so watching adds no work to the timed loop.
+type heapWatch struct {
+	startAlloc, startLive uint64
+
+	mu      sync.Mutex
+	stopped bool
+	maxLive uint64
+}
+
+// start takes the before readings and arms the per-cycle watcher.
+func (w *heapWatch) start() {
+	w.startAlloc, w.startLive = readHeap()
+	w.arm()
+}
+
+func (w *heapWatch) arm() {
+	runtime.SetFinalizer(new(heapSentinel), func(*heapSentinel) {
+		_, live := readHeap()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.stopped {
+			return
+		}
+		w.maxLive = max(w.maxLive, live)
+		w.arm()
+	})
+}
+
+// report stops watching and adds heap-alloc-start-B, heap-alloc-end-B,
+// heap-live-start-B, heap-live-end-B and heap-live-max-B, the largest live
+// heap of any GC cycle that finished during the benchmark.
+func (w *heapWatch) report(b *testing.B) {
+	alloc, live := readHeap()
+	w.mu.Lock()
+	w.stopped = true
+	maxLive := max(w.maxLive, live)
+	w.mu.Unlock()
+
+	b.ReportMetric(float64(w.startAlloc), "heap-alloc-start-B")
+	b.ReportMetric(float64(alloc), "heap-alloc-end-B")
+	b.ReportMetric(float64(w.startLive), "heap-live-start-B")
+	b.ReportMetric(float64(live), "heap-live-end-B")
+	b.ReportMetric(float64(maxLive), "heap-live-max-B")
+}
+
+// Live heap every GC benchmark runs against. GOGC=100 lets the heap grow to
+// about twice this before each cycle.
+const gcLiveHeapBytes = 16 << 20
+
+// The allocation units of liveHeap, 64 bytes each: all pointers into the
+// graph, or all scalars, which the GC does not scan.
+type (
+	ptrChunk    [8]*ptrChunk
+	scalarChunk [64]byte
+)
+
+// liveHeap is a fixed-size object graph kept alive across a GC benchmark,
+// so each GC cycle marks the same amount of memory in every iteration and
+// on every Go version, regardless of what the measured loop allocates.
+type liveHeap struct {
+	ptrs    []*ptrChunk
+	scalars []*scalarChunk
+}
+
+// newLiveHeap builds a liveHeap of about size bytes in 64-byte chunks.
+// pointerDensity (0..1) is the share of chunks made of pointers; each of
+// their pointers links to a pseudo-random pointer chunk, so marking chases
+// pointers across the whole graph rather than walking it in address order.
+// The graph is the same on every run, and a GC settles the heap before
+// the caller starts timing.
+func newLiveHeap(size int, pointerDensity float64) *liveHeap {
+	chunks := size / 64
+	nPtrs := int(float64(chunks) * pointerDensity)
+	h := &liveHeap{
+		ptrs:    make([]*ptrChunk, nPtrs),
+		scalars: make([]*scalarChunk, chunks-nPtrs),
+	}
+	for i := range h.ptrs {
+		h.ptrs[i] = new(ptrChunk)
+	}
+	for i := range h.scalars {
+		h.scalars[i] = new(scalarChunk)
+	}
+	rng := rand.New(rand.NewSource(42))
+	for _, c := range h.ptrs {
+		for j := range c {
+			c[j] = h.ptrs[rng.Intn(len(h.ptrs))]
+		}
+	}
+	runtime.GC()
+	return h
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// lookupSizes sweeps collection sizes from a cache line of keys to well
+// past the L2 cache.
+var lookupSizes = []int{8, 16, 32, 64, 128, 256, 512, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20}
+
+// lookupLinearMax caps the linear scan: past a few thousand keys it is
+// orders of magnitude behind and would dominate the run time.
+const lookupLinearMax = 4 << 10
+
+// lookupSizeName formats a size as "Size8", "Size4K" or "Size1M".
+func lookupSizeName(n int) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("Size%dM", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("Size%dK", n>>10)
+	}
+	return fmt.Sprintf("Size%d", n)
+}
+
+// BenchmarkLookup compares linear scan, binary search over a sorted slice
+// and map lookup across collection sizes, for int and string keys. Every
+// lookup hits. perfbench derives the crossover sizes, where one strategy
+// starts to beat another, and exports them per Go version.
+func BenchmarkLookup(b *testing.B) {
+	b.Run("Int", func(b *testing.B) {
+		benchmarkLookup(b, func(i int) int { return i * 2 })
+	})
+	b.Run("String", func(b *testing.B) {
+		// Zero padding keeps lexical order equal to numeric order
+		benchmarkLookup(b, func(i int) string { return fmt.Sprintf("key-%08d", i*2) })
+	})
+}
+
+func benchmarkLookup[K cmp.Ordered](b *testing.B, key func(int) K) {
+	for _, size := range lookupSizes {
+		keys := make([]K, size)
+		index := make(map[K]int, size)
+		for i := range keys {
+			keys[i] = key(i)
+			index[keys[i]] = i
+		}
+		// Random hits in a fixed order, so every strategy sees the same
+		// queries and branch predictors can't learn a pattern
+		rng := rand.New(rand.NewSource(42))
+		queries := make([]K, 1024)
+		for i := range queries {
+			queries[i] = keys[rng.Intn(size)]
+		}
+
+		b.Run(lookupSizeName(size), func(b *testing.B) {
+			if size <= lookupLinearMax {
+				b.Run("Linear", func(b *testing.B) {
+					sum, i := 0, 0
+					for b.Loop() {
+						sum += slices.Index(keys, queries[i%len(queries)])
+						i++
+					}
+					_ = sum // Prevent DCE
+				})
+			}
+			b.Run("Binary", func(b *testing.B) {
+				sum, i := 0, 0
+				for b.Loop() {
+					pos, _ := slices.BinarySearch(keys, queries[i%len(queries)])
+					sum += pos
+					i++
+				}
+				_ = sum // Prevent DCE
+			})
+			b.Run("Map", func(b *testing.B) {
+				sum, i := 0, 0
+				for b.Loop() {
+					sum += index[queries[i%len(queries)]]
+					i++
+				}
+				_ = sum // Prevent DCE
+			})
+		})
+	}
+}
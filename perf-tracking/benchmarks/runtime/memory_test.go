@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"fmt"
 	"runtime"
 	"testing"
 	"unsafe"
@@ -35,15 +36,20 @@ func recursive(n int) int {
 	return arr[0] + recursive(n-1)
 }
 
-// BenchmarkSmallAllocSpecialized measures sub-512 byte allocation performance.
-// Go 1.26 shows up to 30% improvement for allocations under 512 bytes.
+// BenchmarkSmallAllocSpecialized measures allocation per runtime size class,
+// one SizeN sub-benchmark per class of N bytes (see sizeclass_test.go for
+// the classes run). Go 1.26 shows up to 30% improvement for allocations
+// under 512 bytes; per-class results show which classes a release changed.
 func BenchmarkSmallAllocSpecialized(b *testing.B) {
+	sizes, err := selectedAllocSizeClasses()
+	if err != nil {
+		b.Fatal(err)
+	}
 	runtime.GC()
 	b.ResetTimer()
-	sizes := []int{32, 64, 128, 256, 512}
 
 	for _, size := range sizes {
-		b.Run(allocSizeToString(size), func(b *testing.B) {
+		b.Run(fmt.Sprintf("Size%d", size), func(b *testing.B) {
 			b.ReportAllocs()
 			b.SetBytes(int64(size))
 			for b.Loop() {
@@ -54,22 +60,126 @@ func BenchmarkSmallAllocSpecialized(b *testing.B) {
 	}
 }
 
-// allocSizeToString converts size to string for sub-benchmark names.
-func allocSizeToString(size int) string {
-	switch size {
-	case 32:
-		return "Size32"
-	case 64:
-		return "Size64"
-	case 128:
-		return "Size128"
-	case 256:
-		return "Size256"
-	case 512:
-		return "Size512"
-	default:
-		return "Unknown"
+// allocScanSizes are the object sizes of BenchmarkAllocScan, spanning
+// small size classes to the 4KB objects of buffers and tables.
+var allocScanSizes = []int{16, 64, 256, 1024, 4096}
+
+// allocScanRing is how many of the most recent objects BenchmarkAllocScan
+// keeps alive, so GC cycles have a bounded live set of the kind measured.
+const allocScanRing = 1024
+
+// allocScanTarget is what the pointer-full objects point at.
+var allocScanTarget uint64
+
+// BenchmarkAllocScan allocates pointer-free (noscan) and pointer-full
+// objects of the same sizes. The allocator skips heap bitmaps for noscan
+// objects and the GC never scans them, so GC scanning work (Green Tea,
+// vectorized scanning) only shows up in the Pointers variants; the NoScan
+// variants isolate allocation itself. Each op allocates one object, fills
+// every word (a value or a pointer to one shared target) and keeps it in a
+// ring of the last allocScanRing objects, so the live heap is the same for
+// both kinds.
+func BenchmarkAllocScan(b *testing.B) {
+	for _, size := range allocScanSizes {
+		words := size / 8
+		b.Run(fmt.Sprintf("NoScan/Size%d", size), func(b *testing.B) {
+			ring := make([][]uint64, allocScanRing)
+			b.ReportAllocs()
+			b.SetBytes(int64(size))
+			i := 0
+			for b.Loop() {
+				obj := make([]uint64, words)
+				for j := range obj {
+					obj[j] = uint64(j)
+				}
+				ring[i%allocScanRing] = obj
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("Pointers/Size%d", size), func(b *testing.B) {
+			ring := make([][]*uint64, allocScanRing)
+			b.ReportAllocs()
+			b.SetBytes(int64(size))
+			i := 0
+			for b.Loop() {
+				obj := make([]*uint64, words)
+				for j := range obj {
+					obj[j] = &allocScanTarget
+				}
+				ring[i%allocScanRing] = obj
+				i++
+			}
+		})
+	}
+}
+
+// Sinks for BenchmarkTinyAlloc: the zero-size and combined objects must
+// escape to the heap, and its pointer-full objects need a pointer type.
+var (
+	sinkEmpty        *struct{}
+	sinkEmptySlice   []struct{}
+	sinkTiny4        *uint32
+	sinkTiny8        *uint64
+	sinkTinyPtr      **uint64
+	sinkTinyCombined [3]unsafe.Pointer
+)
+
+// BenchmarkTinyAlloc measures allocations below the 16-byte size class.
+// Pointer-free objects under 16 bytes are packed into shared 16-byte blocks
+// by the tiny allocator, zero-size objects all share one address and never
+// reach it, and 8-byte pointer-full objects bypass it for the 8-byte class;
+// each path changes differently from the 32-512B range of
+// BenchmarkSmallAllocSpecialized.
+func BenchmarkTinyAlloc(b *testing.B) {
+	b.Run("ZeroSize/Struct", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sinkEmpty = new(struct{})
+		}
+	})
+	b.Run("ZeroSize/Slice", func(b *testing.B) {
+		b.ReportAllocs()
+		n := 16
+		for b.Loop() {
+			sinkEmptySlice = make([]struct{}, n)
+		}
+	})
+	for _, size := range []int{1, 2, 4, 8, 12} {
+		b.Run(fmt.Sprintf("Tiny/Size%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				sinkBytes = make([]byte, size)
+			}
+		})
 	}
+	// Three objects filling one 16-byte block: after the first, the tiny
+	// allocator only bumps an offset into the current block.
+	b.Run("Tiny/Combined", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sinkTinyCombined[0] = unsafe.Pointer(new(uint32))
+			sinkTinyCombined[1] = unsafe.Pointer(new(uint32))
+			sinkTinyCombined[2] = unsafe.Pointer(new(uint64))
+		}
+	})
+	b.Run("Typed/Uint32", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sinkTiny4 = new(uint32)
+		}
+	})
+	b.Run("Typed/Uint64", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sinkTiny8 = new(uint64)
+		}
+	})
+	b.Run("Typed/Pointer", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			sinkTinyPtr = new(*uint64)
+		}
+	})
 }
 
 // BenchmarkGoroutineCreate measures goroutine creation overhead.
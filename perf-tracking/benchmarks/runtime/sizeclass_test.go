@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// allocSizeClassesEnv selects the size classes BenchmarkSmallAllocSpecialized
+// runs: "all" for every class in allocSizeClasses, or a comma-separated list
+// of class sizes in bytes. collect_benchmarks.py --size-classes sets it.
+const allocSizeClassesEnv = "BENCH_ALLOC_SIZE_CLASSES"
+
+// allocSizeClasses are the runtime's small-object size classes from 16 bytes
+// up to 32KB (internal/runtime/gc/sizeclasses.go, unchanged across the
+// benchmarked versions). Pointer-free objects under 16 bytes go through the
+// tiny allocator instead of the 8-byte class; BenchmarkTinyAlloc covers them.
+var allocSizeClasses = []int{
+	16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240,
+	256, 288, 320, 352, 384, 416, 448, 480, 512, 576, 640, 704, 768, 896,
+	1024, 1152, 1280, 1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456,
+	4096, 4864, 5376, 6144, 6528, 6784, 6912, 8192, 9472, 9728, 10240, 10880,
+	12288, 13568, 14336, 16384, 18432, 19072, 20480, 21760, 24576, 27264,
+	28672, 32768,
+}
+
+// defaultAllocSizeClasses is the subset run without allocSizeClassesEnv:
+// every class up to 512 bytes, where Go 1.26 generates a specialized
+// allocator per class, and the power-of-two classes above it.
+func defaultAllocSizeClasses() []int {
+	var sizes []int
+	for _, size := range allocSizeClasses {
+		if size <= 512 || size&(size-1) == 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+// selectedAllocSizeClasses returns the size classes chosen by
+// allocSizeClassesEnv, or defaultAllocSizeClasses when it is unset.
+func selectedAllocSizeClasses() ([]int, error) {
+	spec := strings.TrimSpace(os.Getenv(allocSizeClassesEnv))
+	switch spec {
+	case "":
+		return defaultAllocSizeClasses(), nil
+	case "all":
+		return allocSizeClasses, nil
+	}
+
+	var sizes []int
+	for field := range strings.SplitSeq(spec, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid size %q", allocSizeClassesEnv, field)
+		}
+		if !slices.Contains(allocSizeClasses, size) {
+			return nil, fmt.Errorf("%s: %d is not a size class", allocSizeClassesEnv, size)
+		}
+		sizes = append(sizes, size)
+	}
+	slices.Sort(sizes)
+	return slices.Compact(sizes), nil
+}
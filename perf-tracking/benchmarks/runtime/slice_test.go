@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+// sliceConcatLen is the length of each slice merged by BenchmarkSliceConcat
+const sliceConcatLen = 16
+
+// elem64 is the large element of BenchmarkSliceConcat
+type elem64 [8]int64
+
+var (
+	sinkInts   []int64
+	sinkElem64 []elem64
+)
+
+// BenchmarkSliceConcat merges 4 or 64 slices of sliceConcatLen elements, of
+// 8 and 64 bytes, into one: append(dst, src...) onto a nil slice, which
+// regrows as it goes, copy into a slice presized to the total length, and
+// slices.Concat, which presizes internally.
+func BenchmarkSliceConcat(b *testing.B) {
+	for _, count := range []int{4, 64} {
+		benchSliceConcat(b, count, &sinkInts)
+		benchSliceConcat(b, count, &sinkElem64)
+	}
+}
+
+func benchSliceConcat[E any](b *testing.B, count int, sink *[]E) {
+	srcs := make([][]E, count)
+	for i := range srcs {
+		srcs[i] = make([]E, sliceConcatLen)
+	}
+	var zero E
+	suffix := fmt.Sprintf("Slices%d/Elem%dB", count, unsafe.Sizeof(zero))
+	total := int64(count * sliceConcatLen * int(unsafe.Sizeof(zero)))
+
+	b.Run("Append/"+suffix, func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(total)
+		for b.Loop() {
+			var dst []E
+			for _, src := range srcs {
+				dst = append(dst, src...)
+			}
+			*sink = dst
+		}
+	})
+	b.Run("Copy/"+suffix, func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(total)
+		for b.Loop() {
+			dst := make([]E, count*sliceConcatLen)
+			n := 0
+			for _, src := range srcs {
+				n += copy(dst[n:], src)
+			}
+			*sink = dst
+		}
+	})
+	b.Run("Concat/"+suffix, func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(total)
+		for b.Loop() {
+			*sink = slices.Concat(srcs...)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const startupMinimalSource = `package main
+
+import "os"
+
+func main() {
+	os.Stdout.Write([]byte("ready\n"))
+}
+`
+
+// Package count and package-level variables per package of the large init
+// program; each package imports the previous one, so initialization runs as
+// a long dependency chain like in big CLI tools.
+const (
+	startupInitPackages = 20
+	startupInitVars     = 50
+)
+
+// startupLargeInitSources returns the files of a program whose package-level
+// variables build maps and compile regexps at init, plus imports of
+// net/http, crypto/tls and text/template whose own init also runs.
+func startupLargeInitSources() map[string]string {
+	files := make(map[string]string)
+	for p := 0; p < startupInitPackages; p++ {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "package pkg%02d\n\nimport (\n\t\"regexp\"\n\t\"strconv\"\n", p)
+		if p > 0 {
+			fmt.Fprintf(&sb, "\n\tprev \"startup/pkg%02d\"\n", p-1)
+		}
+		sb.WriteString(")\n\n")
+		for v := 0; v < startupInitVars; v++ {
+			dep := "0"
+			if p > 0 {
+				dep = fmt.Sprintf("len(prev.Table%d)", v)
+			}
+			fmt.Fprintf(&sb, "var Table%d = func() map[string]int {\n", v)
+			sb.WriteString("\tm := make(map[string]int, 64)\n")
+			fmt.Fprintf(&sb, "\tfor i := 0; i < 64; i++ {\n\t\tm[strconv.Itoa(i)] = i + %s\n\t}\n", dep)
+			sb.WriteString("\treturn m\n}()\n\n")
+			fmt.Fprintf(&sb, "var Pattern%d = regexp.MustCompile(`^v%d-[a-z]+-(\\d+)$`)\n\n", v, v)
+		}
+		files[fmt.Sprintf("pkg%02d/pkg.go", p)] = sb.String()
+	}
+
+	files["main.go"] = fmt.Sprintf(`package main
+
+import (
+	_ "crypto/tls"
+	_ "net/http"
+	"os"
+	_ "text/template"
+
+	last "startup/pkg%02d"
+)
+
+func main() {
+	if len(last.Table0) == 0 {
+		os.Exit(1)
+	}
+	os.Stdout.Write([]byte("ready\n"))
+}
+`, startupInitPackages-1)
+	return files
+}
+
+// buildStartupBinary writes files as module "startup" in a temp dir and
+// builds it with the go command running the benchmarks (go test puts its
+// GOROOT/bin first in PATH), so the binary matches the version under test.
+func buildStartupBinary(b *testing.B, files map[string]string) string {
+	b.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		b.Skipf("go command not found: %v", err)
+	}
+
+	dir := b.TempDir()
+	files["go.mod"] = "module startup\n\ngo 1.21\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	binary := filepath.Join(dir, "startup")
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	cmd := exec.Command(goBin, "build", "-o", binary, ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		b.Fatalf("failed to build startup binary: %v\n%s", err, out)
+	}
+	return binary
+}
+
+// BenchmarkStartup measures process startup: the time from exec until the
+// binary's first line of output, which covers runtime bootstrap, scheduler
+// and module data setup, and package initialization. This is the fixed cost
+// paid by every CLI invocation and cold serverless function.
+//
+// Minimal has no imports beyond os; LargeInit builds 1000 maps and compiles
+// 1000 regexps at init across a chain of packages, plus heavy stdlib init.
+// Process exit and reaping are excluded from the timing.
+func BenchmarkStartup(b *testing.B) {
+	cases := []struct {
+		name  string
+		files func() map[string]string
+	}{
+		{"Minimal", func() map[string]string { return map[string]string{"main.go": startupMinimalSource} }},
+		{"LargeInit", startupLargeInitSources},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			binary := buildStartupBinary(b, tc.files())
+			b.ReportAllocs()
+			for b.Loop() {
+				cmd := exec.Command(binary)
+				stdout, err := cmd.StdoutPipe()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := cmd.Start(); err != nil {
+					b.Fatal(err)
+				}
+				line, err := bufio.NewReader(stdout).ReadString('\n')
+				if err != nil || line != "ready\n" {
+					b.Fatalf("unexpected first output %q: %v", line, err)
+				}
+
+				b.StopTimer()
+				if err := cmd.Wait(); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -1,13 +1,19 @@
 package runtime
 
 import (
+	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/astavonin/go-optimization-guide/benchmarks/internal/contention"
 )
 
 // BenchmarkSyncMap measures sync.Map concurrent operations.
 // Go 1.24 reduces contention for disjoint key access patterns.
+// Parallel reports contention metrics under BENCH_CONTENTION_PROFILE.
 func BenchmarkSyncMap(b *testing.B) {
 	b.Run("SingleThreaded", func(b *testing.B) {
 		var m sync.Map
@@ -21,6 +27,8 @@ func BenchmarkSyncMap(b *testing.B) {
 
 	b.Run("Parallel", func(b *testing.B) {
 		var m sync.Map
+		var prof contention.Profile
+		prof.Start()
 		b.RunParallel(func(pb *testing.PB) {
 			i := 0
 			for pb.Next() {
@@ -29,6 +37,7 @@ func BenchmarkSyncMap(b *testing.B) {
 				i++
 			}
 		})
+		prof.Report(b)
 	})
 }
 
@@ -112,6 +121,144 @@ func BenchmarkSwissMapIteration(b *testing.B) {
 	}
 }
 
+// mapRangeSize is the number of entries of the maps in BenchmarkMapRange
+const mapRangeSize = 10000
+
+// BenchmarkMapRange complements BenchmarkSwissMapIteration, which ranges
+// over a map that never changes. Sweep ranges over a cache-like map that
+// evicts a share of its entries during every pass and inserts a replacement
+// for each as it goes, keeping its size steady; None is the same pass evicting
+// nothing. Load ranges over mapRangeSize entries in a map made with a size
+// hint of 1x, 2x and 8x that, so the iterator walks tables 100%, 50% and
+// 12% full (relative to the hint).
+func BenchmarkMapRange(b *testing.B) {
+	for _, evict := range []struct {
+		name   string
+		period int // every period-th entry is evicted per pass
+	}{
+		{"None", 0},
+		{"Evict1pct", 100},
+		{"Evict10pct", 10},
+	} {
+		b.Run("Sweep/"+evict.name, func(b *testing.B) {
+			b.ReportAllocs()
+			// Values are insertion sequence numbers, so each pass evicts
+			// a different set of entries
+			m := make(map[int]int, mapRangeSize)
+			for i := range mapRangeSize {
+				m[i] = i
+			}
+			next := mapRangeSize
+
+			pass := 0
+			for b.Loop() {
+				for k, v := range m {
+					if evict.period > 0 && (v+pass)%evict.period == 0 {
+						delete(m, k)
+						m[next] = next
+						next++
+					}
+				}
+				pass++
+			}
+		})
+	}
+
+	for _, hint := range []int{1, 2, 8} {
+		b.Run(fmt.Sprintf("Load/Pct%d", 100/hint), func(b *testing.B) {
+			b.ReportAllocs()
+			m := make(map[int]int, mapRangeSize*hint)
+			for i := range mapRangeSize {
+				m[i] = i
+			}
+
+			for b.Loop() {
+				sum := 0
+				for _, v := range m {
+					sum += v
+				}
+				_ = sum // Prevent DCE
+			}
+		})
+	}
+}
+
+// mapKey is the composite key of BenchmarkMapKey
+type mapKey struct {
+	region string
+	id     int
+}
+
+// mapKeySize is the number of entries of the maps in BenchmarkMapKey
+const mapKeySize = 10000
+
+// BenchmarkMapKey looks up a (region, id) pair, building the key from its
+// parts on every lookup as callers do: as a comparable struct, or encoded
+// into a string with fmt.Sprintf or a strings.Builder. Struct keys hash
+// both fields without allocating; string keys pay for encoding first.
+func BenchmarkMapKey(b *testing.B) {
+	regions := []string{"us-east-1", "us-west-2", "eu-central-1", "ap-southeast-1"}
+	parts := make([]mapKey, mapKeySize)
+	for i := range parts {
+		parts[i] = mapKey{region: regions[i%len(regions)], id: i}
+	}
+
+	b.Run("Struct", func(b *testing.B) {
+		m := make(map[mapKey]int, mapKeySize)
+		for i, k := range parts {
+			m[k] = i
+		}
+		b.ReportAllocs()
+		sum, i := 0, 0
+		for b.Loop() {
+			p := parts[i%mapKeySize]
+			sum += m[mapKey{region: p.region, id: p.id}]
+			i++
+		}
+		_ = sum // Prevent DCE
+	})
+
+	b.Run("Sprintf", func(b *testing.B) {
+		m := make(map[string]int, mapKeySize)
+		for i, k := range parts {
+			m[fmt.Sprintf("%s:%d", k.region, k.id)] = i
+		}
+		b.ReportAllocs()
+		sum, i := 0, 0
+		for b.Loop() {
+			p := parts[i%mapKeySize]
+			sum += m[fmt.Sprintf("%s:%d", p.region, p.id)]
+			i++
+		}
+		_ = sum // Prevent DCE
+	})
+
+	b.Run("Builder", func(b *testing.B) {
+		m := make(map[string]int, mapKeySize)
+		for i, k := range parts {
+			m[buildMapKey(k.region, k.id)] = i
+		}
+		b.ReportAllocs()
+		sum, i := 0, 0
+		for b.Loop() {
+			p := parts[i%mapKeySize]
+			sum += m[buildMapKey(p.region, p.id)]
+			i++
+		}
+		_ = sum // Prevent DCE
+	})
+}
+
+// buildMapKey encodes region and id like "%s:%d" with a presized Builder
+func buildMapKey(region string, id int) string {
+	var sb strings.Builder
+	sb.Grow(len(region) + 1 + 20)
+	sb.WriteString(region)
+	sb.WriteByte(':')
+	sb.WriteString(strconv.Itoa(id))
+	return sb.String()
+}
+
 // sizeToString converts size to string for sub-benchmark names.
 func sizeToString(size int) string {
 	switch size {
@@ -127,11 +274,16 @@ func sizeToString(size int) string {
 }
 
 // BenchmarkMutexContention measures mutex performance under contention.
-// Baseline for scheduler behavior across versions.
+// Baseline for scheduler behavior across versions. With
+// BENCH_CONTENTION_PROFILE set it also reports mutex-contention-ns/op, the
+// time spent waiting for the lock, which shows spinning and handoff changes
+// directly rather than through throughput.
 func BenchmarkMutexContention(b *testing.B) {
 	var mu sync.Mutex
 	var counter int
 
+	var prof contention.Profile
+	prof.Start()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
 			mu.Lock()
@@ -139,6 +291,7 @@ func BenchmarkMutexContention(b *testing.B) {
 			mu.Unlock()
 		}
 	})
+	prof.Report(b)
 
 	_ = counter // Prevent DCE
 }
@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// BenchmarkSyscall measures the floor cost of cheap syscalls and clock reads.
+// Results show per-platform syscall entry cost (and vDSO use for clocks) and
+// catch runtime changes to the syscall path between Go versions.
+func BenchmarkSyscall(b *testing.B) {
+	b.Run("Getpid", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = os.Getpid()
+		}
+	})
+
+	// time.Now reads both the wall and monotonic clocks
+	b.Run("TimeNow", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = time.Now()
+		}
+	})
+
+	// time.Since on a monotonic time only reads runtime nanotime
+	b.Run("TimeSince", func(b *testing.B) {
+		b.ReportAllocs()
+		start := time.Now()
+		for b.Loop() {
+			_ = time.Since(start)
+		}
+	})
+
+	b.Run("DevNullWrite", func(b *testing.B) {
+		b.ReportAllocs()
+		f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+		buf := make([]byte, 64)
+		for b.Loop() {
+			if _, err := f.Write(buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// Reading /dev/null returns EOF immediately: a pure round trip
+	b.Run("DevNullRead", func(b *testing.B) {
+		b.ReportAllocs()
+		f, err := os.Open(os.DevNull)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+		buf := make([]byte, 64)
+		for b.Loop() {
+			_, _ = f.Read(buf)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// timeoutIdle is the timeout of BenchmarkTimeout, long enough that it never
+// fires while the benchmark runs
+const timeoutIdle = time.Minute
+
+// BenchmarkTimeout waits for a result with a timeout, using the common
+// idioms: select on time.After, a time.NewTimer stopped afterwards, one
+// timer reused with Reset, and context.WithTimeout. The result is always
+// ready, as in the common case where the operation beats its deadline.
+//
+// Besides allocs/op each reports retained-B/op, the heap still in use per
+// op after a GC. Before Go 1.23 a time.After timer stayed reachable until it
+// fired, so TimeAfter retained its timer for timeoutIdle; since then unused
+// timers are collected and Stop is no longer needed to release them.
+func BenchmarkTimeout(b *testing.B) {
+	b.Run("TimeAfter", func(b *testing.B) {
+		benchTimeout(b, func(ch chan int) int {
+			select {
+			case v := <-ch:
+				return v
+			case <-time.After(timeoutIdle):
+				return -1
+			}
+		})
+	})
+
+	b.Run("NewTimer", func(b *testing.B) {
+		benchTimeout(b, func(ch chan int) int {
+			t := time.NewTimer(timeoutIdle)
+			defer t.Stop()
+			select {
+			case v := <-ch:
+				return v
+			case <-t.C:
+				return -1
+			}
+		})
+	})
+
+	b.Run("TimerReset", func(b *testing.B) {
+		t := time.NewTimer(timeoutIdle)
+		defer t.Stop()
+		benchTimeout(b, func(ch chan int) int {
+			t.Reset(timeoutIdle)
+			select {
+			case v := <-ch:
+				return v
+			case <-t.C:
+				return -1
+			}
+		})
+	})
+
+	b.Run("Context", func(b *testing.B) {
+		parent := context.Background()
+		benchTimeout(b, func(ch chan int) int {
+			ctx, cancel := context.WithTimeout(parent, timeoutIdle)
+			defer cancel()
+			select {
+			case v := <-ch:
+				return v
+			case <-ctx.Done():
+				return -1
+			}
+		})
+	})
+}
+
+// benchTimeout runs wait with a ready result each iteration and reports
+// retained-B/op.
+func benchTimeout(b *testing.B, wait func(ch chan int) int) {
+	ch := make(chan int, 1)
+	b.ReportAllocs()
+	runtime.GC()
+	before, _ := readHeap()
+
+	n, sum := 0, 0
+	for b.Loop() {
+		ch <- n
+		sum += wait(ch)
+		n++
+	}
+
+	b.StopTimer()
+	runtime.GC()
+	after, _ := readHeap()
+	if after < before {
+		after = before
+	}
+	b.ReportMetric(float64(after-before)/float64(n), "retained-B/op")
+	sinkInt = sum
+}
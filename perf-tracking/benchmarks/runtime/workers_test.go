@@ -0,0 +1,193 @@
+package runtime
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Batch of BenchmarkFanOut: fanOutTasks tasks, the first fanOutLongTasks of
+// them (about 10%) long. Work is counted in spin iterations, so it is the
+// same on every Go version.
+const (
+	fanOutTasks     = 256
+	fanOutLongTasks = 26
+	fanOutShortWork = 200
+	fanOutLongWork  = 20000
+)
+
+var sinkFanOut atomic.Uint64
+
+// spin does n iterations of a data-dependent LCG, so it cannot be elided
+func spin(n int) uint64 {
+	x := uint64(n)
+	for range n {
+		x = x*6364136223846793005 + 1442695040888963407
+	}
+	return x
+}
+
+// fanOutBatch returns the work of each task of a batch, long tasks first.
+func fanOutBatch() []int {
+	tasks := make([]int, fanOutTasks)
+	for i := range tasks {
+		if i < fanOutLongTasks {
+			tasks[i] = fanOutLongWork
+		} else {
+			tasks[i] = fanOutShortWork
+		}
+	}
+	return tasks
+}
+
+// BenchmarkFanOut distributes a batch of short and long tasks across
+// GOMAXPROCS workers and waits for all of them; ns/op is the completion
+// time of the batch. SharedChannel has every worker receive from one
+// channel. Static splits the batch into contiguous chunks up front, so the
+// worker holding the long tasks finishes last. Stealing gives each worker
+// such a chunk as its own queue, and workers that run out take tasks from
+// the others' queues.
+//
+// idle-pct is the share of worker time spent done while others still
+// worked, i.e. the imbalance each strategy leaves.
+func BenchmarkFanOut(b *testing.B) {
+	strategies := []struct {
+		name string
+		run  func(tasks []int, workers int, done func(worker int))
+	}{
+		{"SharedChannel", fanOutShared},
+		{"Static", fanOutStatic},
+		{"Stealing", fanOutStealing},
+	}
+	for _, s := range strategies {
+		b.Run(s.name, func(b *testing.B) {
+			tasks := fanOutBatch()
+			workers := runtime.GOMAXPROCS(0)
+			finished := make([]time.Time, workers)
+			var idle, busy time.Duration
+
+			b.ReportAllocs()
+			for b.Loop() {
+				start := time.Now()
+				s.run(tasks, workers, func(w int) { finished[w] = time.Now() })
+				end := time.Now()
+				for _, f := range finished {
+					idle += end.Sub(f)
+				}
+				busy += time.Duration(workers) * end.Sub(start)
+			}
+
+			if busy > 0 {
+				b.ReportMetric(float64(idle)/float64(busy)*100, "idle-pct")
+			}
+		})
+	}
+}
+
+// fanOutShared feeds all tasks through one unbuffered channel.
+func fanOutShared(tasks []int, workers int, done func(worker int)) {
+	ch := make(chan int)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var sum uint64
+			for work := range ch {
+				sum += spin(work)
+			}
+			sinkFanOut.Add(sum)
+			done(w)
+		}()
+	}
+	for _, work := range tasks {
+		ch <- work
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// fanOutChunk returns worker w's contiguous share of tasks.
+func fanOutChunk(tasks []int, workers, w int) []int {
+	return tasks[w*len(tasks)/workers : (w+1)*len(tasks)/workers]
+}
+
+// fanOutStatic runs each worker on its chunk of tasks only.
+func fanOutStatic(tasks []int, workers int, done func(worker int)) {
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var sum uint64
+			for _, work := range fanOutChunk(tasks, workers, w) {
+				sum += spin(work)
+			}
+			sinkFanOut.Add(sum)
+			done(w)
+		}()
+	}
+	wg.Wait()
+}
+
+// stealQueue is one worker's queue in fanOutStealing: the owner takes from
+// the back, thieves from the front.
+type stealQueue struct {
+	mu    sync.Mutex
+	tasks []int
+}
+
+func (q *stealQueue) popBack() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.tasks) == 0 {
+		return 0, false
+	}
+	work := q.tasks[len(q.tasks)-1]
+	q.tasks = q.tasks[:len(q.tasks)-1]
+	return work, true
+}
+
+func (q *stealQueue) popFront() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.tasks) == 0 {
+		return 0, false
+	}
+	work := q.tasks[0]
+	q.tasks = q.tasks[1:]
+	return work, true
+}
+
+// fanOutStealing starts each worker on its chunk and lets it steal from the
+// other queues, starting with its neighbor, once its own is empty. No tasks
+// are added during a batch, so a worker that finds every queue empty is done.
+func fanOutStealing(tasks []int, workers int, done func(worker int)) {
+	queues := make([]stealQueue, workers)
+	for w := range queues {
+		queues[w].tasks = fanOutChunk(tasks, workers, w)
+	}
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var sum uint64
+			for {
+				work, ok := queues[w].popBack()
+				for i := 1; !ok && i < workers; i++ {
+					work, ok = queues[(w+i)%workers].popFront()
+				}
+				if !ok {
+					break
+				}
+				sum += spin(work)
+			}
+			sinkFanOut.Add(sum)
+			done(w)
+		}()
+	}
+	wg.Wait()
+}
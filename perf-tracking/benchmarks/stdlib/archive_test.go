@@ -0,0 +1,198 @@
+package stdlib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+)
+
+// archiveFile is one file of archiveCorpus
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+// archiveCorpus is a deterministic tree shaped like a deploy bundle: 200
+// files of 256B to 64KB, four in five of them text that compresses well,
+// the rest random bytes that don't (binaries, images), about 3MB in all.
+var archiveCorpus = func() []archiveFile {
+	rng := rand.New(rand.NewPCG(9, 10))
+	line := `{"level":"info","ts":"2024-01-20T12:00:00Z","msg":"request served","path":"/api/v1/users","status":200}` + "\n"
+	files := make([]archiveFile, 200)
+	for i := range files {
+		size := 256 << rng.IntN(9)
+		var data []byte
+		if i%5 == 4 {
+			data = make([]byte, size)
+			for j := range data {
+				data[j] = byte(rng.Uint32())
+			}
+		} else {
+			data = []byte(strings.Repeat(line, size/len(line)+1))[:size]
+		}
+		files[i] = archiveFile{name: fmt.Sprintf("app/d%d/file%03d.dat", i/20, i), data: data}
+	}
+	return files
+}()
+
+// archiveCorpusBytes is the uncompressed size of archiveCorpus
+var archiveCorpusBytes = func() int64 {
+	var n int64
+	for _, f := range archiveCorpus {
+		n += int64(len(f.data))
+	}
+	return n
+}()
+
+// archiveModTime is fixed so archives are byte-for-byte reproducible
+var archiveModTime = time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)
+
+func writeTar(w io.Writer, files []archiveFile) error {
+	tw := tar.NewWriter(w)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.data)), ModTime: archiveModTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeTarGzip(w io.Writer, files []archiveFile) error {
+	zw := gzip.NewWriter(w)
+	if err := writeTar(zw, files); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func writeZip(method uint16) func(io.Writer, []archiveFile) error {
+	return func(w io.Writer, files []archiveFile) error {
+		zw := zip.NewWriter(w)
+		for _, f := range files {
+			fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: method, Modified: archiveModTime})
+			if err != nil {
+				return err
+			}
+			if _, err := fw.Write(f.data); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	}
+}
+
+// readTar extracts every entry to io.Discard and returns the bytes read
+func readTar(r io.Reader) (int64, error) {
+	tr := tar.NewReader(r)
+	var n int64
+	for {
+		_, err := tr.Next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		m, err := io.Copy(io.Discard, tr)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+func readTarGzip(data []byte) (int64, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	return readTar(zr)
+}
+
+func readZip(data []byte) (int64, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return n, err
+		}
+		m, err := io.Copy(io.Discard, rc)
+		n += m
+		if cerr := rc.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+var archiveFormats = []struct {
+	name  string
+	write func(io.Writer, []archiveFile) error
+	read  func([]byte) (int64, error)
+}{
+	{"Tar", writeTar, func(data []byte) (int64, error) { return readTar(bytes.NewReader(data)) }},
+	{"TarGzip", writeTarGzip, readTarGzip},
+	{"ZipStore", writeZip(zip.Store), readZip},
+	{"ZipDeflate", writeZip(zip.Deflate), readZip},
+}
+
+// BenchmarkArchive measures creating and extracting archives of
+// archiveCorpus in memory with archive/tar and archive/zip, stored and
+// deflate-compressed (tar through compress/gzip), as deploy and CI tools do.
+// MB/s is corpus bytes; archive-B is the size of the archive.
+func BenchmarkArchive(b *testing.B) {
+	for _, f := range archiveFormats {
+		b.Run("Create/"+f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(archiveCorpusBytes)
+			var buf bytes.Buffer
+			for b.Loop() {
+				buf.Reset()
+				if err := f.write(&buf, archiveCorpus); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(buf.Len()), "archive-B")
+		})
+	}
+
+	for _, f := range archiveFormats {
+		b.Run("Extract/"+f.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			if err := f.write(&buf, archiveCorpus); err != nil {
+				b.Fatal(err)
+			}
+			data := buf.Bytes()
+			b.ReportAllocs()
+			b.SetBytes(archiveCorpusBytes)
+			for b.Loop() {
+				n, err := f.read(data)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if n != archiveCorpusBytes {
+					b.Fatalf("extracted %d bytes, want %d", n, archiveCorpusBytes)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "archive-B")
+		})
+	}
+}
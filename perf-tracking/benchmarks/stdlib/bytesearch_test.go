@@ -0,0 +1,113 @@
+package stdlib
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// Needles for BenchmarkBytesSearch. The haystack is lowercase letters
+// without 'z', so the rare needle's first byte never occurs before the
+// match, while the common one starts with a letter found every few bytes,
+// which sends bytes.Index down its verify-and-retry path.
+var (
+	searchNeedleRare   = []byte("zq-boundary")
+	searchNeedleCommon = []byte("end-of-record")
+)
+
+var sinkIndex int
+
+// searchHaystack returns size bytes of lowercase text (no 'z') with needle
+// placed near the start (early) or the end (late), and the bytes a search
+// has to scan to find it.
+func searchHaystack(size int, needle []byte, late bool) ([]byte, int) {
+	rng := rand.New(rand.NewPCG(11, 12))
+	h := make([]byte, size)
+	for i := range h {
+		h[i] = 'a' + byte(rng.IntN(25))
+	}
+	pos := size / 100
+	if late {
+		pos = size - len(needle) - size/100
+	}
+	copy(h[pos:], needle)
+	return h, pos + len(needle)
+}
+
+// BenchmarkBytesSearch measures bytes.Index, bytes.Contains and
+// bytes.IndexByte on a 64B and a 4MB haystack with the match 1% in (Early)
+// or 99% in (Late). These primitives have per-architecture assembly that
+// changes between releases and sit under most parsers' delimiter scans.
+// MB/s counts the bytes scanned up to the end of the match.
+func BenchmarkBytesSearch(b *testing.B) {
+	sizes := []struct {
+		name string
+		size int
+	}{
+		{"Size64B", 64},
+		{"Size4MB", 4 << 20},
+	}
+	positions := []struct {
+		name string
+		late bool
+	}{
+		{"Early", false},
+		{"Late", true},
+	}
+	needles := []struct {
+		name   string
+		needle []byte
+	}{
+		{"Rare", searchNeedleRare},
+		{"Common", searchNeedleCommon},
+	}
+	funcs := []struct {
+		name string
+		find func(h, needle []byte) int
+	}{
+		{"Index", bytes.Index},
+		{"Contains", func(h, needle []byte) int {
+			if bytes.Contains(h, needle) {
+				return 1
+			}
+			return -1
+		}},
+	}
+
+	for _, fn := range funcs {
+		for _, n := range needles {
+			for _, s := range sizes {
+				for _, p := range positions {
+					b.Run(fmt.Sprintf("%s/%s/%s/%s", fn.name, n.name, s.name, p.name), func(b *testing.B) {
+						h, scanned := searchHaystack(s.size, n.needle, p.late)
+						b.SetBytes(int64(scanned))
+						b.ReportAllocs()
+						for b.Loop() {
+							sinkIndex = fn.find(h, n.needle)
+						}
+						if sinkIndex < 0 {
+							b.Fatal("needle not found")
+						}
+					})
+				}
+			}
+		}
+	}
+
+	for _, s := range sizes {
+		for _, p := range positions {
+			b.Run(fmt.Sprintf("IndexByte/%s/%s", s.name, p.name), func(b *testing.B) {
+				h, scanned := searchHaystack(s.size, []byte{'z'}, p.late)
+				b.SetBytes(int64(scanned))
+				b.ReportAllocs()
+				for b.Loop() {
+					sinkIndex = bytes.IndexByte(h, 'z')
+				}
+				if sinkIndex != scanned-1 {
+					b.Fatalf("IndexByte = %d, want %d", sinkIndex, scanned-1)
+				}
+			})
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package stdlib
 
 import (
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"math/rand/v2"
 	"testing"
 )
 
@@ -194,3 +197,97 @@ func (w *bytesWriter) Write(p []byte) (n int, err error) {
 	w.buf = append(w.buf, p...)
 	return len(p), nil
 }
+
+// textEncodingSizes are the payload sizes of BenchmarkBase64 and
+// BenchmarkHex: a token, a small blob, a large attachment
+var textEncodingSizes = func() []struct {
+	name string
+	data []byte
+} {
+	rng := rand.New(rand.NewPCG(13, 14))
+	data := make([]byte, 64<<10)
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+	return []struct {
+		name string
+		data []byte
+	}{
+		{"Size64B", data[:64]},
+		{"Size1KB", data[:1<<10]},
+		{"Size64KB", data},
+	}
+}()
+
+// BenchmarkBase64 measures encoding/base64 encoding and decoding into
+// preallocated buffers for the standard and URL alphabets, with and without
+// padding. MB/s is raw (decoded) bytes.
+func BenchmarkBase64(b *testing.B) {
+	encodings := []struct {
+		name string
+		enc  *base64.Encoding
+	}{
+		{"Std", base64.StdEncoding},
+		{"URL", base64.URLEncoding},
+		{"RawStd", base64.RawStdEncoding},
+		{"RawURL", base64.RawURLEncoding},
+	}
+
+	for _, e := range encodings {
+		for _, s := range textEncodingSizes {
+			b.Run("Encode/"+e.name+"/"+s.name, func(b *testing.B) {
+				dst := make([]byte, e.enc.EncodedLen(len(s.data)))
+				b.SetBytes(int64(len(s.data)))
+				b.ReportAllocs()
+				for b.Loop() {
+					e.enc.Encode(dst, s.data)
+				}
+			})
+		}
+	}
+
+	for _, e := range encodings {
+		for _, s := range textEncodingSizes {
+			b.Run("Decode/"+e.name+"/"+s.name, func(b *testing.B) {
+				src := []byte(e.enc.EncodeToString(s.data))
+				dst := make([]byte, e.enc.DecodedLen(len(src)))
+				b.SetBytes(int64(len(s.data)))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := e.enc.Decode(dst, src); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkHex measures encoding/hex encoding and decoding into
+// preallocated buffers. MB/s is raw (decoded) bytes.
+func BenchmarkHex(b *testing.B) {
+	for _, s := range textEncodingSizes {
+		b.Run("Encode/"+s.name, func(b *testing.B) {
+			dst := make([]byte, hex.EncodedLen(len(s.data)))
+			b.SetBytes(int64(len(s.data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				hex.Encode(dst, s.data)
+			}
+		})
+	}
+
+	for _, s := range textEncodingSizes {
+		b.Run("Decode/"+s.name, func(b *testing.B) {
+			src := []byte(hex.EncodeToString(s.data))
+			dst := make([]byte, hex.DecodedLen(len(src)))
+			b.SetBytes(int64(len(s.data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := hex.Decode(dst, src); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
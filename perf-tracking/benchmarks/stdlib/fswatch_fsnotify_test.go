@@ -0,0 +1,46 @@
+//go:build fsnotify
+
+package stdlib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func init() {
+	watchNotify = benchmarkFsnotify
+}
+
+// benchmarkFsnotify watches dir (one watch covers all files, as config
+// watchers do) and waits for the write event after each touch.
+func benchmarkFsnotify(b *testing.B, dir string, files []string) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer func() { _ = w.Close() }()
+	if err := w.Add(dir); err != nil {
+		b.Fatal(err)
+	}
+
+	n := len(files)
+	var i int
+	for b.Loop() {
+		want := files[i%n]
+		touchFile(b, want)
+		// Events for the same file may be coalesced or split; wait for ours
+		for detected := false; !detected; {
+			select {
+			case ev := <-w.Events:
+				detected = ev.Name == want && ev.Has(fsnotify.Write)
+			case err := <-w.Errors:
+				b.Fatal(err)
+			case <-time.After(5 * time.Second):
+				b.Fatalf("no event for %s", want)
+			}
+		}
+		i++
+	}
+}
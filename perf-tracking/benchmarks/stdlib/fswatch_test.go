@@ -0,0 +1,94 @@
+package stdlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// watchNotify, when set by fswatch_fsnotify_test.go (-tags fsnotify),
+// benchmarks detecting a change to one of files through a platform watcher.
+var watchNotify func(b *testing.B, dir string, files []string)
+
+// watchFiles creates n small files in a temp dir and returns their paths.
+func watchFiles(b *testing.B, n int) (string, []string) {
+	b.Helper()
+	dir := b.TempDir()
+	files := make([]string, n)
+	for i := range files {
+		files[i] = filepath.Join(dir, fmt.Sprintf("config-%04d.yaml", i))
+		if err := os.WriteFile(files[i], []byte("key: value\n"), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return dir, files
+}
+
+// touchFile appends a byte so the change is visible through the size even
+// on filesystems with coarse mtime granularity.
+func touchFile(b *testing.B, path string) {
+	b.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := f.Write([]byte{'\n'}); err != nil {
+		b.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkFileWatch measures detecting a change to one of N files, the
+// pattern behind hot-reload and config-watch loops. Each iteration modifies
+// one file and waits until it is detected.
+//
+// Poll sweeps every file with os.Stat, so its cost grows with N; Notify
+// (-tags fsnotify) waits for an inotify/kqueue/ReadDirectoryChangesW event.
+func BenchmarkFileWatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("Poll/Files%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			_, files := watchFiles(b, n)
+			sizes := make([]int64, n)
+			for i, f := range files {
+				info, err := os.Stat(f)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sizes[i] = info.Size()
+			}
+
+			var i int
+			for b.Loop() {
+				touchFile(b, files[i%n])
+				changed := -1
+				for j, f := range files {
+					info, err := os.Stat(f)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if info.Size() != sizes[j] {
+						sizes[j] = info.Size()
+						changed = j
+					}
+				}
+				if changed != i%n {
+					b.Fatalf("detected change in file %d, want %d", changed, i%n)
+				}
+				i++
+			}
+		})
+
+		b.Run(fmt.Sprintf("Notify/Files%d", n), func(b *testing.B) {
+			if watchNotify == nil {
+				b.Skip("platform watcher benchmarks need -tags fsnotify")
+			}
+			b.ReportAllocs()
+			dir, files := watchFiles(b, n)
+			watchNotify(b, dir, files)
+		})
+	}
+}
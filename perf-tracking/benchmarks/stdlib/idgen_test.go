@@ -0,0 +1,177 @@
+package stdlib
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	mrand "math/rand/v2"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var sinkID string
+
+// formatUUID sets the version 4 and variant bits of u and formats it as
+// the canonical 36-character string
+func formatUUID(u [16]byte) string {
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// uuidCryptoRand reads every UUID from crypto/rand, as
+// github.com/google/uuid.New does by default. crypto/rand.Read never
+// returns an error since Go 1.24.
+func uuidCryptoRand() string {
+	var u [16]byte
+	_, _ = crand.Read(u[:])
+	return formatUUID(u)
+}
+
+// uuidPool amortizes crypto/rand over 256 UUIDs behind a mutex, as
+// github.com/google/uuid.EnableRandPool does
+type uuidPool struct {
+	mu  sync.Mutex
+	buf [16 * 256]byte
+	pos int
+}
+
+func newUUIDPool() *uuidPool {
+	return &uuidPool{pos: len(uuidPool{}.buf)}
+}
+
+func (p *uuidPool) next() string {
+	var u [16]byte
+	p.mu.Lock()
+	if p.pos == len(p.buf) {
+		_, _ = crand.Read(p.buf[:])
+		p.pos = 0
+	}
+	copy(u[:], p.buf[p.pos:])
+	p.pos += len(u)
+	p.mu.Unlock()
+	return formatUUID(u)
+}
+
+// crockford is the Crockford base32 alphabet ULIDs are written in
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidCryptoRand returns a ULID: a 48-bit millisecond timestamp followed by
+// 80 bits from crypto/rand, as 26 base32 characters that sort by time
+func ulidCryptoRand() string {
+	var id [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	for i := range 6 {
+		id[i] = byte(ms >> (40 - 8*i))
+	}
+	_, _ = crand.Read(id[6:])
+
+	// 128 bits in 26 characters: the first carries the top 3 bits, every
+	// following one 5 bits
+	hi := uint64(id[0])<<56 | uint64(id[1])<<48 | uint64(id[2])<<40 | uint64(id[3])<<32 |
+		uint64(id[4])<<24 | uint64(id[5])<<16 | uint64(id[6])<<8 | uint64(id[7])
+	lo := uint64(id[8])<<56 | uint64(id[9])<<48 | uint64(id[10])<<40 | uint64(id[11])<<32 |
+		uint64(id[12])<<24 | uint64(id[13])<<16 | uint64(id[14])<<8 | uint64(id[15])
+	var buf [26]byte
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+// idGenerators are the ID schemes of BenchmarkIDGen. Each new returns the
+// generator one goroutine uses, so per-goroutine state stays unshared while
+// shared state (the pool) is created once per benchmark by the caller.
+var idGenerators = []struct {
+	name string
+	new  func(pool *uuidPool) func() string
+}{
+	{"UUIDv4CryptoRand", func(*uuidPool) func() string { return uuidCryptoRand }},
+	{"UUIDv4CryptoRandPool", func(pool *uuidPool) func() string { return pool.next }},
+	{"ULIDCryptoRand", func(*uuidPool) func() string { return ulidCryptoRand }},
+	// crypto/rand.Text: 26 base32 characters, 130 random bits
+	{"CryptoRandText", func(*uuidPool) func() string { return crand.Text }},
+	// Runtime-seeded global math/rand/v2 source: fast, not unpredictable
+	{"UUIDv4MathRand", func(*uuidPool) func() string {
+		return func() string {
+			var u [16]byte
+			hi, lo := mrand.Uint64(), mrand.Uint64()
+			for i := range 8 {
+				u[i], u[8+i] = byte(hi>>(8*i)), byte(lo>>(8*i))
+			}
+			return formatUUID(u)
+		}
+	}},
+	// A ChaCha8 source per goroutine seeded from crypto/rand
+	{"UUIDv4ChaCha8", func(*uuidPool) func() string {
+		var seed [32]byte
+		_, _ = crand.Read(seed[:])
+		src := mrand.NewChaCha8(seed)
+		return func() string {
+			var u [16]byte
+			_, _ = src.Read(u[:])
+			return formatUUID(u)
+		}
+	}},
+}
+
+// BenchmarkIDGen measures generating string request and entity IDs: UUIDv4
+// from crypto/rand per ID and from a pooled buffer, time-ordered ULIDs,
+// crypto/rand.Text, and UUIDv4 from math/rand/v2, serially and from
+// GOMAXPROCS goroutines, where shared state like the pool's mutex contends.
+func BenchmarkIDGen(b *testing.B) {
+	for _, g := range idGenerators {
+		b.Run("Serial/"+g.name, func(b *testing.B) {
+			gen := g.new(newUUIDPool())
+			b.ReportAllocs()
+			for b.Loop() {
+				sinkID = gen()
+			}
+		})
+	}
+
+	for _, g := range idGenerators {
+		b.Run("Parallel/"+g.name, func(b *testing.B) {
+			pool := newUUIDPool()
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				gen := g.new(pool)
+				for pb.Next() {
+					_ = gen()
+				}
+			})
+		})
+	}
+}
+
+func TestIDGenFormats(t *testing.T) {
+	for _, g := range idGenerators {
+		id := g.new(newUUIDPool())()
+		if strings.HasPrefix(g.name, "UUIDv4") {
+			if len(id) != 36 || id[14] != '4' || id[8] != '-' || id[23] != '-' || !strings.ContainsRune("89ab", rune(id[19])) {
+				t.Errorf("%s: %q is not a version 4 UUID", g.name, id)
+			}
+		} else if len(id) != 26 {
+			t.Errorf("%s: %q has %d characters, want 26", g.name, id, len(id))
+		}
+	}
+	// The ULID timestamp is the leading 10 characters, in time order
+	a := ulidCryptoRand()
+	time.Sleep(2 * time.Millisecond)
+	if b := ulidCryptoRand(); a[:10] >= b[:10] {
+		t.Errorf("ULID %s not before %s", a, b)
+	}
+}
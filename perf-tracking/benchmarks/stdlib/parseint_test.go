@@ -0,0 +1,141 @@
+package stdlib
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"unsafe"
+)
+
+var (
+	sinkInt64 int64
+	sinkStr   string
+)
+
+// parseIntFields are 1000 decimal fields as a log or CSV parser slices them
+// out of a line: Short ones (1-4 digits, counters and status codes) and
+// Long ones (16-18 digits, timestamps in ns and ids), some negative
+var parseIntFields = func() map[string][][]byte {
+	rng := rand.New(rand.NewPCG(15, 16))
+	fields := func(minDigits, maxDigits int) [][]byte {
+		out := make([][]byte, 1000)
+		for i := range out {
+			digits := minDigits + rng.IntN(maxDigits-minDigits+1)
+			n := int64(1 + rng.IntN(9))
+			for range digits - 1 {
+				n = n*10 + int64(rng.IntN(10))
+			}
+			if rng.IntN(4) == 0 {
+				n = -n
+			}
+			out[i] = strconv.AppendInt(nil, n, 10)
+		}
+		return out
+	}
+	return map[string][][]byte{
+		"Short": fields(1, 4),
+		"Long":  fields(16, 18),
+	}
+}()
+
+var errParseBytes = errors.New("invalid integer")
+
+// parseIntBytes parses a base-10 int64 straight from b, with the overflow
+// and syntax checks strconv.ParseInt does but without building a string
+func parseIntBytes(b []byte) (int64, error) {
+	neg := false
+	if len(b) > 0 && (b[0] == '-' || b[0] == '+') {
+		neg = b[0] == '-'
+		b = b[1:]
+	}
+	if len(b) == 0 {
+		return 0, errParseBytes
+	}
+	var n uint64
+	for _, c := range b {
+		d := c - '0'
+		if d > 9 {
+			return 0, errParseBytes
+		}
+		if n > (1<<63)/10 {
+			return 0, errParseBytes
+		}
+		n = n*10 + uint64(d)
+	}
+	if neg {
+		if n > 1<<63 {
+			return 0, errParseBytes
+		}
+		return -int64(n), nil
+	}
+	if n > 1<<63-1 {
+		return 0, errParseBytes
+	}
+	return int64(n), nil
+}
+
+// BenchmarkParseIntBytes measures parsing integers held in []byte, a hot
+// path of log, CSV and wire-format parsers:
+//   - StringConv: strconv.ParseInt(string(b)); the conversion does not
+//     escape, so the compiler copies it to a stack buffer instead of the heap
+//   - StringHeap: the same with the string kept, which defeats that
+//     optimization and allocates per field
+//   - UnsafeString: strconv.ParseInt on an unsafe.String view, no copy
+//   - Manual: parseIntBytes, a loop over the bytes
+//
+// Each op parses 1000 fields; MB/s counts their bytes.
+func BenchmarkParseIntBytes(b *testing.B) {
+	parsers := []struct {
+		name  string
+		parse func([]byte) (int64, error)
+	}{
+		{"StringConv", func(f []byte) (int64, error) {
+			return strconv.ParseInt(string(f), 10, 64)
+		}},
+		{"StringHeap", func(f []byte) (int64, error) {
+			sinkStr = string(f)
+			return strconv.ParseInt(sinkStr, 10, 64)
+		}},
+		{"UnsafeString", func(f []byte) (int64, error) {
+			return strconv.ParseInt(unsafe.String(unsafe.SliceData(f), len(f)), 10, 64)
+		}},
+		{"Manual", parseIntBytes},
+	}
+
+	for _, size := range []string{"Short", "Long"} {
+		fields := parseIntFields[size]
+		var total int64
+		for _, f := range fields {
+			total += int64(len(f))
+		}
+		for _, p := range parsers {
+			b.Run(size+"/"+p.name, func(b *testing.B) {
+				b.SetBytes(total)
+				b.ReportAllocs()
+				for b.Loop() {
+					var sum int64
+					for _, f := range fields {
+						n, err := p.parse(f)
+						if err != nil {
+							b.Fatal(err)
+						}
+						sum += n
+					}
+					sinkInt64 = sum
+				}
+			})
+		}
+	}
+}
+
+func TestParseIntBytes(t *testing.T) {
+	for _, s := range []string{"0", "-0", "+42", "-17", "9223372036854775807", "-9223372036854775808",
+		"9223372036854775808", "-9223372036854775809", "99999999999999999999", "", "-", "12a", " 1"} {
+		want, wantErr := strconv.ParseInt(s, 10, 64)
+		got, err := parseIntBytes([]byte(s))
+		if (err != nil) != (wantErr != nil) || err == nil && got != want {
+			t.Errorf("parseIntBytes(%q) = %d, %v; want %d, %v", s, got, err, want, wantErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//go:build linux && iouring
+
+package stdlib
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/pawelgaczynski/giouring"
+)
+
+// giouring unmaps its rings through a linkname to syscall.munmap, which
+// the linker rejects since Go 1.23 unless the check is off:
+//
+//	go test -tags iouring -ldflags=-checklinkname=0 -bench RandomRead ./stdlib
+func init() {
+	randomReadRing = benchmarkIOUring
+}
+
+// benchmarkIOUring keeps depth reads in flight on one ring, driven without
+// SQPOLL: each submit hands the kernel the reads queued to refill the slots
+// freed by the completions reaped before it, and waits for at least one
+// more, so every batch costs one io_uring_enter.
+func benchmarkIOUring(b *testing.B, f *os.File, offsets []int64, depth int) {
+	ring, err := giouring.CreateRing(uint32(depth))
+	if errors.Is(err, syscall.ENOSYS) || errors.Is(err, syscall.EPERM) {
+		b.Skipf("io_uring unavailable: %v", err)
+	}
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer ring.QueueExit()
+
+	fd := int(f.Fd())
+	bufs := make([][]byte, depth)
+	for i := range bufs {
+		bufs[i] = make([]byte, randReadBlockSize)
+	}
+
+	next, completed := 0, 0
+	queue := func(slot int) {
+		// No more reads are queued or in flight than the ring has entries
+		sqe := ring.GetSQE()
+		sqe.PrepareRead(fd, uintptr(unsafe.Pointer(&bufs[slot][0])), randReadBlockSize, uint64(offsets[next%randReadOffsets]))
+		sqe.UserData = uint64(slot)
+		next++
+	}
+	b.ResetTimer()
+	for slot := 0; slot < depth && next < b.N; slot++ {
+		queue(slot)
+	}
+	for completed < b.N {
+		if _, err := ring.SubmitAndWait(1); err != nil {
+			if errors.Is(err, syscall.EINTR) {
+				continue
+			}
+			b.Fatal(err)
+		}
+		reaped := uint32(0)
+		ring.ForEachCQE(func(cqe *giouring.CompletionQueueEvent) {
+			reaped++
+			if cqe.Res != randReadBlockSize {
+				b.Fatalf("read returned %d, want %d", cqe.Res, randReadBlockSize)
+			}
+			if next < b.N {
+				queue(int(cqe.UserData))
+			}
+		})
+		ring.CQAdvance(reaped)
+		completed += int(reaped)
+	}
+	b.StopTimer()
+	// The kernel writes into bufs until the last completion
+	runtime.KeepAlive(bufs)
+}
@@ -0,0 +1,98 @@
+package stdlib
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	randReadFileSize  = 64 << 20
+	randReadBlockSize = 4 << 10
+	randReadOffsets   = 64 << 10
+)
+
+// randomReadRing, when set by randread_iouring_test.go (-tags iouring on
+// Linux), reads blocks of f at offsets through io_uring, keeping depth reads
+// in flight, until b.N reads have completed.
+var randomReadRing func(b *testing.B, f *os.File, offsets []int64, depth int)
+
+// randomReadFile writes a randReadFileSize file to a temp dir and returns it
+// opened along with randReadOffsets random block-aligned offsets into it.
+func randomReadFile(b *testing.B) (*os.File, []int64) {
+	b.Helper()
+	path := filepath.Join(b.TempDir(), "data.bin")
+	data := make([]byte, randReadFileSize)
+	_, _ = rand.NewChaCha8([32]byte{7}).Read(data)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		b.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = f.Close() })
+
+	rng := rand.New(rand.NewPCG(7, 8))
+	offsets := make([]int64, randReadOffsets)
+	for i := range offsets {
+		offsets[i] = int64(rng.IntN(randReadFileSize/randReadBlockSize)) * randReadBlockSize
+	}
+	return f, offsets
+}
+
+// BenchmarkRandomRead measures random 4KB reads from a 64MB file at queue
+// depths of 1, 16 and 128, one op per read.
+//
+// Pread runs depth goroutines each calling ReadAt (pread), the usual Go
+// approach: the runtime parks a thread per blocking read. IOUring (-tags
+// iouring, Linux only) submits the reads to one io_uring and reaps their
+// completions, with no extra threads. The file is read through the page
+// cache, so the comparison is of per-read overhead rather than device
+// latency.
+func BenchmarkRandomRead(b *testing.B) {
+	f, offsets := randomReadFile(b)
+	for _, depth := range []int{1, 16, 128} {
+		b.Run(fmt.Sprintf("QD%d/Pread", depth), func(b *testing.B) {
+			b.SetBytes(randReadBlockSize)
+			b.ReportAllocs()
+
+			var (
+				next atomic.Int64
+				wg   sync.WaitGroup
+			)
+			b.ResetTimer()
+			for range depth {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					buf := make([]byte, randReadBlockSize)
+					for {
+						i := next.Add(1)
+						if i > int64(b.N) {
+							return
+						}
+						if _, err := f.ReadAt(buf, offsets[i%randReadOffsets]); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+
+		b.Run(fmt.Sprintf("QD%d/IOUring", depth), func(b *testing.B) {
+			if randomReadRing == nil {
+				b.Skip("io_uring benchmarks need -tags iouring -ldflags=-checklinkname=0 on Linux")
+			}
+			b.SetBytes(randReadBlockSize)
+			b.ReportAllocs()
+			randomReadRing(b, f, offsets, depth)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package stdlib
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// copyAddress and copyCustomer form a medium DTO of about 250B: scalars,
+// strings, timestamps and a nested struct, but nothing a copy could share
+// except immutable string data, so assignment is already a deep copy
+type copyAddress struct {
+	Street  string
+	City    string
+	Zip     string
+	Country string
+}
+
+type copyCustomer struct {
+	ID        int64
+	AccountID int64
+	Name      string
+	Email     string
+	Phone     string
+	Status    string
+	Age       int
+	Score     float64
+	Active    bool
+	Verified  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Address   copyAddress
+}
+
+type copyLineItem struct {
+	SKU      string
+	Name     string
+	Quantity int
+	Price    float64
+	Discount float64
+}
+
+// copyOrder is a large DTO: the customer plus 32 line items, tags, string
+// attributes and an optional address. Assignment copies only the slice,
+// map and pointer headers, so the copy shares them with the original.
+type copyOrder struct {
+	Customer copyCustomer
+	Items    []copyLineItem
+	Tags     []string
+	Attrs    map[string]string
+	Shipping *copyAddress
+}
+
+func newCopyCustomer() copyCustomer {
+	created := time.Date(2024, 3, 14, 9, 26, 53, 0, time.UTC)
+	return copyCustomer{
+		ID:        424242,
+		AccountID: 1001,
+		Name:      "Ada Lovelace",
+		Email:     "ada@example.com",
+		Phone:     "+44 20 7946 0958",
+		Status:    "active",
+		Age:       36,
+		Score:     97.5,
+		Active:    true,
+		Verified:  true,
+		CreatedAt: created,
+		UpdatedAt: created.Add(90 * 24 * time.Hour),
+		Address:   copyAddress{"12 St James's Square", "London", "SW1Y 4LB", "UK"},
+	}
+}
+
+func newCopyOrder() copyOrder {
+	o := copyOrder{
+		Customer: newCopyCustomer(),
+		Items:    make([]copyLineItem, 32),
+		Tags:     []string{"priority", "gift", "fragile", "b2b", "eu", "repeat", "promo", "net30"},
+		Attrs:    make(map[string]string, 16),
+		Shipping: &copyAddress{"221B Baker Street", "London", "NW1 6XE", "UK"},
+	}
+	for i := range o.Items {
+		o.Items[i] = copyLineItem{
+			SKU:      fmt.Sprintf("SKU-%05d", i*37),
+			Name:     fmt.Sprintf("Item %d", i),
+			Quantity: 1 + i%5,
+			Price:    9.99 + float64(i),
+			Discount: float64(i%3) * 0.05,
+		}
+	}
+	for i := range 16 {
+		o.Attrs[fmt.Sprintf("attr-%02d", i)] = fmt.Sprintf("value-%02d", i)
+	}
+	return o
+}
+
+// copyCustomerFields and copyOrderFields copy field by field, the code a
+// deep-copy generator such as deepcopy-gen emits: slices and maps are
+// re-made and filled, pointers re-allocated
+func copyCustomerFields(dst, src *copyCustomer) error {
+	*dst = copyCustomer{
+		ID:        src.ID,
+		AccountID: src.AccountID,
+		Name:      src.Name,
+		Email:     src.Email,
+		Phone:     src.Phone,
+		Status:    src.Status,
+		Age:       src.Age,
+		Score:     src.Score,
+		Active:    src.Active,
+		Verified:  src.Verified,
+		CreatedAt: src.CreatedAt,
+		UpdatedAt: src.UpdatedAt,
+		Address: copyAddress{
+			Street:  src.Address.Street,
+			City:    src.Address.City,
+			Zip:     src.Address.Zip,
+			Country: src.Address.Country,
+		},
+	}
+	return nil
+}
+
+func copyOrderFields(dst, src *copyOrder) error {
+	_ = copyCustomerFields(&dst.Customer, &src.Customer)
+	dst.Items = nil
+	if src.Items != nil {
+		dst.Items = make([]copyLineItem, len(src.Items))
+		copy(dst.Items, src.Items)
+	}
+	dst.Tags = nil
+	if src.Tags != nil {
+		dst.Tags = make([]string, len(src.Tags))
+		copy(dst.Tags, src.Tags)
+	}
+	dst.Attrs = nil
+	if src.Attrs != nil {
+		dst.Attrs = make(map[string]string, len(src.Attrs))
+		for k, v := range src.Attrs {
+			dst.Attrs[k] = v
+		}
+	}
+	dst.Shipping = nil
+	if src.Shipping != nil {
+		shipping := *src.Shipping
+		dst.Shipping = &shipping
+	}
+	return nil
+}
+
+// reflectCopy deep-copies src into dst, which must be settable, the way
+// reflection-based cloning libraries walk a value. Structs with unexported
+// fields, such as time.Time, are opaque and copied by assignment.
+func reflectCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		t := src.Type()
+		for i := range t.NumField() {
+			if !t.Field(i).IsExported() {
+				dst.Set(src)
+				return
+			}
+		}
+		for i := range t.NumField() {
+			reflectCopy(dst.Field(i), src.Field(i))
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.SetZero()
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			reflectCopy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Map:
+		if src.IsNil() {
+			dst.SetZero()
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			reflectCopy(v, iter.Value())
+			m.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(m)
+	case reflect.Pointer:
+		if src.IsNil() {
+			dst.SetZero()
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		reflectCopy(p.Elem(), src.Elem())
+		dst.Set(p)
+	default:
+		dst.Set(src)
+	}
+}
+
+// structCopier copies src into dst, which may hold a previous copy
+type structCopier[T any] struct {
+	name string
+	copy func(dst, src *T) error
+}
+
+// structCopiers lists the ways to copy a T, with fieldByField as the
+// hand-written or generated copier
+func structCopiers[T any](fieldByField func(dst, src *T) error) []structCopier[T] {
+	return []structCopier[T]{
+		{"Assign", func(dst, src *T) error {
+			*dst = *src
+			return nil
+		}},
+		{"FieldByField", fieldByField},
+		{"Reflect", func(dst, src *T) error {
+			reflectCopy(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem())
+			return nil
+		}},
+		{"Gob", func(dst, src *T) error {
+			// A fresh encoder per copy, as gob-based clone helpers do: a
+			// reused stream would send the type descriptors only once
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(src); err != nil {
+				return err
+			}
+			*dst = *new(T) // decoding merges into maps and reuses slices
+			return gob.NewDecoder(&buf).Decode(dst)
+		}},
+		{"JSON", func(dst, src *T) error {
+			data, err := json.Marshal(src)
+			if err != nil {
+				return err
+			}
+			*dst = *new(T)
+			return json.Unmarshal(data, dst)
+		}},
+	}
+}
+
+// BenchmarkStructCopy measures copying DTOs, a recurring cost in code that
+// maps between API, domain and storage types:
+//   - Assign: *dst = *src, a memmove; a shallow copy for Large
+//   - FieldByField: copyCustomerFields/copyOrderFields, as generated
+//     deep copiers are written
+//   - Reflect: reflectCopy, a generic reflection-based deep copy
+//   - Gob, JSON: an encode/decode round trip, the shortcut for deep copies
+//
+// Medium is copyCustomer (no slices, maps or pointers), Large is copyOrder.
+// Allocation counts show what each deep copy costs beyond the memmove.
+func BenchmarkStructCopy(b *testing.B) {
+	customer := newCopyCustomer()
+	order := newCopyOrder()
+	b.Run("Medium", func(b *testing.B) {
+		benchStructCopy(b, &customer, structCopiers(copyCustomerFields))
+	})
+	b.Run("Large", func(b *testing.B) {
+		benchStructCopy(b, &order, structCopiers(copyOrderFields))
+	})
+}
+
+func benchStructCopy[T any](b *testing.B, src *T, copiers []structCopier[T]) {
+	for _, c := range copiers {
+		b.Run(c.name, func(b *testing.B) {
+			dst := new(T)
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := c.copy(dst, src); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestStructCopyDeep(t *testing.T) {
+	customer := newCopyCustomer()
+	for _, c := range structCopiers(copyCustomerFields) {
+		var dst copyCustomer
+		if err := c.copy(&dst, &customer); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !reflect.DeepEqual(dst, customer) {
+			t.Errorf("%s: copy differs:\n got %+v\nwant %+v", c.name, dst, customer)
+		}
+	}
+
+	for _, c := range structCopiers(copyOrderFields) {
+		src := newCopyOrder()
+		dst := copyOrder{Attrs: map[string]string{"stale": "from a previous copy"}}
+		if err := c.copy(&dst, &src); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !reflect.DeepEqual(dst, src) {
+			t.Errorf("%s: copy differs:\n got %+v\nwant %+v", c.name, dst, src)
+		}
+		if c.name == "Assign" {
+			continue // shares Items, Tags, Attrs and Shipping by design
+		}
+		dst.Items[0].Quantity = -1
+		dst.Tags[0] = "changed"
+		dst.Attrs["attr-00"] = "changed"
+		dst.Shipping.City = "changed"
+		if want := newCopyOrder(); !reflect.DeepEqual(src, want) {
+			t.Errorf("%s: changing the copy changed the original", c.name)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package stdlib
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -96,3 +98,61 @@ func BenchmarkRegexp(b *testing.B) {
 		})
 	})
 }
+
+var sinkString string
+
+// concatSegmentCounts are the segment counts of BenchmarkStringConcat
+var concatSegmentCounts = []int{2, 4, 16, 256}
+
+// BenchmarkStringConcat builds one string from N 8-byte segments with +=
+// in a loop, a single fmt.Sprintf, a strings.Builder and append to a byte
+// slice, none of them presized. The sweep over N shows where the quadratic
+// copying of += overtakes the fixed overhead of the alternatives.
+func BenchmarkStringConcat(b *testing.B) {
+	for _, n := range concatSegmentCounts {
+		segments := make([]string, n)
+		args := make([]any, n)
+		for i := range segments {
+			segments[i] = fmt.Sprintf("seg%05d", i)
+			args[i] = segments[i]
+		}
+		format := strings.Repeat("%s", n)
+
+		b.Run(fmt.Sprintf("Plus/Segments%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var s string
+				for _, seg := range segments {
+					s += seg
+				}
+				sinkString = s
+			}
+		})
+		b.Run(fmt.Sprintf("Sprintf/Segments%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				sinkString = fmt.Sprintf(format, args...)
+			}
+		})
+		b.Run(fmt.Sprintf("Builder/Segments%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var sb strings.Builder
+				for _, seg := range segments {
+					sb.WriteString(seg)
+				}
+				sinkString = sb.String()
+			}
+		})
+		b.Run(fmt.Sprintf("Append/Segments%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var buf []byte
+				for _, seg := range segments {
+					buf = append(buf, seg...)
+				}
+				sinkString = string(buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package stdlib
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+const x509LeafHost = "bench.example.com"
+
+// x509Chain is a root -> intermediate -> leaf chain, as a TLS client sees it:
+// the root in its trust store, the leaf and intermediate as DER from the peer.
+type x509Chain struct {
+	roots           *x509.CertPool
+	leafDER         []byte
+	intermediateDER []byte
+}
+
+// newX509Chain generates a 3-level ECDSA P-256 chain for x509LeafHost.
+func newX509Chain(b *testing.B) *x509Chain {
+	b.Helper()
+	now := time.Now()
+	issue := func(serial int64, tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		tmpl.SerialNumber = big.NewInt(serial)
+		tmpl.NotBefore = now.Add(-time.Hour)
+		tmpl.NotAfter = now.Add(24 * time.Hour)
+		if parent == nil {
+			parent, parentKey = tmpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			b.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return cert, key
+	}
+
+	ca := func(name string) *x509.Certificate {
+		return &x509.Certificate{
+			Subject:               pkix.Name{CommonName: name},
+			KeyUsage:              x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+	}
+	root, rootKey := issue(1, ca("Benchmark Root CA"), nil, nil)
+	intermediate, intermediateKey := issue(2, ca("Benchmark Intermediate CA"), root, rootKey)
+	leaf, _ := issue(3, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: x509LeafHost},
+		DNSNames:    []string{x509LeafHost},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, intermediate, intermediateKey)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	return &x509Chain{roots: roots, leafDER: leaf.Raw, intermediateDER: intermediate.Raw}
+}
+
+// BenchmarkX509Verify measures certificate chain verification, which every
+// full TLS handshake pays on the client (and on the server with client auth).
+// Go has rewritten parts of the verifier several times (chain building,
+// platform verifiers, name constraints).
+//
+// IntermediatesCached verifies against pools built once. IntermediatesPerVerify
+// parses the peer's DER and builds a fresh intermediates pool each time, as
+// crypto/tls does per connection. Hostname adds DNSName checking to Verify;
+// VerifyHostname measures the name check alone.
+func BenchmarkX509Verify(b *testing.B) {
+	chain := newX509Chain(b)
+	leaf, err := x509.ParseCertificate(chain.leafDER)
+	if err != nil {
+		b.Fatal(err)
+	}
+	intermediate, err := x509.ParseCertificate(chain.intermediateDER)
+	if err != nil {
+		b.Fatal(err)
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate)
+
+	b.Run("IntermediatesCached", func(b *testing.B) {
+		opts := x509.VerifyOptions{Roots: chain.roots, Intermediates: intermediates}
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := leaf.Verify(opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("IntermediatesPerVerify", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			leaf, err := x509.ParseCertificate(chain.leafDER)
+			if err != nil {
+				b.Fatal(err)
+			}
+			intermediate, err := x509.ParseCertificate(chain.intermediateDER)
+			if err != nil {
+				b.Fatal(err)
+			}
+			pool := x509.NewCertPool()
+			pool.AddCert(intermediate)
+			if _, err := leaf.Verify(x509.VerifyOptions{Roots: chain.roots, Intermediates: pool}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Hostname", func(b *testing.B) {
+		opts := x509.VerifyOptions{
+			Roots:         chain.roots,
+			Intermediates: intermediates,
+			DNSName:       x509LeafHost,
+		}
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := leaf.Verify(opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("VerifyHostname", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if err := leaf.VerifyHostname(x509LeafHost); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,3 @@
+module github.com/astavonin/go-optimization-guide/buildtarget
+
+go 1.24.0
@@ -0,0 +1,108 @@
+// Command buildtarget is the representative module whose build wall time and
+// binary size collect_benchmarks.py records per Go version.
+//
+// It is a small JSON-over-HTTPS service using only the standard library, so
+// the numbers reflect the toolchain (compiler, linker, stdlib size) rather
+// than third-party dependencies. It is built, never run, by the collector.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+type item struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+type store struct {
+	mu    sync.RWMutex
+	items map[int]item
+	next  int
+}
+
+func (s *store) list() []item {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]item, 0, len(s.items))
+	for _, it := range s.items {
+		out = append(out, it)
+	}
+	return out
+}
+
+func (s *store) add(name string) item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	it := item{ID: s.next, Name: name, Created: time.Now()}
+	s.items[it.ID] = it
+	return it
+}
+
+var page = template.Must(template.New("page").Parse(
+	`<ul>{{range .}}<li>{{.ID}}: {{.Name}}</li>{{end}}</ul>`))
+
+func main() {
+	addr := flag.String("addr", ":8443", "listen address")
+	cert := flag.String("cert", "cert.pem", "TLS certificate")
+	key := flag.String("key", "key.pem", "TLS key")
+	flag.Parse()
+
+	log := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	s := &store{items: make(map[int]item)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.list())
+	})
+	mux.HandleFunc("POST /items", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(s.add(req.Name))
+	})
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		_ = page.Execute(w, s.list())
+	})
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("listening", "addr", *addr)
+	if err := srv.ListenAndServeTLS(*cert, *key); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
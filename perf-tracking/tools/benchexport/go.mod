@@ -1,3 +0,0 @@
-module github.com/astavonin/go-optimization-guide/benchexport
-
-go 1.25.5
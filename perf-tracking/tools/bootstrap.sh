@@ -215,8 +215,8 @@ BENCHMARK_RESULT="failed: warmup run"
 
 runuser -l ec2-user -c \
     "cd $REPO_DIR/perf-tracking && \
-     taskset -c 2,3 python3 tools/collect_benchmarks.py \
-         --count 3 --benchtime 1s --skip-system-check \
+     python3 tools/collect_benchmarks.py \
+         --count 3 --benchtime 1s --skip-system-check --pin-cpus 2,3 \
          $GO_VERSIONS"
 echo "✓ Warmup complete"
 
@@ -232,8 +232,8 @@ BENCHMARK_RESULT="failed: benchmark collection"
 
 runuser -l ec2-user -c \
     "cd $REPO_DIR/perf-tracking && \
-     taskset -c 2,3 python3 tools/collect_benchmarks.py \
-         --count 20 --benchtime 3s --skip-system-check --progress \
+     python3 tools/collect_benchmarks.py \
+         --count 20 --benchtime 3s --skip-system-check --progress --pin-cpus 2,3 \
          --max-reruns 3 --rerun-count 40 \
          $GO_VERSIONS"
 
@@ -14,6 +14,7 @@ Features:
 """
 
 import argparse
+import atexit
 import json
 import os
 import re
@@ -27,6 +28,8 @@ import math
 import statistics
 import time
 import shutil
+import tempfile
+import urllib.parse
 
 
 @dataclass
@@ -49,6 +52,34 @@ VARIANCE_ACCEPTABLE = 10.0
 VARIANCE_WARNING = 15.0
 VARIANCE_HIGH = 30.0
 
+# Packages under perf-tracking/benchmarks run on every collection
+DEFAULT_TEST_PACKAGES = ["runtime", "stdlib", "networking", "database"]
+
+# Nice level for --raise-priority
+RAISED_NICE = -10
+
+# Optional stdlib-vs-third-party group, a separate module (--alternatives)
+ALTERNATIVES_PACKAGE = "alternatives"
+
+# Representative module whose cold build time and binary size are recorded
+BUILD_TARGET_MODULE = "buildtarget"
+BUILD_RUNS = 3
+
+# One GODEBUG=gctrace=1 summary line; go test may print it mid-line, right
+# after a benchmark name
+GCTRACE_LINE = re.compile(r'gc \d+ @[\d.]+s \d+%: .*')
+
+# Makes contention benchmarks enable mutex/block profiling and report
+# mutex-contention-ns/op and block-ns/op (--contention-profile)
+CONTENTION_PROFILE_ENV = "BENCH_CONTENTION_PROFILE"
+
+# Selects the size classes BenchmarkSmallAllocSpecialized runs: "all" or a
+# comma-separated list of class sizes in bytes (--size-classes)
+ALLOC_SIZE_CLASSES_ENV = "BENCH_ALLOC_SIZE_CLASSES"
+
+# runtime.MemProfileRate for --memprofile runs: record every allocation
+MEMPROFILE_RATE = 1
+
 
 @dataclass
 class SubprocessResult:
@@ -444,21 +475,757 @@ class StreamingBenchmarkRunner:
             print(f"\r{' ' * 80}\r    [{self.benchmark_count}] {self.current_benchmark[:40]:<40} {status_str}")
 
 
+@dataclass
+class CpuAffinity:
+    """CPU pinning applied to benchmark processes (Linux only)."""
+    method: str                   # "taskset" or "cpuset"
+    cpus: List[int]
+    cgroup: Optional[str] = None  # cgroup v2 directory (cpuset method only)
+    isolated: bool = False        # True if every pinned CPU is in the kernel isolcpus set
+
+    def command_prefix(self) -> List[str]:
+        """Return the command prefix that applies the pinning to a child process.
+
+        The cpuset method moves the runner itself into the cgroup, so children
+        inherit it and no prefix is needed.
+        """
+        if self.method == "taskset":
+            return ["taskset", "-c", format_cpu_list(self.cpus)]
+        return []
+
+    def to_metadata(self) -> dict:
+        """Serialize for the run metadata sidecar."""
+        data = {
+            'method': self.method,
+            'cpus': self.cpus,
+            'isolated': self.isolated,
+        }
+        if self.cgroup:
+            data['cgroup'] = self.cgroup
+        return data
+
+
+def parse_cpu_list(spec: str) -> List[int]:
+    """Parse a Linux CPU list such as "2,3" or "4-7,9" into sorted CPU ids."""
+    cpus = set()
+    for part in spec.strip().split(','):
+        part = part.strip()
+        if not part:
+            continue
+        if '-' in part:
+            lo, hi = part.split('-', 1)
+            start, end = int(lo), int(hi)
+            if end < start:
+                raise ValueError(f"Invalid CPU range: {part}")
+            cpus.update(range(start, end + 1))
+        else:
+            cpus.add(int(part))
+    if not cpus:
+        raise ValueError(f"Empty CPU list: '{spec}'")
+    return sorted(cpus)
+
+
+def format_cpu_list(cpus: List[int]) -> str:
+    """Format CPU ids as a comma-separated list accepted by taskset -c."""
+    return ",".join(str(c) for c in cpus)
+
+
+def read_isolated_cpus() -> List[int]:
+    """Return CPUs isolated from the scheduler via isolcpus= (empty if none/unknown)."""
+    isolated_file = Path("/sys/devices/system/cpu/isolated")
+    try:
+        content = isolated_file.read_text().strip()
+    except OSError:
+        return []
+    if not content:
+        return []
+    try:
+        return parse_cpu_list(content)
+    except ValueError:
+        return []
+
+
+def setup_cpu_affinity(pin_cpus: Optional[str], cpuset_cgroup: Optional[Path]) -> Optional[CpuAffinity]:
+    """Validate and apply the requested CPU pinning.
+
+    --pin-cpus wraps every go test invocation in taskset. --cpuset-cgroup moves
+    the runner into an existing cgroup v2 directory whose cpuset.cpus has been
+    prepared by the operator (typically the isolated cores), so go test and the
+    test binaries it spawns inherit the restriction.
+
+    Raises RuntimeError when pinning was requested but cannot be applied.
+    """
+    if not pin_cpus and not cpuset_cgroup:
+        return None
+
+    if not sys.platform.startswith('linux'):
+        raise RuntimeError("CPU pinning is only supported on Linux")
+
+    isolated = set(read_isolated_cpus())
+
+    if cpuset_cgroup:
+        procs_file = cpuset_cgroup / "cgroup.procs"
+        cpus_file = cpuset_cgroup / "cpuset.cpus.effective"
+        if not procs_file.exists() or not cpus_file.exists():
+            raise RuntimeError(f"Not a cgroup v2 cpuset directory: {cpuset_cgroup}")
+        try:
+            procs_file.write_text(f"{os.getpid()}\n")
+            cpus = parse_cpu_list(cpus_file.read_text())
+        except (OSError, ValueError) as e:
+            raise RuntimeError(f"Failed to join cgroup {cpuset_cgroup}: {e}")
+        return CpuAffinity(
+            method="cpuset",
+            cpus=cpus,
+            cgroup=str(cpuset_cgroup),
+            isolated=bool(isolated) and set(cpus) <= isolated,
+        )
+
+    if shutil.which("taskset") is None:
+        raise RuntimeError("taskset not found (install util-linux)")
+    try:
+        cpus = parse_cpu_list(pin_cpus)
+    except ValueError as e:
+        raise RuntimeError(str(e))
+
+    available = os.sched_getaffinity(0)
+    unavailable = [c for c in cpus if c not in available]
+    if unavailable:
+        raise RuntimeError(f"CPU(s) not available to this process: {format_cpu_list(unavailable)}")
+
+    return CpuAffinity(
+        method="taskset",
+        cpus=cpus,
+        isolated=bool(isolated) and set(cpus) <= isolated,
+    )
+
+
+class SystemTuner:
+    """Optional pre-run system tuning with post-run restore.
+
+    Every knob is opt-in. Original values are saved before anything is written
+    so restore() can put the machine back the way it was found. Only settings
+    that were actually applied are reported in to_metadata().
+    """
+
+    def __init__(self, nice: Optional[int] = None, disable_turbo: bool = False,
+                 performance_governor: bool = False, disable_idle_states: bool = False,
+                 sysfs_cpu: Path = Path("/sys/devices/system/cpu")):
+        self.nice = nice
+        self.disable_turbo = disable_turbo
+        self.performance_governor = performance_governor
+        self.disable_idle_states = disable_idle_states
+        self.sysfs_cpu = sysfs_cpu
+        self.applied: Dict[str, object] = {}
+        self._saved_nice: Optional[int] = None
+        self._saved_files: List[Tuple[Path, str]] = []  # (path, original value) in apply order
+
+    def requested(self) -> bool:
+        return (self.nice is not None or self.disable_turbo
+                or self.performance_governor or self.disable_idle_states)
+
+    def _write(self, path: Path, value: str) -> bool:
+        """Write a sysfs knob, remembering its original value. Returns False on failure."""
+        try:
+            original = path.read_text().strip()
+            if original != value:
+                path.write_text(f"{value}\n")
+                self._saved_files.append((path, original))
+            return True
+        except OSError:
+            return False
+
+    def _apply_priority(self) -> Optional[str]:
+        try:
+            self._saved_nice = os.getpriority(os.PRIO_PROCESS, 0)
+            os.setpriority(os.PRIO_PROCESS, 0, self.nice)
+        except (OSError, AttributeError) as e:
+            self._saved_nice = None
+            return f"could not set nice {self.nice}: {e}"
+        self.applied['nice'] = self.nice
+        return None
+
+    def _apply_turbo(self) -> Optional[str]:
+        # intel_pstate exposes no_turbo (1 = off); acpi-cpufreq/amd expose boost (0 = off)
+        no_turbo = self.sysfs_cpu / "intel_pstate" / "no_turbo"
+        boost = self.sysfs_cpu / "cpufreq" / "boost"
+        if no_turbo.exists():
+            ok = self._write(no_turbo, "1")
+        elif boost.exists():
+            ok = self._write(boost, "0")
+        else:
+            return "no turbo control exposed in sysfs"
+        if not ok:
+            return "could not disable turbo (root required)"
+        self.applied['turbo_disabled'] = True
+        return None
+
+    def _apply_governor(self) -> Optional[str]:
+        files = sorted(self.sysfs_cpu.glob("cpu[0-9]*/cpufreq/scaling_governor"))
+        if not files:
+            return "no cpufreq governor exposed in sysfs"
+        if not all(self._write(f, "performance") for f in files):
+            return "could not set performance governor on all CPUs (root required)"
+        self.applied['governor'] = "performance"
+        return None
+
+    def _apply_idle_states(self) -> Optional[str]:
+        # Same policy as `cpupower idle-set -D 0`: disable every state with exit latency > 0
+        states = []
+        for state in sorted(self.sysfs_cpu.glob("cpu[0-9]*/cpuidle/state[0-9]*")):
+            try:
+                latency = int((state / "latency").read_text().strip())
+            except (OSError, ValueError):
+                continue
+            if latency > 0:
+                states.append(state / "disable")
+        if not states:
+            return "no cpuidle states exposed in sysfs"
+        if not all(self._write(f, "1") for f in states):
+            return "could not disable idle states (root required)"
+        self.applied['idle_states_disabled'] = True
+        return None
+
+    def apply(self) -> List[str]:
+        """Apply the requested settings. Returns warnings for settings that failed."""
+        steps = []
+        if self.nice is not None:
+            steps.append(self._apply_priority)
+        if self.disable_turbo:
+            steps.append(self._apply_turbo)
+        if self.performance_governor:
+            steps.append(self._apply_governor)
+        if self.disable_idle_states:
+            steps.append(self._apply_idle_states)
+
+        warnings = []
+        for step in steps:
+            warning = step()
+            if warning:
+                warnings.append(warning)
+        return warnings
+
+    def restore(self):
+        """Restore everything apply() changed, in reverse order. Safe to call twice."""
+        for path, original in reversed(self._saved_files):
+            try:
+                path.write_text(f"{original}\n")
+            except OSError as e:
+                print(f"⚠ Failed to restore {path}: {e}", file=sys.stderr)
+        self._saved_files = []
+
+        if self._saved_nice is not None:
+            try:
+                os.setpriority(os.PRIO_PROCESS, 0, self._saved_nice)
+            except OSError as e:
+                print(f"⚠ Failed to restore process priority: {e}", file=sys.stderr)
+            self._saved_nice = None
+
+    def to_metadata(self) -> dict:
+        """Serialize applied settings for the run metadata sidecar."""
+        return dict(self.applied)
+
+
+def parse_proc_cpuinfo(text: str) -> dict:
+    """Extract logical and physical core counts from /proc/cpuinfo."""
+    logical = 0
+    cores = set()
+    physical_id = core_id = None
+    for line in text.splitlines() + [""]:
+        if not line.strip():
+            if physical_id is not None or core_id is not None:
+                cores.add((physical_id, core_id))
+            physical_id = core_id = None
+            continue
+        key, _, value = line.partition(':')
+        key, value = key.strip(), value.strip()
+        if key == 'processor':
+            logical += 1
+        elif key == 'physical id':
+            physical_id = value
+        elif key == 'core id':
+            core_id = value
+
+    info = {}
+    if logical:
+        info['logical_cores'] = logical
+    if cores:
+        info['physical_cores'] = len(cores)
+    return info
+
+
+def parse_meminfo(text: str) -> Optional[int]:
+    """Return MemTotal from /proc/meminfo in bytes."""
+    match = re.search(r'^MemTotal:\s+(\d+)\s+kB', text, re.MULTILINE)
+    return int(match.group(1)) * 1024 if match else None
+
+
+def parse_pmset_batt(text: str) -> Optional[str]:
+    """Parse `pmset -g batt` output into "ac" or "battery"."""
+    if "'AC Power'" in text:
+        return "ac"
+    if "'Battery Power'" in text:
+        return "battery"
+    return None
+
+
+def _read_int(path: Path) -> Optional[int]:
+    try:
+        return int(path.read_text().strip())
+    except (OSError, ValueError):
+        return None
+
+
+def _linux_power_source(power_supply: Path = Path("/sys/class/power_supply")) -> str:
+    """Return "battery" when a battery is discharging, otherwise "ac"."""
+    if not power_supply.is_dir():
+        return "ac"
+    for supply in power_supply.iterdir():
+        try:
+            kind = (supply / "type").read_text().strip()
+            if kind == "Mains" and (supply / "online").read_text().strip() == "1":
+                return "ac"
+            if kind == "Battery" and (supply / "status").read_text().strip() == "Discharging":
+                return "battery"
+        except OSError:
+            continue
+    return "ac"
+
+
+def _linux_system_info() -> dict:
+    info = {}
+    try:
+        info.update(parse_proc_cpuinfo(Path("/proc/cpuinfo").read_text()))
+    except OSError:
+        pass
+    try:
+        memory = parse_meminfo(Path("/proc/meminfo").read_text())
+        if memory:
+            info['memory_bytes'] = memory
+    except OSError:
+        pass
+
+    # cpufreq reports kHz; base_frequency is only exposed by intel_pstate.
+    # /proc/cpuinfo "cpu MHz" is the current clock, not the base one, so
+    # without it no base clock is recorded
+    cpufreq = Path("/sys/devices/system/cpu/cpu0/cpufreq")
+    base = _read_int(cpufreq / "base_frequency")
+    boost = _read_int(cpufreq / "cpuinfo_max_freq")
+    if base:
+        info['base_clock_mhz'] = base // 1000
+    if boost:
+        info['boost_clock_mhz'] = boost // 1000
+
+    info['power_source'] = _linux_power_source()
+    return info
+
+
+def _sysctl_int(name: str) -> Optional[int]:
+    try:
+        result = subprocess.run(["sysctl", "-n", name], capture_output=True, text=True, check=False)
+        return int(result.stdout.strip()) if result.returncode == 0 else None
+    except (OSError, ValueError):
+        return None
+
+
+def _darwin_system_info() -> dict:
+    info = {}
+    for key, name in [('physical_cores', 'hw.physicalcpu'),
+                      ('logical_cores', 'hw.logicalcpu'),
+                      ('memory_bytes', 'hw.memsize')]:
+        value = _sysctl_int(name)
+        if value:
+            info[key] = value
+
+    # hw.cpufrequency* only exist on Intel Macs; Apple Silicon does not publish clocks
+    base = _sysctl_int('hw.cpufrequency')
+    boost = _sysctl_int('hw.cpufrequency_max')
+    if base:
+        info['base_clock_mhz'] = base // 1_000_000
+    if boost:
+        info['boost_clock_mhz'] = boost // 1_000_000
+
+    try:
+        result = subprocess.run(["pmset", "-g", "batt"], capture_output=True, text=True, check=False)
+        power = parse_pmset_batt(result.stdout)
+        if power:
+            info['power_source'] = power
+    except OSError:
+        pass
+    return info
+
+
+def _windows_system_info() -> dict:
+    # WMI through PowerShell; Win32_Battery.BatteryStatus 1 means discharging
+    script = (
+        "$p = Get-CimInstance Win32_Processor | Select-Object -First 1; "
+        "$c = Get-CimInstance Win32_ComputerSystem; "
+        "$b = Get-CimInstance Win32_Battery | Select-Object -First 1; "
+        "@{cores=$p.NumberOfCores; logical=$p.NumberOfLogicalProcessors; "
+        "maxclock=$p.MaxClockSpeed; memory=$c.TotalPhysicalMemory; "
+        "battery=if ($b) { $b.BatteryStatus } else { $null }} | ConvertTo-Json"
+    )
+    info = {}
+    try:
+        result = subprocess.run(["powershell", "-NoProfile", "-Command", script],
+                                capture_output=True, text=True, check=False)
+        data = json.loads(result.stdout) if result.returncode == 0 else {}
+    except (OSError, json.JSONDecodeError):
+        data = {}
+
+    for key, field in [('physical_cores', 'cores'), ('logical_cores', 'logical'),
+                       ('boost_clock_mhz', 'maxclock'), ('memory_bytes', 'memory')]:
+        if data.get(field):
+            info[key] = int(data[field])
+    if data:
+        info['power_source'] = "battery" if data.get('battery') == 1 else "ac"
+
+    # Registry holds the nominal (base) clock
+    try:
+        import winreg
+        key = winreg.OpenKey(winreg.HKEY_LOCAL_MACHINE,
+                             r"HARDWARE\DESCRIPTION\System\CentralProcessor\0")
+        info['base_clock_mhz'] = int(winreg.QueryValueEx(key, "~MHz")[0])
+        winreg.CloseKey(key)
+    except (ImportError, OSError):
+        pass
+    return info
+
+
+def collect_system_info() -> dict:
+    """Collect hardware details that `go test` does not print.
+
+    Missing values are omitted rather than guessed. Power source matters
+    because laptops on battery throttle aggressively.
+    """
+    if sys.platform.startswith('linux'):
+        return _linux_system_info()
+    if sys.platform == 'darwin':
+        return _darwin_system_info()
+    if sys.platform.startswith('win'):
+        return _windows_system_info()
+    return {}
+
+
+def write_run_metadata(output_dir: Path, timestamp: str, metadata: dict) -> Path:
+    """Write the run metadata sidecar next to the result file.
+
+    perfbench reads <timestamp>_metadata.json when exporting <timestamp>.txt
+    and merges it into the exported system metadata.
+    """
+    metadata_file = output_dir / f"{timestamp}_metadata.json"
+    with open(metadata_file, 'w') as f:
+        json.dump(metadata, f, indent=2)
+    return metadata_file
+
+
+def gctrace_path(result_file: Path) -> Path:
+    """Return the gctrace sidecar for a result file (<timestamp>_gctrace.txt).
+
+    perfbench attaches the parsed sections to the exported benchmarks.
+    """
+    return result_file.with_name(f"{result_file.stem}_gctrace.txt")
+
+
+def exectrace_dir(result_file: Path) -> Path:
+    """Return the execution trace directory for a result file (<timestamp>_exectrace/)."""
+    return result_file.with_name(f"{result_file.stem}_exectrace")
+
+
+def memprofile_dir(result_file: Path) -> Path:
+    """Return the memory profile directory for a result file (<timestamp>_memprofile/)."""
+    return result_file.with_name(f"{result_file.stem}_memprofile")
+
+
+def benchmark_file(directory: Path, name: str, ext: str) -> Path:
+    """Return the per-benchmark file for name in a sidecar directory, with '/'
+    and other unsafe characters percent-escaped so sub-benchmarks stay a
+    single file name."""
+    return directory / f"{urllib.parse.quote(name, safe='')}{ext}"
+
+
+def extract_gctrace_lines(output: str) -> List[str]:
+    """Return the gctrace lines from combined go test output."""
+    return [m.group(0) for m in GCTRACE_LINE.finditer(output)]
+
+
+def summarize_build_runs(wall_seconds: List[float], binary_bytes: int) -> dict:
+    """Build the run metadata 'build' section from timed cold builds.
+
+    The median is reported since a single cold build is easily disturbed by
+    filesystem caching; individual runs are kept for inspection.
+    """
+    return {
+        'module': f"perf-tracking/{BUILD_TARGET_MODULE}",
+        'wall_seconds': round(statistics.median(wall_seconds), 3),
+        'wall_seconds_runs': [round(t, 3) for t in wall_seconds],
+        'binary_bytes': binary_bytes,
+    }
+
+
 class BenchmarkRunner:
     """Execute Go benchmarks with variance checking."""
 
     def __init__(self, script_dir: Path, verbose: bool = False, progress: Optional[ProgressTracker] = None,
-                 variance_threshold: float = 15.0):
+                 variance_threshold: float = 15.0, affinity: Optional[CpuAffinity] = None,
+                 tuner: Optional[SystemTuner] = None, alternatives: bool = False,
+                 build_metrics: bool = True, gctrace_benchmarks: Optional[List[str]] = None,
+                 trace_benchmarks: Optional[List[str]] = None, contention_profile: bool = False,
+                 memprofile_benchmarks: Optional[List[str]] = None,
+                 size_classes: Optional[str] = None):
         self.script_dir = script_dir
         self.benchmarks_dir = script_dir.parent / "benchmarks"
+        # Third-party comparisons live in their own module to keep their
+        # dependencies out of the core benchmarks
+        self.alternatives_dir = script_dir.parent / "alternatives"
+        self.test_packages = list(DEFAULT_TEST_PACKAGES)
+        if alternatives:
+            self.test_packages.append(ALTERNATIVES_PACKAGE)
+        self.build_target_dir = script_dir.parent / BUILD_TARGET_MODULE
+        self.build_metrics = build_metrics
+        self.gctrace_benchmarks = gctrace_benchmarks or []
+        self.trace_benchmarks = trace_benchmarks or []
+        self.contention_profile = contention_profile
+        self.memprofile_benchmarks = memprofile_benchmarks or []
+        self.size_classes = size_classes
         self.results_base_dir = script_dir.parent / "results" / "stable"
         self.results_dir = None  # Set by detect_platform()
         self.parser = BenchmarkParser()
         self.verbose = verbose
         self.progress = progress
         self.variance_threshold = variance_threshold
+        self.affinity = affinity
+        self.tuner = tuner
         self.streaming_runner = StreamingBenchmarkRunner(progress, verbose, variance_threshold)
 
+    def package_location(self, pkg: str) -> Tuple[Path, str]:
+        """Return the module directory and go test path for a package name."""
+        if pkg == ALTERNATIVES_PACKAGE:
+            return self.alternatives_dir, "./"
+        return self.benchmarks_dir, f"./{pkg}/"
+
+    def _pinned(self, cmd: List[str]) -> List[str]:
+        """Prefix cmd with the configured CPU pinning, if any."""
+        if self.affinity is None:
+            return cmd
+        return self.affinity.command_prefix() + cmd
+
+    def run_metadata(self) -> dict:
+        """Collect runner settings recorded alongside each result file."""
+        system = collect_system_info()
+        if self.affinity is not None:
+            system['pinning'] = self.affinity.to_metadata()
+        if self.tuner is not None and self.tuner.applied:
+            system['tuning'] = self.tuner.to_metadata()
+        metadata = {'system': system}
+        if self.contention_profile:
+            # Profiling slows contended paths; keep these runs apart from normal ones
+            metadata['contention_profile'] = True
+        if self.size_classes:
+            metadata['size_classes'] = self.size_classes
+        source_sha = self.benchmark_source_sha()
+        if source_sha:
+            metadata['benchmark_source_sha'] = source_sha
+        return metadata
+
+    def measure_build(self, go_bin: Path, runs: int = BUILD_RUNS) -> Optional[dict]:
+        """Time cold builds of the build target module and record its binary size.
+
+        Each run uses an empty GOCACHE so the whole stdlib is compiled, which is
+        what compiler and linker changes show up in. Returns None when disabled
+        or when the build fails; build metrics never fail a collection.
+        """
+        if not self.build_metrics:
+            return None
+
+        print(f"Measuring build time ({runs} cold builds of {BUILD_TARGET_MODULE})...")
+        wall_seconds = []
+        binary_bytes = 0
+        with tempfile.TemporaryDirectory(prefix="build-metrics-") as tmp:
+            binary = Path(tmp) / "buildtarget"
+            for i in range(runs):
+                env = os.environ.copy()
+                env["GOTOOLCHAIN"] = "local"
+                env["GOCACHE"] = str(Path(tmp) / f"cache{i}")
+                start = time.perf_counter()
+                result = subprocess.run(
+                    self._pinned([str(go_bin), "build", "-o", str(binary), "."]),
+                    cwd=self.build_target_dir,
+                    env=env,
+                    capture_output=True,
+                    text=True,
+                    check=False
+                )
+                elapsed = time.perf_counter() - start
+                if result.returncode != 0:
+                    print(f"  ⚠ Build failed, skipping build metrics: {result.stderr.strip()}")
+                    return None
+                wall_seconds.append(elapsed)
+                binary_bytes = binary.stat().st_size
+
+        metrics = summarize_build_runs(wall_seconds, binary_bytes)
+        print(f"  ✓ Cold build {metrics['wall_seconds']:.2f}s, binary {binary_bytes / 1e6:.2f} MB")
+        return metrics
+
+    def find_benchmark_package(self, name: str) -> Optional[str]:
+        """Return the test package defining benchmark name (sub-benchmarks allowed)."""
+        func = f"func {name.split('/')[0]}("
+        for pkg in self.test_packages:
+            module_dir, pkg_path = self.package_location(pkg)
+            for source in (module_dir / pkg_path).glob("*_test.go"):
+                if func in source.read_text():
+                    return pkg
+        return None
+
+    def _run_isolated(self, go_bin: Path, name: str, benchtime: str, bin_dir: Path,
+                      extra_args: List[str], env: dict) -> Optional[subprocess.CompletedProcess]:
+        """Run one benchmark once in its own test process, for tracing.
+
+        The package's test binary is built into bin_dir on first use (without
+        the tracing environment, so only the benchmark itself is traced). Prints
+        a warning and returns None when the benchmark can't be run.
+        """
+        pkg = self.find_benchmark_package(name)
+        if pkg is None:
+            print(f"  ⚠ {name}: benchmark not found, skipping")
+            return None
+        module_dir, pkg_path = self.package_location(pkg)
+        test_bin = bin_dir / f"{pkg}.test"
+        if not test_bin.exists():
+            build_env = os.environ.copy()
+            build_env["GOTOOLCHAIN"] = "local"
+            build = subprocess.run(
+                [str(go_bin), "test", "-c", "-o", str(test_bin), pkg_path],
+                cwd=module_dir, env=build_env, capture_output=True, text=True, check=False
+            )
+            if build.returncode != 0:
+                print(f"  ⚠ {name}: building {pkg} tests failed, skipping")
+                return None
+
+        bench_filter = create_benchmark_filters([name])[0]
+        result = subprocess.run(
+            self._pinned([
+                str(test_bin), "-test.run=^$",
+                f"-test.bench={bench_filter}", "-test.benchmem",
+                "-test.count=1", f"-test.benchtime={benchtime}",
+                "-test.timeout=1800s", *extra_args
+            ]),
+            # go test runs test binaries in the package directory
+            cwd=module_dir / pkg_path,
+            env=env,
+            stdout=subprocess.PIPE,
+            stderr=subprocess.STDOUT,
+            text=True,
+            check=False
+        )
+        if result.returncode != 0:
+            print(f"  ⚠ {name}: benchmark failed, skipping")
+            return None
+        return result
+
+    def capture_gctrace(self, go_bin: Path, output_file: Path, benchtime: str) -> Optional[Path]:
+        """Run each --gctrace benchmark once more with GODEBUG=gctrace=1.
+
+        Every benchmark runs in its own test process so the GC cycles in
+        its section belong to it alone; the timed results are not used. The
+        sidecar holds one "=== <name>" section per benchmark. Returns None
+        when no benchmarks were selected; failures never fail a collection.
+        """
+        if not self.gctrace_benchmarks:
+            return None
+
+        trace_env = os.environ.copy()
+        trace_env["GODEBUG"] = ",".join(filter(None, [trace_env.get("GODEBUG"), "gctrace=1"]))
+
+        print(f"Capturing GC traces for {len(self.gctrace_benchmarks)} benchmark(s)...")
+        sections = []
+        with tempfile.TemporaryDirectory(prefix="gctrace-") as tmp:
+            for name in self.gctrace_benchmarks:
+                result = self._run_isolated(go_bin, name, benchtime, Path(tmp), [], trace_env)
+                if result is None:
+                    continue
+                lines = extract_gctrace_lines(result.stdout)
+                sections.append(f"=== {name}\n" + "".join(f"{line}\n" for line in lines))
+                print(f"  ✓ {name}: {len(lines)} GC cycles")
+
+        if not sections:
+            return None
+        path = gctrace_path(output_file)
+        with open(path, 'w') as f:
+            f.write("".join(sections))
+        return path
+
+    def _capture_files(self, go_bin: Path, names: List[str], benchtime: str, out_dir: Path,
+                       ext: str, flag: str, extra_args: List[str], what: str) -> Optional[Path]:
+        """Run each benchmark once more, writing one file per benchmark.
+
+        Like capture_gctrace, every benchmark gets its own test process and
+        the timed results are discarded. flag (e.g. -test.trace) receives the
+        benchmark's file in out_dir, named by benchmark_file. Returns out_dir,
+        or None when nothing was captured.
+        """
+        print(f"Capturing {what}s for {len(names)} benchmark(s)...")
+        out_dir.mkdir(exist_ok=True)
+        captured = 0
+        with tempfile.TemporaryDirectory(prefix="capture-") as tmp:
+            for name in names:
+                out_file = benchmark_file(out_dir, name, ext)
+                result = self._run_isolated(go_bin, name, benchtime, Path(tmp),
+                                            [f"{flag}={out_file}", *extra_args], os.environ.copy())
+                if result is None:
+                    out_file.unlink(missing_ok=True)
+                    continue
+                captured += 1
+                size_mb = out_file.stat().st_size / (1024 * 1024)
+                print(f"  ✓ {name}: {size_mb:.1f} MB {what}")
+
+        if not captured:
+            out_dir.rmdir()
+            return None
+        return out_dir
+
+    def capture_exectrace(self, go_bin: Path, output_file: Path, benchtime: str) -> Optional[Path]:
+        """Run each --trace benchmark once more with -test.trace.
+
+        perfbench summarizes the traces at export. Returns the trace
+        directory, or None when nothing was traced.
+        """
+        if not self.trace_benchmarks:
+            return None
+        return self._capture_files(go_bin, self.trace_benchmarks, benchtime, exectrace_dir(output_file),
+                                   ".trace", "-test.trace", [], "execution trace")
+
+    def capture_memprofile(self, go_bin: Path, output_file: Path, benchtime: str) -> Optional[Path]:
+        """Run each --memprofile benchmark once more with -test.memprofile.
+
+        Every allocation is sampled (MEMPROFILE_RATE) so call sites with few
+        but large allocations are not missed; perfbench attributes them at
+        export. Returns the profile directory, or None when nothing ran.
+        """
+        if not self.memprofile_benchmarks:
+            return None
+        return self._capture_files(go_bin, self.memprofile_benchmarks, benchtime,
+                                   memprofile_dir(output_file), ".pprof", "-test.memprofile",
+                                   [f"-test.memprofilerate={MEMPROFILE_RATE}"], "memory profile")
+
+    def benchmark_source_sha(self) -> Optional[str]:
+        """Return the last commit touching the benchmark sources (None outside git).
+
+        Results are only comparable when collected from the same benchmark code;
+        perfbench validate checks this against the local checkout.
+        """
+        try:
+            result = subprocess.run(
+                ["git", "log", "-1", "--format=%H", "--", "."],
+                cwd=self.benchmarks_dir,
+                capture_output=True,
+                text=True,
+                check=False
+            )
+        except OSError:
+            return None
+        sha = result.stdout.strip()
+        return sha if result.returncode == 0 and sha else None
+
     def find_go_binary(self, version: str) -> Optional[Path]:
         """Find Go binary for specified version."""
         setup_script = self.script_dir / "setup-go-versions.sh"
@@ -614,13 +1381,13 @@ class BenchmarkRunner:
 
         os.chdir(self.benchmarks_dir)
 
-        cmd = [
+        cmd = self._pinned([
             str(go_bin), "test",
             "-bench=.", "-benchmem",
             "-count=3", "-benchtime=1s",
             "-timeout=300s",
-            "./runtime/", "./stdlib/", "./networking/"
-        ]
+            *(f"./{pkg}/" for pkg in DEFAULT_TEST_PACKAGES)
+        ])
 
         env = os.environ.copy()
         env["GOTOOLCHAIN"] = "local"
@@ -671,10 +1438,8 @@ class BenchmarkRunner:
         - results: dict of {package: {'success': bool, 'error': str, 'benchmarks': int}}
         - output_file: Path
         """
-        os.chdir(self.benchmarks_dir)
-
         if test_packages is None:
-            test_packages = ["runtime", "stdlib", "networking"]
+            test_packages = self.test_packages
 
         # Normalize to list of filters
         if benchmark_filters is None:
@@ -682,6 +1447,10 @@ class BenchmarkRunner:
 
         env = os.environ.copy()
         env["GOTOOLCHAIN"] = "local"
+        if self.contention_profile:
+            env[CONTENTION_PROFILE_ENV] = "1"
+        if self.size_classes:
+            env[ALLOC_SIZE_CLASSES_ENV] = self.size_classes
 
         if self.progress and version:
             self.progress.set_phase(version, "Collecting benchmarks")
@@ -696,7 +1465,8 @@ class BenchmarkRunner:
         all_failed_benchmarks = []  # Track all failed benchmarks across packages
 
         for pkg in test_packages:
-            pkg_path = f"./{pkg}/"
+            module_dir, pkg_path = self.package_location(pkg)
+            os.chdir(module_dir)
 
             if self.progress and version:
                 if not is_retry:
@@ -723,14 +1493,14 @@ class BenchmarkRunner:
 
                 bench_arg = f"-bench={bench_filter}" if bench_filter else "-bench=."
 
-                cmd = [
+                cmd = self._pinned([
                     str(go_bin), "test",
                     bench_arg, "-benchmem",
                     f"-count={count}",
                     f"-benchtime={benchtime}",
                     "-timeout=1800s",
                     pkg_path
-                ]
+                ])
 
                 # Run test package with streaming
                 returncode, output, failed_benches = self.streaming_runner.run_with_streaming(cmd, env, pkg)
@@ -1185,6 +1955,15 @@ def run_variance_aware_benchmarks(
 
     print(f"\n✓ Collection complete: {output_file}")
 
+    metadata = runner.run_metadata()
+    build = runner.measure_build(go_bin)
+    if build:
+        metadata['build'] = build
+    write_run_metadata(output_dir, timestamp, metadata)
+    runner.capture_gctrace(go_bin, output_file, benchtime)
+    runner.capture_exectrace(go_bin, output_file, benchtime)
+    runner.capture_memprofile(go_bin, output_file, benchtime)
+
     # Analyze variance
     stats, failed = runner.analyze_variance(output_file, variance_threshold)
 
@@ -1255,6 +2034,22 @@ Examples:
 
   # CI/CD mode (skip system checks, no interactive prompts)
   %(prog)s 1.23 --count 25 --progress --skip-system-check
+
+  # Attach GC trace summaries to selected benchmarks
+  %(prog)s 1.24 --count 20 --progress --gctrace BenchmarkHTTPService,BenchmarkGCThroughput
+
+  # Attach execution trace summaries (scheduler latency, STW, netpoller wait)
+  %(prog)s 1.24 --count 20 --progress --trace BenchmarkHTTPService/Parallel
+
+  # Attribute allocations of selected benchmarks to their top call sites
+  %(prog)s 1.24 --count 20 --progress --memprofile BenchmarkJSONEncode,BenchmarkHTTPService
+
+  # Pin benchmark processes to isolated cores (Linux)
+  %(prog)s 1.24 --count 20 --progress --pin-cpus 2,3
+
+  # Controlled environment: priority, turbo, governor and idle states (Linux, root)
+  %(prog)s 1.24 --count 20 --progress --raise-priority --disable-turbo \\
+      --performance-governor --disable-idle-states
         """
     )
 
@@ -1316,12 +2111,111 @@ Examples:
         help="Enable progress tracking with timestamps (writes to results/collection_progress.json)"
     )
 
+    parser.add_argument(
+        "--alternatives",
+        action="store_true",
+        help="Also run the stdlib vs third-party comparisons in perf-tracking/alternatives"
+    )
+
+    parser.add_argument(
+        "--skip-build-metrics",
+        action="store_true",
+        help=f"Don't record cold build time and binary size of perf-tracking/{BUILD_TARGET_MODULE}"
+    )
+
+    parser.add_argument(
+        "--gctrace",
+        metavar="NAMES",
+        type=lambda v: [n.strip() for n in v.split(",") if n.strip()],
+        default=[],
+        help="Comma-separated benchmarks (e.g. BenchmarkHTTPService) to re-run once with "
+             "GODEBUG=gctrace=1; GC cycles and CPU share are exported with their results"
+    )
+
+    parser.add_argument(
+        "--trace",
+        metavar="NAMES",
+        type=lambda v: [n.strip() for n in v.split(",") if n.strip()],
+        default=[],
+        help="Comma-separated benchmarks to re-run once with -test.trace; perfbench exports "
+             "scheduler latency, GC stop-the-world pauses and netpoller wait from the traces"
+    )
+
+    parser.add_argument(
+        "--memprofile",
+        metavar="NAMES",
+        type=lambda v: [n.strip() for n in v.split(",") if n.strip()],
+        default=[],
+        help="Comma-separated benchmarks to re-run once with -test.memprofile, sampling every "
+             "allocation; perfbench exports their top allocation call sites"
+    )
+
+    parser.add_argument(
+        "--contention-profile",
+        action="store_true",
+        help="Enable mutex/block profiling in contention benchmarks (BenchmarkMutexContention, "
+             "BenchmarkSyncMap, networking Parallel variants) and report contention time per op; "
+             "slows contended paths, so use dedicated runs"
+    )
+
+    parser.add_argument(
+        "--size-classes",
+        metavar="SIZES",
+        help="Size classes for BenchmarkSmallAllocSpecialized: 'all' (every class from 16B to "
+             "32KB) or comma-separated class sizes in bytes; default is every class up to 512B "
+             "plus the power-of-two classes above"
+    )
+
     parser.add_argument(
         "--skip-system-check",
         action="store_true",
         help="Skip system stability checks (useful for CI/CD environments)"
     )
 
+    pinning = parser.add_mutually_exclusive_group()
+    pinning.add_argument(
+        "--pin-cpus",
+        metavar="LIST",
+        help="Linux only: run go test under taskset on these CPUs (e.g. 2,3 or 4-7)"
+    )
+    pinning.add_argument(
+        "--cpuset-cgroup",
+        type=Path,
+        metavar="DIR",
+        help="Linux only: join this prepared cgroup v2 cpuset before running benchmarks"
+    )
+
+    parser.add_argument(
+        "--raise-priority",
+        action="store_true",
+        help=f"Run benchmarks at nice {RAISED_NICE} (requires root); use --nice for another level"
+    )
+
+    parser.add_argument(
+        "--nice",
+        type=int,
+        metavar="N",
+        help="Run benchmarks at nice level N (requires root for N < 0)"
+    )
+
+    parser.add_argument(
+        "--disable-turbo",
+        action="store_true",
+        help="Linux only: disable turbo/boost via sysfs for the run, restored afterwards"
+    )
+
+    parser.add_argument(
+        "--performance-governor",
+        action="store_true",
+        help="Linux only: switch cpufreq governor to performance for the run, restored afterwards"
+    )
+
+    parser.add_argument(
+        "--disable-idle-states",
+        action="store_true",
+        help="Linux only: disable non-polling cpuidle states for the run, restored afterwards"
+    )
+
     args = parser.parse_args()
 
     # Setup paths
@@ -1337,8 +2231,42 @@ Examples:
         progress.log(f"Starting collection for {len(args.versions)} version(s): {', '.join(args.versions)}")
         progress.log("="*60)
 
+    try:
+        affinity = setup_cpu_affinity(args.pin_cpus, args.cpuset_cgroup)
+    except RuntimeError as e:
+        print(f"✗ CPU pinning failed: {e}", file=sys.stderr)
+        sys.exit(1)
+    if affinity:
+        note = " (isolated)" if affinity.isolated else ""
+        print(f"CPU pinning: {affinity.method} on CPUs {format_cpu_list(affinity.cpus)}{note}")
+
+    nice = args.nice
+    if nice is None and args.raise_priority:
+        nice = RAISED_NICE
+    tuner = SystemTuner(nice=nice, disable_turbo=args.disable_turbo,
+                        performance_governor=args.performance_governor,
+                        disable_idle_states=args.disable_idle_states)
+    if tuner.requested():
+        # atexit also covers KeyboardInterrupt and sys.exit paths
+        atexit.register(tuner.restore)
+        for warning in tuner.apply():
+            print(f"⚠ System tuning: {warning}")
+        if tuner.applied:
+            applied = ", ".join(f"{k}={v}" for k, v in tuner.applied.items())
+            print(f"System tuning applied: {applied}")
+
+    if collect_system_info().get('power_source') == 'battery':
+        print("⚠ Running on battery power: expect throttling and high variance")
+
     runner = BenchmarkRunner(script_dir, verbose=args.verbose, progress=progress,
-                             variance_threshold=args.variance_threshold)
+                             variance_threshold=args.variance_threshold, affinity=affinity,
+                             tuner=tuner, alternatives=args.alternatives,
+                             build_metrics=not args.skip_build_metrics,
+                             gctrace_benchmarks=args.gctrace,
+                             trace_benchmarks=args.trace,
+                             contention_profile=args.contention_profile,
+                             memprofile_benchmarks=args.memprofile,
+                             size_classes=args.size_classes)
 
     # Process each version
     for version in args.versions:
@@ -1409,7 +2337,7 @@ Examples:
             bench_filters = create_benchmark_filters(failed_benchmarks)
 
             # Use same workflow as main collection with _rerun suffix
-            # This prevents benchexport from picking up these partial result files
+            # This prevents perfbench from picking up these partial result files
             timestamp = datetime.now().strftime("%Y-%m-%d_%H-%M-%S") + "_rerun"
 
             result = run_variance_aware_benchmarks(
@@ -1572,6 +2500,10 @@ Examples:
         print("Collection complete")
         print('='*60)
 
+    if tuner.requested():
+        tuner.restore()
+        print("System tuning restored")
+
 
 if __name__ == "__main__":
     main()
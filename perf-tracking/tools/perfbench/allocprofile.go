@@ -0,0 +1,163 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// allocSiteLimit is the number of call sites kept per benchmark
+const allocSiteLimit = 10
+
+// AllocProfile attributes the allocations of one -memprofile run of a
+// benchmark, captured by collect_benchmarks.py --memprofile, to the call
+// sites that made them. Like GCTrace it covers the whole run including
+// setup and b.N calibration; the shares are what to compare.
+type AllocProfile struct {
+	TotalBytes   int64       `json:"total_bytes"`
+	TotalObjects int64       `json:"total_objects"`
+	Sites        []AllocSite `json:"sites"` // largest first, at most allocSiteLimit
+}
+
+// AllocSite is the first frame outside the runtime's allocator on the
+// stacks of sampled allocations, with their summed size and count.
+type AllocSite struct {
+	Function     string  `json:"function"`
+	File         string  `json:"file"`
+	Line         int64   `json:"line"`
+	Bytes        int64   `json:"bytes"`
+	Objects      int64   `json:"objects"`
+	BytesPercent float64 `json:"bytes_percent"`
+}
+
+// allocProfileExt is the extension of each profile in the sidecar directory
+const allocProfileExt = ".pprof"
+
+// allocProfileDir returns the directory holding the memory profiles for a
+// benchmark result file, one <escaped name>.pprof file per benchmark
+func allocProfileDir(benchFile string) string {
+	return strings.TrimSuffix(benchFile, filepath.Ext(benchFile)) + "_memprofile"
+}
+
+// loadAllocProfiles attributes every profile in the sidecar directory of
+// benchFile, keyed by benchmark name. Returns nil without error when the run
+// had no --memprofile. A profile that fails to parse is reported and skipped.
+func loadAllocProfiles(benchFile string) (map[string]*AllocProfile, error) {
+	files, errs := benchmarkFiles(allocProfileDir(benchFile), allocProfileExt)
+	if len(files) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	profiles := make(map[string]*AllocProfile, len(files))
+	for name, path := range files {
+		p, err := readAllocProfile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		profiles[name] = p
+	}
+	return profiles, errors.Join(errs...)
+}
+
+func readAllocProfile(path string) (*AllocProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memprofile %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }() // read-only
+
+	prof, err := profile.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse memprofile %s: %w", path, err)
+	}
+	p, err := attributeAllocs(prof)
+	if err != nil {
+		return nil, fmt.Errorf("memprofile %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// attributeAllocs sums the alloc_space and alloc_objects samples of a heap
+// profile by call site. runtime/pprof already scales samples by the
+// sampling rate, so the totals estimate all allocations of the run.
+func attributeAllocs(prof *profile.Profile) (*AllocProfile, error) {
+	bytesIdx, objectsIdx := -1, -1
+	for i, st := range prof.SampleType {
+		switch st.Type {
+		case "alloc_space":
+			bytesIdx = i
+		case "alloc_objects":
+			objectsIdx = i
+		}
+	}
+	if bytesIdx < 0 || objectsIdx < 0 {
+		return nil, errors.New("not a heap profile: missing alloc_space/alloc_objects")
+	}
+
+	var result AllocProfile
+	sites := make(map[AllocSite]*AllocSite)
+	for _, s := range prof.Sample {
+		key, ok := allocCallSite(s)
+		if !ok {
+			continue
+		}
+		site := sites[key]
+		if site == nil {
+			site = &AllocSite{Function: key.Function, File: key.File, Line: key.Line}
+			sites[key] = site
+		}
+		site.Bytes += s.Value[bytesIdx]
+		site.Objects += s.Value[objectsIdx]
+		result.TotalBytes += s.Value[bytesIdx]
+		result.TotalObjects += s.Value[objectsIdx]
+	}
+
+	all := make([]*AllocSite, 0, len(sites))
+	for _, site := range sites {
+		all = append(all, site)
+	}
+	slices.SortFunc(all, func(a, b *AllocSite) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Function, b.Function), cmp.Compare(a.Line, b.Line))
+	})
+	for _, site := range all[:min(allocSiteLimit, len(all))] {
+		if result.TotalBytes > 0 {
+			site.BytesPercent = float64(site.Bytes) / float64(result.TotalBytes) * 100
+		}
+		result.Sites = append(result.Sites, *site)
+	}
+	return &result, nil
+}
+
+// allocCallSite returns the innermost frame of s outside the allocator
+// (see inAllocator), with inlined frames expanded. Only the Function, File
+// and Line fields are set.
+func allocCallSite(s *profile.Sample) (AllocSite, bool) {
+	for _, loc := range s.Location {
+		// Line lists inlined frames innermost first
+		for _, line := range loc.Line {
+			if line.Function == nil || inAllocator(line.Function) {
+				continue
+			}
+			return AllocSite{Function: line.Function.Name, File: line.Function.Filename, Line: line.Line}, true
+		}
+	}
+	return AllocSite{}, false
+}
+
+// inAllocator reports whether fn is part of the runtime: mallocgc,
+// growslice and friends, the internal/runtime map implementation, and
+// functions such as reflect.unsafe_New that are linknamed into package
+// runtime and so only identifiable by their source file.
+func inAllocator(fn *profile.Function) bool {
+	return strings.HasPrefix(fn.Name, "runtime.") ||
+		strings.HasPrefix(fn.Name, "internal/runtime/") ||
+		strings.HasPrefix(fn.Filename, "runtime/") || // -trimpath
+		strings.Contains(fn.Filename, "/src/runtime/")
+}
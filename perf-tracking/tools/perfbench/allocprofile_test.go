@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+)
+
+var allocSink [][]byte
+
+//go:noinline
+func allocateForProfile() {
+	for range 100 {
+		allocSink = append(allocSink, make([]byte, 64<<10))
+	}
+}
+
+// recordMemProfile profiles every allocation of allocateForProfile.
+func recordMemProfile(t *testing.T) []byte {
+	t.Helper()
+	defer func(rate int) { runtime.MemProfileRate = rate }(runtime.MemProfileRate)
+	runtime.MemProfileRate = 1
+	allocateForProfile()
+	allocSink = nil
+	// Allocations show up in the profile once a GC cycle has completed
+	runtime.GC()
+	runtime.GC()
+
+	var buf bytes.Buffer
+	if err := pprof.Lookup("allocs").WriteTo(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAttributeAllocs(t *testing.T) {
+	prof, err := profile.Parse(bytes.NewReader(recordMemProfile(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := attributeAllocs(prof)
+	if err != nil {
+		t.Fatalf("attributeAllocs failed: %v", err)
+	}
+	if len(p.Sites) == 0 || len(p.Sites) > allocSiteLimit {
+		t.Fatalf("got %d sites, want 1..%d", len(p.Sites), allocSiteLimit)
+	}
+
+	var found *AllocSite
+	for i, site := range p.Sites {
+		if strings.HasPrefix(site.Function, "runtime.") {
+			t.Errorf("site %d is inside the runtime: %s", i, site.Function)
+		}
+		if i > 0 && site.Bytes > p.Sites[i-1].Bytes {
+			t.Errorf("sites not sorted by bytes: %d > %d", site.Bytes, p.Sites[i-1].Bytes)
+		}
+		if strings.HasSuffix(site.Function, ".allocateForProfile") {
+			found = &p.Sites[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("allocateForProfile not among sites %+v", p.Sites)
+	}
+	if found.Objects < 100 || found.Bytes < 100*64<<10 || filepath.Base(found.File) != "allocprofile_test.go" {
+		t.Errorf("allocateForProfile site = %+v", *found)
+	}
+	if found.BytesPercent <= 0 || found.BytesPercent > 100 {
+		t.Errorf("BytesPercent = %v", found.BytesPercent)
+	}
+
+	cpu := &profile.Profile{SampleType: []*profile.ValueType{{Type: "samples", Unit: "count"}}}
+	if _, err := attributeAllocs(cpu); err == nil {
+		t.Error("expected an error for a non-heap profile")
+	}
+}
+
+func TestLoadAllocProfiles(t *testing.T) {
+	dir := t.TempDir()
+	benchFile := filepath.Join(dir, "2026-01-26_21-55-10.txt")
+	benchOutput := `goos: linux
+goarch: amd64
+cpu: Test CPU
+BenchmarkJSONEncode/Small-16    	  100000	      1000 ns/op	     512 B/op	       4 allocs/op
+BenchmarkJSONEncode/Large-16    	   10000	     10000 ns/op	    8192 B/op	      40 allocs/op
+`
+	if err := os.WriteFile(benchFile, []byte(benchOutput), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := loadAllocProfiles(benchFile)
+	if err != nil || profiles != nil {
+		t.Fatalf("missing sidecar: got %v, %v; want nil, nil", profiles, err)
+	}
+
+	profileDir := allocProfileDir(benchFile)
+	if err := os.Mkdir(profileDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"BenchmarkJSONEncode.pprof":         recordMemProfile(t),
+		"BenchmarkJSONDecode%2FLarge.pprof": []byte("not a profile"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(profileDir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	profiles, err = loadAllocProfiles(benchFile)
+	if err == nil {
+		t.Error("expected an error for the invalid profile")
+	}
+	if len(profiles) != 1 || profiles["BenchmarkJSONEncode"] == nil {
+		t.Fatalf("profiles = %v, want BenchmarkJSONEncode only", profiles)
+	}
+
+	vd, err := parseBenchmarkFile(benchFile, "1.25")
+	if err != nil {
+		t.Fatalf("parseBenchmarkFile failed: %v", err)
+	}
+	for _, name := range []string{"BenchmarkJSONEncode/Small", "BenchmarkJSONEncode/Large"} {
+		if got := vd.Benchmarks[name].AllocProfile; got == nil || len(got.Sites) == 0 {
+			t.Errorf("%s: AllocProfile = %+v, want the BenchmarkJSONEncode profile", name, got)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// formatGitHubAnnotations prints workflow commands GitHub Actions turns into
+// annotations on the PR diff, one per significant regression
+const formatGitHubAnnotations = "gh-annotations"
+
+// sourceLocation is where a benchmark function is defined, relative to the
+// repository root; Line is 0 when unknown
+type sourceLocation struct {
+	File string
+	Line int
+}
+
+// sourceLocator finds benchmark functions under a repository checkout,
+// starting from the file getBenchmarkSourceFile maps them to
+type sourceLocator struct {
+	root  string
+	cache map[string]sourceLocation
+}
+
+func newSourceLocator(root string) *sourceLocator {
+	return &sourceLocator{root: root, cache: make(map[string]sourceLocation)}
+}
+
+// locate returns the definition of the top-level function of the benchmark
+// name. When the mapped file does not define it, the other _test.go files
+// of its directory are searched; when none does, the location is empty.
+func (l *sourceLocator) locate(name string) sourceLocation {
+	base := benchmarkBaseName(name)
+	if loc, ok := l.cache[base]; ok {
+		return loc
+	}
+	var loc sourceLocation
+	mapped := getBenchmarkSourceFile(base)
+	candidates := []string{mapped}
+	if matches, err := filepath.Glob(filepath.Join(l.root, filepath.FromSlash(path.Dir(mapped)), "*_test.go")); err == nil {
+		for _, m := range matches {
+			if rel, err := filepath.Rel(l.root, m); err == nil && filepath.ToSlash(rel) != mapped {
+				candidates = append(candidates, filepath.ToSlash(rel))
+			}
+		}
+	}
+	for _, file := range candidates {
+		if line := funcLine(filepath.Join(l.root, filepath.FromSlash(file)), base); line > 0 {
+			loc = sourceLocation{File: file, Line: line}
+			break
+		}
+	}
+	l.cache[base] = loc
+	return loc
+}
+
+// funcLine returns the 1-based line declaring func name in file, 0 when the
+// file cannot be read or lacks it
+func funcLine(file, name string) int {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+
+	prefix := "func " + name + "("
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.HasPrefix(scanner.Text(), prefix) {
+			return line
+		}
+	}
+	return 0
+}
+
+// escapeAnnotationData escapes a workflow command message
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// writeGitHubAnnotations writes a ::warning command for every significant
+// ns/op regression, worst first, pointing at the benchmark's source. Those
+// in failures, the regressions beyond their -fail-on-regression threshold,
+// are written as ::error instead, including metric regressions.
+func writeGitHubAnnotations(w io.Writer, comparisons []Comparison, failures []GateFailure, locator *sourceLocator) {
+	type annotation struct {
+		level, benchmark, message string
+		delta                     float64
+	}
+	var annotations []annotation
+	failed := make(map[string]bool)
+	for _, f := range failures {
+		failed[f.Benchmark+" "+f.Metric] = true
+		annotations = append(annotations, annotation{
+			level:     "error",
+			benchmark: f.Benchmark,
+			message:   fmt.Sprintf("%s %s regressed %+.1f%% (threshold %.1f%%)", f.Benchmark, f.Metric, f.DeltaPercent, f.Threshold),
+			delta:     f.DeltaPercent,
+		})
+	}
+	for _, c := range comparisons {
+		if !c.significant() || c.DeltaPercent <= 0 || failed[c.Benchmark+" ns/op"] {
+			continue
+		}
+		pValue := fmt.Sprintf("n=%d+%d", c.BaselineSamples, c.TargetSamples)
+		if c.tested() {
+			pValue = fmt.Sprintf("p=%.3f", c.PValue)
+		}
+		annotations = append(annotations, annotation{
+			level:     "warning",
+			benchmark: c.Benchmark,
+			message:   fmt.Sprintf("%s regressed %+.1f%% in ns/op (%s)", c.Benchmark, c.DeltaPercent, pValue),
+			delta:     c.DeltaPercent,
+		})
+	}
+	slices.SortFunc(annotations, func(a, b annotation) int {
+		if c := cmp.Compare(b.delta, a.delta); c != 0 {
+			return c
+		}
+		return strings.Compare(a.message, b.message)
+	})
+
+	for _, a := range annotations {
+		props := []string{"title=" + escapeAnnotationProperty("Benchmark regression")}
+		if loc := locator.locate(a.benchmark); loc.File != "" {
+			props = append([]string{"file=" + escapeAnnotationProperty(loc.File), "line=" + fmt.Sprint(loc.Line)}, props...)
+		}
+		fmt.Fprintf(w, "::%s %s::%s\n", a.level, strings.Join(props, ","), escapeAnnotationData(a.message))
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "perf-tracking", "benchmarks", "networking")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// getBenchmarkSourceFile maps TLS benchmarks to tls_test.go; the
+	// function lives next to it
+	src := "package networking\n\nimport \"testing\"\n\nfunc BenchmarkTLSResume(b *testing.B) {\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "resume_test.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tested := func(name string, delta, p float64) Comparison {
+		return Comparison{Benchmark: name, BaselineNs: 100, TargetNs: 100 + delta, DeltaPercent: delta,
+			BaselineSamples: 6, TargetSamples: 6, PValue: p}
+	}
+	comparisons := []Comparison{
+		tested("BenchmarkTLSResume/Rotate", 12, 0.002),
+		tested("BenchmarkGCLatency", 30, 0.002),
+		tested("BenchmarkTinyAlloc", 20, 0.3), // not significant
+		tested("BenchmarkSHA256", -15, 0.002), // faster
+	}
+	failures := []GateFailure{{Benchmark: "BenchmarkGCLatency", Metric: "ns/op", DeltaPercent: 30, Threshold: 25}}
+
+	var sb strings.Builder
+	writeGitHubAnnotations(&sb, comparisons, failures, newSourceLocator(root))
+	// % in messages is escaped as %25, which GitHub decodes
+	want := "::error title=Benchmark regression::BenchmarkGCLatency ns/op regressed +30.0%25 (threshold 25.0%25)\n" +
+		"::warning file=perf-tracking/benchmarks/networking/resume_test.go,line=5,title=Benchmark regression::BenchmarkTLSResume/Rotate regressed +12.0%25 in ns/op (p=0.002)\n"
+	if got := sb.String(); got != want {
+		t.Errorf("annotations:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEscapeAnnotation(t *testing.T) {
+	if got := escapeAnnotationData("a%b\nc"); got != "a%25b%0Ac" {
+		t.Errorf("escapeAnnotationData = %q", got)
+	}
+	if got := escapeAnnotationProperty("dir/a,b:c"); got != "dir/a%2Cb%3Ac" {
+		t.Errorf("escapeAnnotationProperty = %q", got)
+	}
+}
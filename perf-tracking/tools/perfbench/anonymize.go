@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	// /home/alice/..., /Users/alice/..., C:\Users\alice\...
+	userPathPattern = regexp.MustCompile(`(?i)(/home/|/Users/|[A-Z]:\\Users\\)[^/\\\s]+`)
+
+	// EC2-style internal hostnames and mDNS/corporate names
+	hostnamePattern = regexp.MustCompile(`(?i)\b(?:ip-\d{1,3}-\d{1,3}-\d{1,3}-\d{1,3}(?:\.[a-z0-9.-]+)?|[a-z0-9-]+\.(?:local|lan|internal|corp))\b`)
+
+	// Serial-like tokens: hex ids, UUIDs, and long alphanumeric runs that mix
+	// digits and letters. Model numbers such as "8375C" or "M2" are shorter.
+	serialPattern = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]{4,}|[0-9a-fA-F]{8}(?:-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}|[0-9A-Za-z]*[0-9][0-9A-Za-z]*[A-Za-z][0-9A-Za-z]{6,}|[0-9A-Za-z]*[A-Za-z][0-9A-Za-z]*[0-9][0-9A-Za-z]{6,})\b`)
+)
+
+// anonymizeString removes environment details from a free-form metadata value.
+// hostname is the local machine name; it is scrubbed verbatim when non-empty.
+func anonymizeString(s, hostname string) string {
+	if s == "" {
+		return s
+	}
+	if hostname != "" {
+		s = strings.ReplaceAll(s, hostname, "<host>")
+	}
+	s = userPathPattern.ReplaceAllString(s, "${1}<user>")
+	s = hostnamePattern.ReplaceAllString(s, "<host>")
+	s = serialPattern.ReplaceAllString(s, "<id>")
+	return s
+}
+
+// anonymizeMetadata scrubs hostnames, usernames in paths, and serial-like
+// identifiers from exported metadata so results from corporate machines can
+// be shared. Hardware characteristics needed for comparison are kept.
+func anonymizeMetadata(meta *VersionMetadata) {
+	hostname, _ := os.Hostname()
+	// Short hostnames like "dev" would mangle unrelated text
+	if len(hostname) < 4 {
+		hostname = ""
+	}
+
+	meta.GoVersionFull = anonymizeString(meta.GoVersionFull, hostname)
+	meta.System.CPU = anonymizeString(meta.System.CPU, hostname)
+	if meta.System.Pinning != nil && meta.System.Pinning.Cgroup != "" {
+		meta.System.Pinning.Cgroup = anonymizeString(meta.System.Pinning.Cgroup, hostname)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestAnonymizeString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		hostname string
+		want     string
+	}{
+		{"plain CPU kept", "Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz", "", "Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz"},
+		{"model numbers kept", "AMD Ryzen 9 7950X3D 16-Core Processor", "", "AMD Ryzen 9 7950X3D 16-Core Processor"},
+		{"apple silicon kept", "Apple M2 Pro", "", "Apple M2 Pro"},
+		{"linux home", "/home/alice/cgroup/bench", "", "/home/<user>/cgroup/bench"},
+		{"macos home", "/Users/bob/go", "", "/Users/<user>/go"},
+		{"windows home", `C:\Users\carol\go`, "", `C:\Users\<user>\go`},
+		{"ec2 hostname", "ip-10-0-1-23.ec2.internal", "", "<host>"},
+		{"mdns hostname", "run on alices-macbook.local", "", "run on <host>"},
+		{"local hostname", "built on buildbox42", "buildbox42", "built on <host>"},
+		{"hex id", "Virtual CPU 0x5003604", "", "Virtual CPU <id>"},
+		{"uuid", "vm 4c4c4544-0042-3510-8051-b4c04f4e3432", "", "vm <id>"},
+		{"serial run", "QEMU Virtual CPU SN8A7F3K2Q9", "", "QEMU Virtual CPU <id>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := anonymizeString(tt.input, tt.hostname); got != tt.want {
+				t.Errorf("anonymizeString(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyExportOptionsAnonymize(t *testing.T) {
+	vd := &VersionData{
+		Metadata: VersionMetadata{
+			System: SystemInfo{
+				Pinning: &PinningInfo{Method: "cpuset", Cgroup: "/home/alice/bench.slice"},
+			},
+		},
+	}
+
+	applyExportOptions(vd, ExportOptions{CPUFallback: "Intel Xeon 0xdeadbeef", Anonymize: true})
+
+	if vd.Metadata.System.CPU != "Intel Xeon <id>" {
+		t.Errorf("CPU = %q, want fallback to be applied then scrubbed", vd.Metadata.System.CPU)
+	}
+	if vd.Metadata.System.Pinning.Cgroup != "/home/<user>/bench.slice" {
+		t.Errorf("Cgroup = %q", vd.Metadata.System.Pinning.Cgroup)
+	}
+}
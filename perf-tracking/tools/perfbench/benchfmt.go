@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/perf/benchfmt"
+)
+
+// Configuration keys written by writeBenchfmt for the result metadata and
+// read back by loadBenchmarkResult. goos, goarch and cpu are the ones go test
+// prints itself.
+const (
+	benchfmtGoVersion     = "go-version"
+	benchfmtGoVersionFull = "go-version-full"
+	benchfmtCommit        = "commit"
+	benchfmtTimestamp     = "timestamp"
+	benchfmtCores         = "cores"
+	benchfmtContention    = "contention-profile"
+)
+
+// applyBenchfmtConfig copies a configuration value into the metadata fields
+// it corresponds to; other keys are ignored
+func applyBenchfmtConfig(m *Metadata, key, value string) {
+	switch key {
+	case "goos":
+		m.Runner.OS = value
+	case "goarch":
+		m.Runner.Arch = value
+	case "cpu":
+		m.Runner.CPU = value
+	case benchfmtCores:
+		if n, err := strconv.Atoi(value); err == nil {
+			m.Runner.Cores = n
+		}
+	case benchfmtGoVersion:
+		m.GoVersion = value
+	case benchfmtGoVersionFull:
+		m.GoVersionFull = value
+	case benchfmtCommit:
+		m.CommitSha = value
+	case benchfmtTimestamp:
+		m.Timestamp = value
+	case benchfmtContention:
+		m.ContentionProfile = value == "on"
+	}
+}
+
+// writeBenchfmt writes result in the Go benchmark format, so it can be fed
+// to benchstat and other golang.org/x/perf tools: the metadata as
+// configuration lines, then every result line. The result lines are read and
+// written with golang.org/x/perf/benchfmt; configuration lines among them
+// (pkg: and the like) are kept unless the metadata sets the key, and test
+// runner chatter such as PASS is dropped.
+func writeBenchfmt(w io.Writer, result BenchmarkResult) error {
+	m := result.Metadata
+	cores, contention := "", ""
+	if m.Runner.Cores > 0 {
+		cores = strconv.Itoa(m.Runner.Cores)
+	}
+	if m.ContentionProfile {
+		contention = onOff(true)
+	}
+	var config []benchfmt.Config
+	set := make(map[string]bool)
+	for _, c := range []struct{ key, value string }{
+		{"goos", m.Runner.OS},
+		{"goarch", m.Runner.Arch},
+		{"cpu", resultCPU(result)},
+		{benchfmtCores, cores},
+		{benchfmtGoVersion, m.GoVersion},
+		{benchfmtGoVersionFull, m.GoVersionFull},
+		{benchfmtCommit, m.CommitSha},
+		{benchfmtTimestamp, m.Timestamp},
+		{benchfmtContention, contention},
+	} {
+		if c.value != "" {
+			config = append(config, benchfmt.Config{Key: c.key, Value: []byte(c.value), File: true})
+			set[c.key] = true
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	writer := benchfmt.NewWriter(bw)
+	reader := benchfmt.NewReader(strings.NewReader(strings.Join(result.Benchmarks, "\n")), "")
+	for reader.Scan() {
+		res, ok := reader.Result().(*benchfmt.Result)
+		if !ok {
+			continue
+		}
+		out := &benchfmt.Result{Config: slices.Clone(config), Name: res.Name, Iters: res.Iters, Values: res.Values}
+		for _, c := range res.Config {
+			if c.File && !set[c.Key] {
+				out.Config = append(out.Config, c)
+			}
+		}
+		if err := writer.Write(out); err != nil {
+			return err
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// convertToBenchfmt writes the result in inputFile (JSON or raw go test
+// output) as a benchmark format file
+func convertToBenchfmt(inputFile, outputFile string) error {
+	result, err := loadBenchmarkResult(inputFile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	if err := writeBenchfmt(f, result); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteBenchfmtRoundTrip(t *testing.T) {
+	var result BenchmarkResult
+	result.Metadata.GoVersion = "1.24"
+	result.Metadata.GoVersionFull = "go1.24.0"
+	result.Metadata.CommitSha = "abc123"
+	result.Metadata.Runner.OS = "linux"
+	result.Metadata.Runner.Arch = "amd64"
+	result.Metadata.Runner.Cores = 16
+	result.Metadata.ContentionProfile = true
+	result.Benchmarks = []string{
+		"goos: linux",
+		"pkg: github.com/example/bench",
+		"cpu: Xeon 8375C",
+		"BenchmarkMapIteration-16 \t 1000\t 100 ns/op\t 0 B/op\t 0 allocs/op",
+		"BenchmarkMapIteration-16 \t 1000\t 102 ns/op\t 0 B/op\t 0 allocs/op",
+		"PASS",
+		"ok  \tgithub.com/example/bench\t1.2s",
+	}
+
+	var sb strings.Builder
+	if err := writeBenchfmt(&sb, result); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	want := "goos: linux\ngoarch: amd64\ncpu: Xeon 8375C\ncores: 16\ngo-version: 1.24\ngo-version-full: go1.24.0\ncommit: abc123\n" +
+		"contention-profile: on\n" +
+		"pkg: github.com/example/bench\n\n" +
+		"BenchmarkMapIteration-16 1000 100 ns/op 0 B/op 0 allocs/op\n" +
+		"BenchmarkMapIteration-16 1000 102 ns/op 0 B/op 0 allocs/op\n"
+	if out != want {
+		t.Errorf("benchfmt output:\n%s\nwant:\n%s", out, want)
+	}
+
+	// Reading it back restores the metadata and every sample
+	path := filepath.Join(t.TempDir(), "result.txt")
+	if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+		t.Fatal(err)
+	}
+	back, err := loadBenchmarkResult(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Metadata.GoVersion != "1.24" || back.Metadata.CommitSha != "abc123" || back.Metadata.Runner.Cores != 16 ||
+		back.Metadata.Runner.CPU != "Xeon 8375C" || back.Metadata.Runner.Arch != "amd64" || !back.Metadata.ContentionProfile {
+		t.Errorf("metadata = %+v", back.Metadata)
+	}
+	if stats := extractBenchmarks(back.Benchmarks)["BenchmarkMapIteration"]; stats == nil || len(stats.Samples) != 2 {
+		t.Errorf("stats = %+v, want two samples", stats)
+	}
+}
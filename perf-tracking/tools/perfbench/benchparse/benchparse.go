@@ -0,0 +1,160 @@
+// Package benchparse reads benchmark results in the Go benchmark format
+// (https://go.googlesource.com/proposal/+/master/design/14313-benchmark-format.md),
+// as printed by go test -bench and read by benchstat: configuration lines
+// such as "goos: linux" and one result line per benchmark run, e.g.
+//
+//	BenchmarkSmallAllocation-16    	1000000000	         3.000 ns/op	       0 B/op	       0 allocs/op
+//	BenchmarkAESCTR/Size1KB-16     	 2705214	      1330 ns/op	 770.04 MB/s	     608 B/op	       3 allocs/op
+//	BenchmarkTCPConnect/Sequential-16	   43210	     25966 ns/op	   24012 p50-ns	   61440 p99-ns	     1024 B/op	      20 allocs/op
+//
+// Other lines, such as PASS or test output, are skipped. Lines are read with
+// golang.org/x/perf/benchfmt, the reader benchstat uses, and converted to
+// the fields the perf-tracking tools work with.
+package benchparse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/perf/benchfmt"
+)
+
+// Result is one result line.
+type Result struct {
+	Name        string // without the -GOMAXPROCS suffix
+	Iterations  int64
+	NsPerOp     float64
+	MBPerSec    float64 // 0 when the benchmark does not call b.SetBytes
+	BytesPerOp  int64
+	AllocsPerOp int64
+	Metrics     map[string]float64 // custom b.ReportMetric units, e.g. "p99-ns"
+
+	// HasBytesPerOp and HasAllocsPerOp report whether the line carried B/op
+	// and allocs/op, which go test prints only with -benchmem or
+	// b.ReportAllocs; otherwise the fields are 0 without meaning it.
+	HasBytesPerOp  bool
+	HasAllocsPerOp bool
+}
+
+// ErrInvalidLine is wrapped by the errors of ParseLine.
+var ErrInvalidLine = errors.New("invalid benchmark line format")
+
+// ParseLine parses a result line. The name is followed by the iteration
+// count and value/unit pairs in any order, of which ns/op is required. The
+// -GOMAXPROCS suffix is dropped from the name. Units other than ns/op, MB/s,
+// B/op and allocs/op go to Metrics.
+func ParseLine(line string) (Result, error) {
+	r := benchfmt.NewReader(strings.NewReader(line), "")
+	for r.Scan() {
+		switch rec := r.Result().(type) {
+		case *benchfmt.Result:
+			return FromBenchfmt(rec)
+		case *benchfmt.SyntaxError:
+			return Result{}, fmt.Errorf("%w: %s", ErrInvalidLine, rec.Msg)
+		}
+	}
+	return Result{}, ErrInvalidLine
+}
+
+// FromBenchfmt converts a result read by a benchfmt.Reader. Values are
+// taken in the units the benchmark reported them in, not the tidied ones
+// benchfmt keeps (sec/op for ns/op and the like).
+func FromBenchfmt(res *benchfmt.Result) (Result, error) {
+	r := Result{Name: TrimProcsSuffix("Benchmark" + string(res.Name)), Iterations: int64(res.Iters)}
+	hasNs := false
+	for _, v := range res.Values {
+		value, unit := v.Value, v.Unit
+		if v.OrigUnit != "" {
+			value, unit = v.OrigValue, v.OrigUnit
+		}
+		switch unit {
+		case "ns/op":
+			r.NsPerOp = value
+			hasNs = true
+		case "MB/s":
+			r.MBPerSec = value
+		case "B/op":
+			r.BytesPerOp = int64(value)
+			r.HasBytesPerOp = true
+		case "allocs/op":
+			r.AllocsPerOp = int64(value)
+			r.HasAllocsPerOp = true
+		default:
+			if r.Metrics == nil {
+				r.Metrics = make(map[string]float64)
+			}
+			r.Metrics[unit] = value
+		}
+	}
+	if !hasNs {
+		return Result{}, fmt.Errorf("%w: no ns/op", ErrInvalidLine)
+	}
+	return r, nil
+}
+
+// TrimProcsSuffix drops the -GOMAXPROCS suffix go test appends to a
+// benchmark name. Dashes elsewhere in the name, as in
+// BenchmarkDecode/utf-8, are kept.
+func TrimProcsSuffix(name string) string {
+	idx := strings.LastIndex(name, "-")
+	if idx == -1 || idx == len(name)-1 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[idx+1:]); err != nil {
+		return name
+	}
+	return name[:idx]
+}
+
+// ParseConfig splits a configuration line ("key: value"). Keys start with a
+// lower-case letter and contain no spaces or upper-case letters.
+func ParseConfig(line string) (key, value string, ok bool) {
+	key, value, found := strings.Cut(line, ":")
+	if !found || key == "" {
+		return "", "", false
+	}
+	for i, r := range key {
+		if (i == 0 && !unicode.IsLower(r)) || unicode.IsSpace(r) || unicode.IsUpper(r) {
+			return "", "", false
+		}
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// File is a parsed results file.
+type File struct {
+	// Config holds the last value of every configuration key in effect for
+	// a result, e.g. goos, goarch and cpu as printed by go test
+	Config  map[string]string
+	Results []Result // in file order, several per benchmark with -count
+}
+
+// Parse reads a results file.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{Config: make(map[string]string)}
+	reader := benchfmt.NewReader(r, "")
+	for reader.Scan() {
+		rec, ok := reader.Result().(*benchfmt.Result)
+		if !ok {
+			continue
+		}
+		res, err := FromBenchfmt(rec)
+		if err != nil {
+			continue
+		}
+		f.Results = append(f.Results, res)
+		for _, c := range rec.Config {
+			if c.File {
+				f.Config[c.Key] = string(c.Value)
+			}
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
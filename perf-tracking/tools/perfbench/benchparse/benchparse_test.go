@@ -0,0 +1,124 @@
+package benchparse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLineThroughput(t *testing.T) {
+	stats, err := ParseLine("BenchmarkAESCTR/Size1KB-16     \t 2705214\t      1330 ns/op\t 770.04 MB/s\t     608 B/op\t       3 allocs/op")
+	if err != nil {
+		t.Fatalf("ParseLine failed: %v", err)
+	}
+	if stats.Name != "BenchmarkAESCTR/Size1KB" || stats.NsPerOp != 1330 || stats.MBPerSec != 770.04 ||
+		stats.BytesPerOp != 608 || stats.AllocsPerOp != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	stats, err = ParseLine("BenchmarkSmallAllocation-16 \t1000000000\t 3.000 ns/op\t 0 B/op\t 0 allocs/op")
+	if err != nil {
+		t.Fatalf("ParseLine failed: %v", err)
+	}
+	if stats.MBPerSec != 0 || stats.NsPerOp != 3 || !stats.HasBytesPerOp || !stats.HasAllocsPerOp {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Without -benchmem the memory units are absent, not zero
+	stats, err = ParseLine("BenchmarkSmallAllocation-16 \t1000000000\t 3.000 ns/op")
+	if err != nil {
+		t.Fatalf("ParseLine failed: %v", err)
+	}
+	if stats.HasBytesPerOp || stats.HasAllocsPerOp {
+		t.Errorf("memory units reported without -benchmem: %+v", stats)
+	}
+}
+
+func TestParseLineCustomMetrics(t *testing.T) {
+	stats, err := ParseLine("BenchmarkTCPConnect/Sequential-16 \t 43210\t 25966 ns/op\t 24012 p50-ns\t 61440 p99-ns\t 1024 B/op\t 20 allocs/op")
+	if err != nil {
+		t.Fatalf("ParseLine failed: %v", err)
+	}
+	if stats.NsPerOp != 25966 || stats.BytesPerOp != 1024 || stats.AllocsPerOp != 20 {
+		t.Errorf("standard units lost after custom metrics: %+v", stats)
+	}
+	if stats.Metrics["p50-ns"] != 24012 || stats.Metrics["p99-ns"] != 61440 || len(stats.Metrics) != 2 {
+		t.Errorf("metrics = %v", stats.Metrics)
+	}
+}
+
+func TestParseLinePercentMetric(t *testing.T) {
+	stats, err := ParseLine("BenchmarkTLSResume/Resumed-16 \t 1929\t 849183 ns/op\t 100.0 resumed-%\t 6144 B/op\t 70 allocs/op")
+	if err != nil {
+		t.Fatalf("ParseLine failed: %v", err)
+	}
+	if got := stats.Metrics["resumed-%"]; got != 100 {
+		t.Errorf("resumed-%% = %v, want 100 (metrics %v)", got, stats.Metrics)
+	}
+	if stats.BytesPerOp != 6144 || stats.AllocsPerOp != 70 {
+		t.Errorf("standard units lost after percent metric: %+v", stats)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		line, key, value string
+		ok               bool
+	}{
+		{"goos: linux", "goos", "linux", true},
+		{"cpu: Intel(R) Xeon(R) CPU @ 2.20GHz", "cpu", "Intel(R) Xeon(R) CPU @ 2.20GHz", true},
+		{"go-version-full: go1.24.0", "go-version-full", "go1.24.0", true},
+		{"PASS", "", "", false},
+		{"Goos: linux", "", "", false},
+		{"some key: value", "", "", false},
+		{"ok  \tpkg\t1.2s", "", "", false},
+	}
+	for _, tt := range tests {
+		key, value, ok := ParseConfig(tt.line)
+		if key != tt.key || value != tt.value || ok != tt.ok {
+			t.Errorf("ParseConfig(%q) = %q, %q, %v", tt.line, key, value, ok)
+		}
+	}
+}
+
+func TestParseLineBenchfmt(t *testing.T) {
+	// Units in any order and dashes inside sub-benchmark names
+	stats, err := ParseLine("BenchmarkDecode/utf-8-16 1000 512 B/op 2 allocs/op 1500 ns/op")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Name != "BenchmarkDecode/utf-8" || stats.NsPerOp != 1500 || stats.BytesPerOp != 512 || stats.AllocsPerOp != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	if stats, err := ParseLine("BenchmarkNoProcs 10 7 ns/op"); err != nil || stats.Name != "BenchmarkNoProcs" {
+		t.Errorf("no -N suffix: %+v, %v", stats, err)
+	}
+	for _, line := range []string{
+		"BenchmarkOnlyMetric-8 1000 12 p99-ns",
+		"BenchmarkFailed-8 --- FAIL: BenchmarkFailed",
+		"BenchmarkRunning",
+	} {
+		if _, err := ParseLine(line); err == nil {
+			t.Errorf("ParseLine(%q) succeeded, want error", line)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "goos: linux\ngoarch: amd64\npkg: example.com/a\n" +
+		"BenchmarkA-8 \t 1000\t 10 ns/op\n" +
+		"--- BENCH: BenchmarkA-8\n" +
+		"pkg: example.com/b\n" +
+		"BenchmarkB-8 \t 1000\t 20 ns/op\t 8 B/op\t 1 allocs/op\n" +
+		"PASS\nok  \texample.com/b\t1.2s\n"
+	f, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Results) != 2 || f.Results[0].Name != "BenchmarkA" || f.Results[1].BytesPerOp != 8 {
+		t.Errorf("results = %+v", f.Results)
+	}
+	if f.Config["goos"] != "linux" || f.Config["goarch"] != "amd64" || f.Config["pkg"] != "example.com/b" {
+		t.Errorf("config = %v", f.Config)
+	}
+}
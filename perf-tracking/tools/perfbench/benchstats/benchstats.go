@@ -0,0 +1,140 @@
+// Package benchstats summarizes benchmark samples: the mean, spread and
+// percentiles of the values a benchmark reported over repeated runs
+// (go test -count).
+package benchstats
+
+import (
+	"math"
+	"slices"
+)
+
+// Mean returns the arithmetic mean of xs, 0 when xs is empty.
+func Mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// StdDev returns the population standard deviation of xs, the spread of
+// the samples themselves; 0 when xs is empty.
+func StdDev(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	return math.Sqrt(sumSquaredDiffs(xs) / float64(len(xs)))
+}
+
+// SampleStdDev returns the sample standard deviation of xs (Bessel's
+// correction), an estimate of the spread of the population xs was drawn
+// from; 0 for fewer than two values.
+func SampleStdDev(xs []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	return math.Sqrt(sumSquaredDiffs(xs) / float64(len(xs)-1))
+}
+
+func sumSquaredDiffs(xs []float64) float64 {
+	mean := Mean(xs)
+	var sum float64
+	for _, x := range xs {
+		sum += (x - mean) * (x - mean)
+	}
+	return sum
+}
+
+// CV returns the coefficient of variation, stddev/mean, given both; 0 when
+// the mean is not positive.
+func CV(stddev, mean float64) float64 {
+	if mean <= 0 {
+		return 0
+	}
+	return stddev / mean
+}
+
+// tCritical95 holds the two-sided 95% critical values of Student's t
+// distribution for 1 to 30 degrees of freedom
+var tCritical95 = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// tCritical returns the 95% critical value for df degrees of freedom. Past
+// the table it rounds df down to 30, 40, 60 or 120, which widens the
+// interval slightly rather than narrowing it.
+func tCritical(df int) float64 {
+	switch {
+	case df <= len(tCritical95):
+		return tCritical95[df-1]
+	case df < 40:
+		return 2.042
+	case df < 60:
+		return 2.021
+	case df < 120:
+		return 2.000
+	default:
+		return 1.980
+	}
+}
+
+// MeanCI returns the 95% confidence interval of the mean of xs from
+// Student's t distribution, which assumes independent, roughly normal
+// samples. With fewer than two values the interval is the mean itself.
+func MeanCI(xs []float64) (lo, hi float64) {
+	mean := Mean(xs)
+	if len(xs) < 2 {
+		return mean, mean
+	}
+	h := tCritical(len(xs)-1) * SampleStdDev(xs) / math.Sqrt(float64(len(xs)))
+	return mean - h, mean + h
+}
+
+// Percentile returns the p-th percentile (0-100) of sorted using linear
+// interpolation between closest ranks, 0 when sorted is empty.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// Median returns the median of xs, which need not be sorted.
+func Median(xs []float64) float64 {
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	return Percentile(sorted, 50)
+}
+
+// Summary describes the samples of one benchmark.
+type Summary struct {
+	N      int
+	Mean   float64
+	StdDev float64 // population standard deviation
+	CV     float64 // StdDev / Mean
+	P50    float64
+	P95    float64
+}
+
+// Summarize computes the Summary of xs.
+func Summarize(xs []float64) Summary {
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	s := Summary{
+		N:      len(xs),
+		Mean:   Mean(xs),
+		StdDev: StdDev(xs),
+		P50:    Percentile(sorted, 50),
+		P95:    Percentile(sorted, 95),
+	}
+	s.CV = CV(s.StdDev, s.Mean)
+	return s
+}
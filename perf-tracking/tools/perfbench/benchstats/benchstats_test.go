@@ -0,0 +1,72 @@
+package benchstats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+	tests := []struct {
+		p    float64
+		want float64
+	}{{0, 10}, {50, 30}, {95, 48}, {100, 50}}
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %v, want 0", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]float64{4, 2, 6, 8})
+	if s.N != 4 || s.Mean != 5 || s.P50 != 5 || math.Abs(s.P95-7.7) > 1e-9 {
+		t.Errorf("Summarize = %+v", s)
+	}
+	// Population variance is 20/4, sample variance 20/3
+	if s.StdDev != math.Sqrt(5) || s.CV != math.Sqrt(5)/5 {
+		t.Errorf("StdDev = %v, CV = %v", s.StdDev, s.CV)
+	}
+	if got := SampleStdDev([]float64{4, 2, 6, 8}); got != math.Sqrt(20.0/3) {
+		t.Errorf("SampleStdDev = %v, want %v", got, math.Sqrt(20.0/3))
+	}
+	if got := Summarize(nil); got != (Summary{}) {
+		t.Errorf("Summarize(nil) = %+v, want zero", got)
+	}
+	if got := SampleStdDev([]float64{3}); got != 0 {
+		t.Errorf("SampleStdDev of one value = %v, want 0", got)
+	}
+}
+
+func TestMedianUnsorted(t *testing.T) {
+	xs := []float64{9, 1, 5}
+	if got := Median(xs); got != 5 {
+		t.Errorf("Median = %v, want 5", got)
+	}
+	if xs[0] != 9 {
+		t.Errorf("Median reordered its input: %v", xs)
+	}
+}
+
+func TestMeanCI(t *testing.T) {
+	// Mean 5, sample stddev sqrt(20/3), t(3) = 3.182
+	lo, hi := MeanCI([]float64{4, 2, 6, 8})
+	h := 3.182 * math.Sqrt(20.0/3) / 2
+	if math.Abs(lo-(5-h)) > 1e-9 || math.Abs(hi-(5+h)) > 1e-9 {
+		t.Errorf("MeanCI = [%v, %v], want [%v, %v]", lo, hi, 5-h, 5+h)
+	}
+	if lo, hi := MeanCI([]float64{7}); lo != 7 || hi != 7 {
+		t.Errorf("MeanCI of one value = [%v, %v], want [7, 7]", lo, hi)
+	}
+	for _, tt := range []struct {
+		df   int
+		want float64
+	}{{1, 12.706}, {30, 2.042}, {35, 2.042}, {59, 2.021}, {60, 2.000}, {500, 1.980}} {
+		if got := tCritical(tt.df); got != tt.want {
+			t.Errorf("tCritical(%d) = %v, want %v", tt.df, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,966 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/benchparse"
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/benchstats"
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+type Metadata struct {
+	Timestamp     string `json:"timestamp"`
+	GoVersion     string `json:"go_version"`
+	GoVersionFull string `json:"go_version_full"`
+	CommitSha     string `json:"commit_sha"`
+	// Collected with mutex and block profiling on (--contention-profile),
+	// which slows contended paths
+	ContentionProfile bool `json:"contention_profile,omitempty"`
+	Runner            struct {
+		OS    string `json:"os"`
+		Arch  string `json:"arch"`
+		CPU   string `json:"cpu,omitempty"`
+		Cores int    `json:"cores"`
+	} `json:"runner"`
+}
+
+type BenchmarkResult struct {
+	Metadata   Metadata `json:"metadata"`
+	Benchmarks []string `json:"benchmarks"`
+}
+
+// BenchmarkStats is the last result line of a benchmark together with the
+// samples of all of them
+type BenchmarkStats struct {
+	benchparse.Result
+	Samples []float64 // ns/op of every result line for the benchmark, in file order
+
+	// Like Samples, for each of comparedMetrics and custom metrics the
+	// benchmark reported
+	MetricSamples map[string][]float64
+}
+
+// comparedMetrics are the metrics compareResults compares besides ns/op.
+// Many optimizations are allocation wins rather than latency wins.
+var comparedMetrics = []struct {
+	Unit           string
+	HigherIsBetter bool
+	value          func(*BenchmarkStats) (float64, bool) // false when not reported
+}{
+	{"B/op", false, func(s *BenchmarkStats) (float64, bool) { return float64(s.BytesPerOp), s.HasBytesPerOp }},
+	{"allocs/op", false, func(s *BenchmarkStats) (float64, bool) { return float64(s.AllocsPerOp), s.HasAllocsPerOp }},
+	{"MB/s", true, func(s *BenchmarkStats) (float64, bool) { return s.MBPerSec, s.MBPerSec > 0 }},
+}
+
+// higherIsBetterUnits are the custom b.ReportMetric units, other than rates,
+// where a drop is the regression
+var higherIsBetterUnits = []string{"resumed-%", "reuse-%"}
+
+// metricHigherIsBetter reports the direction of a metric. Custom metrics
+// are better higher when they are rates, such as req/s or handshakes/sec, or
+// in higherIsBetterUnits; latencies, sizes and counts are better lower.
+func metricHigherIsBetter(unit string) bool {
+	for _, m := range comparedMetrics {
+		if m.Unit == unit {
+			return m.HigherIsBetter
+		}
+	}
+	return strings.HasSuffix(unit, "/s") || strings.HasSuffix(unit, "/sec") || slices.Contains(higherIsBetterUnits, unit)
+}
+
+// MetricDelta is the change of one of comparedMetrics or of a custom
+// metric, computed like the ns/op change of a Comparison: medians,
+// Mann-Whitney p-value and the same significance rule.
+type MetricDelta struct {
+	Baseline       float64 `json:"baseline"` // median over the samples
+	Target         float64 `json:"target"`
+	DeltaPercent   float64 `json:"delta_percent"`
+	PValue         float64 `json:"p_value,omitempty"`
+	Significant    bool    `json:"significant"`
+	HigherIsBetter bool    `json:"higher_is_better,omitempty"`
+}
+
+// regressionPercent is how much worse the target is, in percent: the delta
+// for lower-is-better metrics, its negation for throughput
+func (m MetricDelta) regressionPercent() float64 {
+	if m.HigherIsBetter {
+		return -m.DeltaPercent
+	}
+	return m.DeltaPercent
+}
+
+type Comparison struct {
+	Benchmark      string   `json:"benchmark"`
+	Group          string   `json:"group"`
+	Variant        []string `json:"variant,omitempty"`
+	BaselineNs     float64  `json:"baseline_ns"` // median over the samples
+	TargetNs       float64  `json:"target_ns"`
+	DeltaPercent   float64  `json:"delta_percent"`
+	Delta          float64  `json:"delta"`        // in DeltaPolicy's terms, see comparisonPolicy
+	DeltaPolicy    string   `json:"delta_policy"` // ratio, difference or log
+	BaselineAllocs int64    `json:"baseline_allocs"`
+	TargetAllocs   int64    `json:"target_allocs"`
+
+	// Mann-Whitney U test of the baseline vs target ns/op samples. PValue
+	// is omitted when either side has fewer than minTestSamples samples.
+	BaselineSamples int     `json:"baseline_samples"`
+	TargetSamples   int     `json:"target_samples"`
+	PValue          float64 `json:"p_value,omitempty"`
+	Significant     bool    `json:"significant"`
+
+	// 95% confidence intervals of the baseline and target mean ns/op, nil
+	// for a side with fewer than two samples. A change is only significant
+	// when the two do not overlap.
+	BaselineCI *Interval `json:"baseline_ci_ns,omitempty"`
+	TargetCI   *Interval `json:"target_ci_ns,omitempty"`
+
+	// NoiseBandPercent is the run-to-run variation seen for the benchmark in
+	// earlier exports (-noise), 0 when unknown. Changes within it are not
+	// significant however small their p-value.
+	NoiseBandPercent float64 `json:"noise_band_percent,omitempty"`
+
+	// Changes of comparedMetrics and custom metrics, keyed by unit, for the
+	// ones both sides reported
+	Metrics map[string]*MetricDelta `json:"metrics,omitempty"`
+}
+
+// Interval is a confidence interval of a mean
+type Interval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// meanInterval returns the 95% confidence interval of the mean of samples,
+// nil for fewer than two
+func meanInterval(samples []float64) *Interval {
+	if len(samples) < 2 {
+		return nil
+	}
+	low, high := benchstats.MeanCI(samples)
+	return &Interval{Low: low, High: high}
+}
+
+// relativeHalfWidth returns the half width of i relative to its middle, in
+// percent. It is the same for ns/op and for its reciprocal, ops/sec.
+func (i Interval) relativeHalfWidth() float64 {
+	if i.High+i.Low <= 0 {
+		return math.Inf(1)
+	}
+	return (i.High - i.Low) / (i.High + i.Low) * 100
+}
+
+// minTestSamples is the number of samples each side needs for compareResults
+// to run a significance test. Fewer samples cannot reach significanceLevel.
+const minTestSamples = 4
+
+// tested reports whether c has a p-value
+func (c Comparison) tested() bool {
+	return c.BaselineSamples >= minTestSamples && c.TargetSamples >= minTestSamples
+}
+
+// change returns the change of c as measured by its policy. Comparisons made
+// without one measure by ratio, which DeltaPercent already holds.
+func (c Comparison) change() float64 {
+	if c.DeltaPolicy == "" || c.DeltaPolicy == policyRatio {
+		return c.DeltaPercent
+	}
+	return c.Delta
+}
+
+// intervalsSeparate reports whether the confidence intervals of c, when
+// both sides have one, do not overlap and order the sides the way the
+// change does: a slower target lies entirely above the baseline. Without
+// both intervals there is nothing to contradict the point estimate.
+func (c Comparison) intervalsSeparate() bool {
+	if c.BaselineCI == nil || c.TargetCI == nil {
+		return true
+	}
+	if c.DeltaPercent > 0 {
+		return c.TargetCI.Low > c.BaselineCI.High
+	}
+	return c.TargetCI.High < c.BaselineCI.Low
+}
+
+// evident reports whether the samples show a change at all: significant
+// when tested, and with separate confidence intervals when both sides have
+// them. Comparisons with too few samples for either fall back to the point
+// estimate.
+func (c Comparison) evident() bool {
+	return (!c.tested() || c.PValue < significanceLevel) && c.intervalsSeparate()
+}
+
+// significant reports whether c is a real change: larger than the noise
+// threshold and the benchmark's noise band, and evident from the samples
+func (c Comparison) significant() bool {
+	if math.Abs(c.DeltaPercent) <= max(noiseThresholdPercent, c.NoiseBandPercent) {
+		return false
+	}
+	return c.evident()
+}
+
+// withinNoise reports whether c would be a real change were it not within
+// the benchmark's noise band
+func (c Comparison) withinNoise() bool {
+	if c.significant() || math.Abs(c.DeltaPercent) <= noiseThresholdPercent {
+		return false
+	}
+	return c.evident()
+}
+
+// loadBenchmarkResult reads a result for comparison: either the JSON wrapper
+// written by the runner or raw `go test -bench` output in the Go benchmark
+// format. For raw output the metadata comes from its configuration lines
+// (goos, goarch, cpu, and the keys writeBenchfmt adds) and, when present,
+// the _metadata.json sidecar next to the file; without a go-version line the
+// file name stands in for the Go version.
+func loadBenchmarkResult(path string) (BenchmarkResult, error) {
+	var result BenchmarkResult
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return result, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return result, nil
+	}
+
+	result.Metadata.GoVersion = filepath.Base(path)
+	runner := &result.Metadata.Runner
+	found := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if _, err := benchparse.ParseLine(line); err == nil {
+			found = true
+		} else if key, value, ok := benchparse.ParseConfig(line); ok {
+			applyBenchfmtConfig(&result.Metadata, key, value)
+		}
+		result.Benchmarks = append(result.Benchmarks, line)
+	}
+	if !found {
+		return result, fmt.Errorf("%s is neither result JSON nor go test -bench output", path)
+	}
+
+	meta, err := loadRunMetadata(path)
+	if err != nil {
+		return result, err
+	}
+	if meta != nil {
+		if meta.System.CPU != "" {
+			runner.CPU = meta.System.CPU
+		}
+		if meta.System.LogicalCores != 0 {
+			runner.Cores = meta.System.LogicalCores
+		}
+		if meta.System.OS != "" {
+			runner.OS = meta.System.OS
+		}
+		if meta.System.Arch != "" {
+			runner.Arch = meta.System.Arch
+		}
+		if meta.ContentionProfile {
+			result.Metadata.ContentionProfile = true
+		}
+	}
+	return result, nil
+}
+
+// loadPooledResults loads a comma-separated list of results of one
+// configuration, such as three runs of the suite on the same machine, as a
+// single result whose benchmarks have the samples of all of them. A single
+// path is loaded as is. The runs must agree on the machine and on the Go
+// version where they name one; a raw result without one is named after its
+// first file.
+func loadPooledResults(paths string) (BenchmarkResult, error) {
+	files := strings.Split(paths, ",")
+	if len(files) == 1 {
+		return loadBenchmarkResult(paths)
+	}
+
+	var pooled BenchmarkResult
+	var versions []string
+	for i, path := range files {
+		path = strings.TrimSpace(path)
+		r, err := loadBenchmarkResult(path)
+		if err != nil {
+			return pooled, err
+		}
+		if i == 0 {
+			pooled.Metadata = r.Metadata
+		} else {
+			if mismatches := machineMismatches(pooled, r); len(mismatches) > 0 {
+				return pooled, fmt.Errorf("cannot pool %s with %s, collected on a different machine: %s",
+					path, files[0], strings.Join(mismatches, ", "))
+			}
+			if r.Metadata.ContentionProfile != pooled.Metadata.ContentionProfile {
+				return pooled, fmt.Errorf("cannot pool %s with %s: contention profiling %s vs %s",
+					path, files[0], onOff(r.Metadata.ContentionProfile), onOff(pooled.Metadata.ContentionProfile))
+			}
+			if r.Metadata.Timestamp > pooled.Metadata.Timestamp {
+				pooled.Metadata.Timestamp = r.Metadata.Timestamp
+			}
+			if r.Metadata.CommitSha != pooled.Metadata.CommitSha {
+				pooled.Metadata.CommitSha = ""
+			}
+		}
+		if v := r.Metadata.GoVersion; v != filepath.Base(path) && !slices.Contains(versions, v) {
+			versions = append(versions, v)
+		}
+		pooled.Benchmarks = append(pooled.Benchmarks, r.Benchmarks...)
+	}
+	if len(versions) > 1 {
+		return pooled, fmt.Errorf("cannot pool runs of different Go versions: %s", strings.Join(versions, ", "))
+	}
+	if len(versions) == 1 {
+		pooled.Metadata.GoVersion = versions[0]
+	}
+	return pooled, nil
+}
+
+// latestBaseline finds the baseline for -baseline-latest: the newest main
+// result file of the highest Go version among the go<version>/ directories
+// of resultsDir, with versions ordered as rebuildIndex orders them. The
+// directory holding target is skipped, so a run just written next to the
+// earlier ones is compared with the previous version rather than itself.
+// It returns the file and its version.
+func latestBaseline(resultsDir, target string) (string, string, error) {
+	entries, err := os.ReadDir(resultsDir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read results directory: %w", err)
+	}
+	targetDir, err := filepath.Abs(filepath.Dir(target))
+	if err != nil {
+		return "", "", err
+	}
+
+	var file, version string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+			continue
+		}
+		v := strings.TrimPrefix(entry.Name(), "go")
+		versionDir := filepath.Join(resultsDir, entry.Name())
+		if abs, err := filepath.Abs(versionDir); err == nil && abs == targetDir {
+			continue
+		}
+		if version != "" && compareVersionStrings(v, version) <= 0 {
+			continue
+		}
+		if mainFiles := mainResultFiles(versionDir); len(mainFiles) > 0 {
+			file, version = mainFiles[0], v
+		}
+	}
+	if file == "" {
+		return "", "", fmt.Errorf("no result files under %s/go*/ to use as baseline", resultsDir)
+	}
+	return file, version, nil
+}
+
+func extractBenchmarks(benchmarkLines []string) map[string]*BenchmarkStats {
+	results := make(map[string]*BenchmarkStats)
+
+	for _, line := range benchmarkLines {
+		r, err := benchparse.ParseLine(line)
+		if err != nil {
+			continue
+		}
+		stats := &BenchmarkStats{Result: r}
+		// Keep the last (most recent) result for each benchmark along with
+		// the ns/op of all of them
+		if prev, ok := results[stats.Name]; ok {
+			stats.Samples = prev.Samples
+			stats.MetricSamples = prev.MetricSamples
+		}
+		stats.Samples = append(stats.Samples, stats.NsPerOp)
+		for _, m := range comparedMetrics {
+			if v, ok := m.value(stats); ok {
+				if stats.MetricSamples == nil {
+					stats.MetricSamples = make(map[string][]float64)
+				}
+				stats.MetricSamples[m.Unit] = append(stats.MetricSamples[m.Unit], v)
+			}
+		}
+		for unit, v := range stats.Metrics {
+			if stats.MetricSamples == nil {
+				stats.MetricSamples = make(map[string][]float64)
+			}
+			stats.MetricSamples[unit] = append(stats.MetricSamples[unit], v)
+		}
+		results[stats.Name] = stats
+	}
+
+	return results
+}
+
+// BenchmarkDrift lists the benchmarks only one side has, which
+// compareResults cannot compare: new or renamed benchmarks in the target and
+// ones it dropped or failed to run
+type BenchmarkDrift struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// benchmarkDrift returns the sorted names present in only one of baseline
+// and target
+func benchmarkDrift(baseline, target map[string]*BenchmarkStats) BenchmarkDrift {
+	var d BenchmarkDrift
+	for name := range target {
+		if _, ok := baseline[name]; !ok {
+			d.Added = append(d.Added, name)
+		}
+	}
+	for name := range baseline {
+		if _, ok := target[name]; !ok {
+			d.Removed = append(d.Removed, name)
+		}
+	}
+	slices.Sort(d.Added)
+	slices.Sort(d.Removed)
+	return d
+}
+
+// compareResults compares the benchmarks both sides ran, measuring each
+// change with policy besides DeltaPercent
+func compareResults(baseline, target map[string]*BenchmarkStats, policy comparisonPolicy) []Comparison {
+	var comparisons []Comparison
+
+	for name, baseStats := range baseline {
+		targetStats, exists := target[name]
+		if !exists {
+			continue
+		}
+
+		baseNs, targetNs := medianNs(baseStats), medianNs(targetStats)
+		// A body optimized away runs in 0 ns/op. As in compareMetric, a
+		// change from zero is measured against 1 ns so it stays finite.
+		shift := 0.0
+		if baseNs == 0 {
+			shift = 1
+		}
+		delta := ((targetNs - baseNs) / (baseNs + shift)) * 100
+		// The policies take positive values, and log2 of a zero target is
+		// -Inf, which JSON can't encode: shift both when either is zero
+		policyShift := shift
+		if targetNs == 0 {
+			policyShift = 1
+		}
+
+		group, variant := splitBenchmarkName(name)
+		c := Comparison{
+			Benchmark:       name,
+			Group:           group,
+			Variant:         variant,
+			BaselineNs:      baseNs,
+			TargetNs:        targetNs,
+			DeltaPercent:    delta,
+			Delta:           policy.delta(baseNs+policyShift, targetNs+policyShift),
+			DeltaPolicy:     policy.name(),
+			BaselineAllocs:  baseStats.AllocsPerOp,
+			TargetAllocs:    targetStats.AllocsPerOp,
+			BaselineSamples: len(baseStats.Samples),
+			TargetSamples:   len(targetStats.Samples),
+			BaselineCI:      meanInterval(baseStats.Samples),
+			TargetCI:        meanInterval(targetStats.Samples),
+		}
+		if c.tested() {
+			c.PValue = mannWhitneyU(baseStats.Samples, targetStats.Samples)
+		}
+		c.Significant = c.significant()
+		for unit, base := range baseStats.MetricSamples {
+			target := targetStats.MetricSamples[unit]
+			if len(base) == 0 || len(target) == 0 {
+				continue
+			}
+			if c.Metrics == nil {
+				c.Metrics = make(map[string]*MetricDelta)
+			}
+			c.Metrics[unit] = compareMetric(base, target, metricHigherIsBetter(unit))
+		}
+		comparisons = append(comparisons, c)
+	}
+
+	return comparisons
+}
+
+// compareMetric compares the samples of a metric. A change from
+// zero is measured against 1, so going from 0 to 2 allocs/op reads as +200%
+// rather than an infinite one.
+func compareMetric(base, target []float64, higherIsBetter bool) *MetricDelta {
+	m := &MetricDelta{
+		Baseline:       benchstats.Median(base),
+		Target:         benchstats.Median(target),
+		HigherIsBetter: higherIsBetter,
+	}
+	denominator := m.Baseline
+	if denominator == 0 {
+		denominator = 1
+	}
+	m.DeltaPercent = (m.Target - m.Baseline) / denominator * 100
+
+	tested := len(base) >= minTestSamples && len(target) >= minTestSamples
+	if tested {
+		m.PValue = mannWhitneyU(base, target)
+	}
+	m.Significant = math.Abs(m.DeltaPercent) > noiseThresholdPercent && (!tested || m.PValue < significanceLevel)
+	return m
+}
+
+// medianNs returns the median ns/op of the samples of s, or NsPerOp when it
+// has none
+func medianNs(s *BenchmarkStats) float64 {
+	if len(s.Samples) == 0 {
+		return s.NsPerOp
+	}
+	return benchstats.Median(s.Samples)
+}
+
+// noiseThresholdPercent is the |delta| below which a change is reported as
+// insignificant, whatever its p-value
+const noiseThresholdPercent = 1.0
+
+// ComparisonSummary is the compact verdict written by -summary-json for
+// scripts that don't need the full comparison
+type ComparisonSummary struct {
+	Verdict             string        `json:"verdict"` // "regressed", "improved" or "unchanged"
+	Benchmarks          int           `json:"benchmarks"`
+	Regressions         int           `json:"regressions"`
+	Improvements        int           `json:"improvements"`
+	Insignificant       int           `json:"insignificant"`
+	WorstRegression     *SummaryEntry `json:"worst_regression,omitempty"`
+	GeomeanRatio        float64       `json:"geomean_ratio"` // target/baseline; <1 is faster
+	GeomeanDeltaPercent float64       `json:"geomean_delta_percent"`
+	// Categories holds the geomean of every category present, in
+	// benchmarkCategories order
+	Categories []CategorySummary `json:"categories,omitempty"`
+}
+
+// CategorySummary is the geometric mean change of one benchmark category
+type CategorySummary struct {
+	Category            string  `json:"category"`
+	Benchmarks          int     `json:"benchmarks"`
+	GeomeanRatio        float64 `json:"geomean_ratio"`
+	GeomeanDeltaPercent float64 `json:"geomean_delta_percent"`
+}
+
+// geomean accumulates the geometric mean of target/baseline ratios
+type geomean struct {
+	logSum float64
+	count  int
+}
+
+func (g *geomean) add(c Comparison) {
+	if c.BaselineNs > 0 && c.TargetNs > 0 {
+		g.logSum += math.Log(c.TargetNs / c.BaselineNs)
+		g.count++
+	}
+}
+
+// ratio returns the geometric mean ratio, 1 when nothing was added
+func (g geomean) ratio() float64 {
+	if g.count == 0 {
+		return 1
+	}
+	return math.Exp(g.logSum / float64(g.count))
+}
+
+// SummaryEntry identifies a single benchmark in a ComparisonSummary
+type SummaryEntry struct {
+	Benchmark    string  `json:"benchmark"`
+	DeltaPercent float64 `json:"delta_percent"`
+}
+
+// summarizeComparisons reduces comparisons to a single verdict
+func summarizeComparisons(comparisons []Comparison) ComparisonSummary {
+	summary := ComparisonSummary{Benchmarks: len(comparisons)}
+
+	var overall geomean
+	categories := make(map[string]*geomean)
+	counts := make(map[string]int)
+	for _, c := range comparisons {
+		switch {
+		case !c.significant():
+			summary.Insignificant++
+		case c.DeltaPercent > 0:
+			summary.Regressions++
+			if summary.WorstRegression == nil || c.DeltaPercent > summary.WorstRegression.DeltaPercent {
+				summary.WorstRegression = &SummaryEntry{Benchmark: c.Benchmark, DeltaPercent: c.DeltaPercent}
+			}
+		default:
+			summary.Improvements++
+		}
+
+		overall.add(c)
+		cat := getBenchmarkCategory(c.Benchmark)
+		if categories[cat] == nil {
+			categories[cat] = &geomean{}
+		}
+		categories[cat].add(c)
+		counts[cat]++
+	}
+
+	summary.GeomeanRatio = overall.ratio()
+	summary.GeomeanDeltaPercent = (summary.GeomeanRatio - 1) * 100
+	for _, cat := range benchmarkCategories {
+		if g := categories[cat]; g != nil {
+			ratio := g.ratio()
+			summary.Categories = append(summary.Categories, CategorySummary{
+				Category:            cat,
+				Benchmarks:          counts[cat],
+				GeomeanRatio:        ratio,
+				GeomeanDeltaPercent: (ratio - 1) * 100,
+			})
+		}
+	}
+
+	switch {
+	case summary.Regressions > 0:
+		summary.Verdict = "regressed"
+	case summary.Improvements > 0:
+		summary.Verdict = "improved"
+	default:
+		summary.Verdict = "unchanged"
+	}
+	return summary
+}
+
+// writeSummary writes summary as JSON to path, creating parent directories
+func writeSummary(path string, summary ComparisonSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create summary directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// barStyle controls how delta bars are drawn in the terminal table
+type barStyle struct {
+	ASCII bool // plain '+'/'-' instead of unicode blocks
+	Color bool // ANSI red/green for regressions/improvements
+}
+
+const (
+	barWidth   = 12
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorReset = "\033[0m"
+)
+
+// Partial blocks from 1/8 to 7/8 of a cell
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// categoryScales returns the largest absolute delta per benchmark category so
+// bars are comparable within a category without one outlier flattening the rest.
+func categoryScales(comparisons []Comparison) map[string]float64 {
+	scales := make(map[string]float64)
+	for _, c := range comparisons {
+		cat := getBenchmarkCategory(c.Benchmark)
+		if d := math.Abs(c.DeltaPercent); d > scales[cat] {
+			scales[cat] = d
+		}
+	}
+	return scales
+}
+
+// renderDeltaBar draws |delta| relative to scale as a fixed-width bar.
+// Non-finite values draw a full bar, or none when the ratio is undefined.
+func renderDeltaBar(delta, scale float64, style barStyle) string {
+	eighths := 0
+	if ratio := math.Abs(delta) / scale; scale > 0 && !math.IsNaN(ratio) {
+		eighths = int(math.Round(min(ratio, 1) * barWidth * 8))
+	}
+
+	var bar string
+	cells := eighths / 8
+	if style.ASCII {
+		ch := "+"
+		if delta < 0 {
+			ch = "-"
+		}
+		cells = int(math.Round(float64(eighths) / 8))
+		bar = strings.Repeat(ch, cells)
+	} else {
+		bar = strings.Repeat("█", cells) + barEighths[eighths%8]
+		if eighths%8 != 0 {
+			cells++
+		}
+	}
+	bar += strings.Repeat(" ", barWidth-cells)
+
+	if style.Color {
+		if delta > noiseThresholdPercent {
+			bar = colorRed + bar + colorReset
+		} else if delta < -noiseThresholdPercent {
+			bar = colorGreen + bar + colorReset
+		}
+	}
+	return bar
+}
+
+// Units of the Baseline and Target columns for -unit
+const (
+	displayNsPerOp   = "ns"
+	displayOpsPerSec = "ops"
+)
+
+// displayedValues returns c's baseline, target and change as shown in the
+// display unit: ns/op as compared, or operations per second (1e9 / ns/op),
+// whose change is positive when the target is faster. The change is measured
+// by c's policy and rendered with it; the unit is shared by both values.
+func displayedValues(c Comparison, display string) (base, target float64, change string, unit units.Unit) {
+	policy := policyNamed(c.DeltaPolicy)
+	if display == displayOpsPerSec && c.BaselineNs > 0 && c.TargetNs > 0 {
+		base, target = 1e9/c.BaselineNs, 1e9/c.TargetNs
+		unit = units.Rate(math.Min(base, target))
+		return base, target, policy.format(policy.delta(base, target), unit), unit
+	}
+	unit = units.Time(math.Min(c.BaselineNs, c.TargetNs))
+	return c.BaselineNs, c.TargetNs, policy.format(c.change(), unit), unit
+}
+
+// printComparisons prints comparisons as a table in their order, with
+// Baseline and Target in the display unit. With top above zero only the top
+// largest movers are shown; bar scales and geomeans still cover every
+// comparison.
+func printComparisons(comparisons []Comparison, baseMetadata, targetMetadata Metadata, style barStyle, top int, display string) {
+	fmt.Printf("\n=== Benchmark Comparison ===\n\n")
+	fmt.Printf("Baseline: %s (%s)\n", baseMetadata.GoVersion, baseMetadata.GoVersionFull)
+	fmt.Printf("Target:   %s (%s)\n\n", targetMetadata.GoVersion, targetMetadata.GoVersionFull)
+
+	// Rate symbols are wider than time ones; the value gives up the room
+	symbolWidth := 2
+	if display == displayOpsPerSec {
+		symbolWidth = 6
+		fmt.Printf("Baseline and Target in operations per second; a positive change is faster.\n\n")
+	}
+
+	// Percent changes fit 10 runes; other policies widen the column to
+	// their longest change
+	shown := largestMovers(comparisons, top)
+	changeWidth := 10
+	for _, c := range shown {
+		_, _, change, _ := displayedValues(c, display)
+		changeWidth = max(changeWidth, utf8.RuneCountInString(change))
+	}
+
+	fmt.Printf("%-30s %15s %s %15s %s %*s %9s %16s %8s %16s %-*s\n", "Benchmark", "Baseline", "  ±95% ", "Target", "  ±95% ",
+		changeWidth+2, "Change", "p", "B/op", "allocs", "MB/s", barWidth, "")
+	fmt.Printf("%s\n", strings.Repeat("-", 85+4*8+3*9+changeWidth-10+barWidth+1))
+
+	scales := categoryScales(comparisons)
+	for _, c := range shown {
+		direction := "→"
+		if c.significant() && c.DeltaPercent > 0 {
+			direction = "↑ slower"
+		} else if c.significant() {
+			direction = "↓ faster"
+		} else if c.withinNoise() {
+			direction = fmt.Sprintf("≈ noise ±%.0f%%", c.NoiseBandPercent)
+		}
+		pValue := fmt.Sprintf("n=%d+%d", c.BaselineSamples, c.TargetSamples)
+		if c.tested() {
+			pValue = fmt.Sprintf("p=%.3f", c.PValue)
+		}
+
+		// Baseline and target share a unit so the row reads at a glance
+		base, target, change, unit := displayedValues(c, display)
+		// Changes that fail the significance test stay uncolored
+		rowStyle := style
+		rowStyle.Color = style.Color && c.significant()
+		bar := renderDeltaBar(c.DeltaPercent, scales[getBenchmarkCategory(c.Benchmark)], rowStyle)
+		fmt.Printf("%-30s %*.2f %s %s %*.2f %s %s %s %9s %s %s %s %s %s\n",
+			c.Benchmark, 14-symbolWidth, unit.Convert(base), unit.Pad(symbolWidth), intervalCell(c.BaselineCI),
+			14-symbolWidth, unit.Convert(target), unit.Pad(symbolWidth), intervalCell(c.TargetCI),
+			colorize(alignRight(change, changeWidth), c.DeltaPercent, c.significant(), style), pValue,
+			metricColumn(c.Metrics["B/op"], units.Bytes, style), metricColumn(c.Metrics["allocs/op"], nil, style),
+			metricColumn(c.Metrics["MB/s"], units.Throughput, style), bar,
+			colorize(direction, c.DeltaPercent, c.significant(), style))
+		for _, unit := range customMetricUnits(c) {
+			m := c.Metrics[unit]
+			change := "~"
+			if m.Significant && m.regressionPercent() > 0 {
+				change = "worse"
+			} else if m.Significant {
+				change = "better"
+			}
+			fmt.Printf("  %-28s %15.2f %7s %15.2f %7s %+9.1f%% %s\n", unit, m.Baseline, "", m.Target, "", m.DeltaPercent,
+				colorize(fmt.Sprintf("%9s", change), m.regressionPercent(), m.Significant, style))
+		}
+	}
+	if hidden := len(comparisons) - len(shown); hidden > 0 {
+		fmt.Printf("... %d smaller changes hidden by -top %d\n", hidden, top)
+	}
+
+	printGeomeans(summarizeComparisons(comparisons))
+}
+
+// alignRight pads s on the left to width runes; fmt's width verbs count
+// bytes, which misaligns multi-byte unit symbols like µs
+func alignRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// intervalCell renders a confidence interval for the terminal table as its
+// half width relative to the mean, 7 runes wide and blank without one
+func intervalCell(i *Interval) string {
+	if i == nil {
+		return strings.Repeat(" ", 7)
+	}
+	if w := i.relativeHalfWidth(); w < 999.95 {
+		return fmt.Sprintf("±%5.1f%%", w)
+	}
+	return " ±>999%"
+}
+
+// printDrift lists the benchmarks left out of the comparison because only
+// one side has them
+func printDrift(d BenchmarkDrift) {
+	for _, section := range []struct {
+		title string
+		names []string
+	}{
+		{"New benchmarks (target only)", d.Added},
+		{"Removed benchmarks (baseline only)", d.Removed},
+	} {
+		if len(section.names) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s: %d\n", section.title, len(section.names))
+		for _, name := range section.names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+}
+
+// printGeomeans prints the geometric mean change of ns/op per category and
+// overall, like benchstat's geomean row
+func printGeomeans(summary ComparisonSummary) {
+	if summary.Benchmarks == 0 {
+		return
+	}
+	fmt.Printf("\nGeomean (target/baseline ns/op):\n")
+	for _, c := range summary.Categories {
+		fmt.Printf("  %-14s %4d benchmarks %+8.1f%%\n", c.Category, c.Benchmarks, c.GeomeanDeltaPercent)
+	}
+	fmt.Printf("  %-14s %4d benchmarks %+8.1f%%\n", "overall", summary.Benchmarks, summary.GeomeanDeltaPercent)
+}
+
+// colorize wraps s in red when regression is positive and green when it is
+// negative. Insignificant changes and styles without color leave s as is.
+func colorize(s string, regression float64, significant bool, style barStyle) string {
+	switch {
+	case !style.Color || !significant:
+		return s
+	case regression > 0:
+		return colorRed + s + colorReset
+	case regression < 0:
+		return colorGreen + s + colorReset
+	}
+	return s
+}
+
+// metricColumn renders metricCell, or scaledMetricCell when pick is set,
+// padded to its terminal column and colored by the direction of the change
+func metricColumn(m *MetricDelta, pick func(float64) units.Unit, style barStyle) string {
+	cell := fmt.Sprintf("%8s", metricCell(m))
+	if pick != nil {
+		cell = fmt.Sprintf("%16s", scaledMetricCell(m, pick))
+	}
+	if m == nil {
+		return cell
+	}
+	return colorize(cell, m.regressionPercent(), m.Significant, style)
+}
+
+// scaledMetricCell renders the target value of m in the unit pick chooses
+// for the smaller of its values, followed by metricCell, e.g. "4.10 KB +3%";
+// empty when not reported
+func scaledMetricCell(m *MetricDelta, pick func(float64) units.Unit) string {
+	if m == nil {
+		return ""
+	}
+	return pick(math.Min(m.Baseline, m.Target)).Format(m.Target) + " " + metricCell(m)
+}
+
+// metricCell renders the change of one of comparedMetrics for a table: the
+// delta when significant, "~" when not, empty when not reported
+func metricCell(m *MetricDelta) string {
+	switch {
+	case m == nil:
+		return ""
+	case !m.Significant:
+		return "~"
+	}
+	return fmt.Sprintf("%+.0f%%", m.DeltaPercent)
+}
+
+// customMetricUnits returns the units of c.Metrics other than
+// comparedMetrics, sorted
+func customMetricUnits(c Comparison) []string {
+	var custom []string
+	for unit := range c.Metrics {
+		if !isComparedMetric(unit) {
+			custom = append(custom, unit)
+		}
+	}
+	slices.Sort(custom)
+	return custom
+}
+
+// colorSupported reports whether stdout is a terminal and NO_COLOR is unset.
+func colorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// resultCPU returns the CPU model for a result, preferring runner metadata
+// and falling back to the "cpu:" header printed by go test.
+func resultCPU(result BenchmarkResult) string {
+	if result.Metadata.Runner.CPU != "" {
+		return result.Metadata.Runner.CPU
+	}
+	for _, line := range result.Benchmarks {
+		if strings.HasPrefix(line, "cpu:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "cpu:"))
+		}
+	}
+	return ""
+}
+
+// machineMismatches lists the ways baseline and target were collected on
+// different machines. Fields unknown on either side are not compared.
+func machineMismatches(baseline, target BenchmarkResult) []string {
+	var mismatches []string
+	check := func(field, base, tgt string) {
+		if base != "" && tgt != "" && base != tgt {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %s vs %s", field, base, tgt))
+		}
+	}
+
+	base, tgt := baseline.Metadata.Runner, target.Metadata.Runner
+	check("OS", base.OS, tgt.OS)
+	check("arch", base.Arch, tgt.Arch)
+	check("CPU", resultCPU(baseline), resultCPU(target))
+	if base.Cores != 0 && tgt.Cores != 0 && base.Cores != tgt.Cores {
+		mismatches = append(mismatches, fmt.Sprintf("cores: %d vs %d", base.Cores, tgt.Cores))
+	}
+	return mismatches
+}
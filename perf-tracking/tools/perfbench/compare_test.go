@@ -0,0 +1,664 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+func TestMachineMismatches(t *testing.T) {
+	result := func(os, arch, cpu string, cores int, lines ...string) BenchmarkResult {
+		var r BenchmarkResult
+		r.Metadata.Runner.OS = os
+		r.Metadata.Runner.Arch = arch
+		r.Metadata.Runner.CPU = cpu
+		r.Metadata.Runner.Cores = cores
+		r.Benchmarks = lines
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		baseline BenchmarkResult
+		target   BenchmarkResult
+		want     int
+	}{
+		{
+			name:     "same machine",
+			baseline: result("linux", "amd64", "Intel Xeon", 4),
+			target:   result("linux", "amd64", "Intel Xeon", 4),
+			want:     0,
+		},
+		{
+			name:     "different OS and cores",
+			baseline: result("linux", "amd64", "", 4),
+			target:   result("darwin", "amd64", "", 8),
+			want:     2,
+		},
+		{
+			name:     "CPU from go test header",
+			baseline: result("linux", "amd64", "", 4, "cpu: Intel Xeon"),
+			target:   result("linux", "amd64", "", 4, "cpu: AMD EPYC"),
+			want:     1,
+		},
+		{
+			name:     "unknown fields are not compared",
+			baseline: result("linux", "", "Intel Xeon", 0),
+			target:   result("linux", "arm64", "", 16),
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := machineMismatches(tt.baseline, tt.target)
+			if len(got) != tt.want {
+				t.Errorf("machineMismatches() = %v, want %d mismatches", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderDeltaBar(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta float64
+		scale float64
+		style barStyle
+		want  string
+	}{
+		{"full unicode", 20, 20, barStyle{}, "████████████"},
+		{"half unicode", -10, 20, barStyle{}, "██████      "},
+		{"partial cell", 1, 96, barStyle{}, "▏           "},
+		{"zero scale", 0, 0, barStyle{}, "            "},
+		{"ascii slower", 10, 20, barStyle{ASCII: true}, "++++++      "},
+		{"ascii faster", -20, 20, barStyle{ASCII: true}, "------------"},
+		{"color regression", 20, 20, barStyle{ASCII: true, Color: true}, colorRed + "++++++++++++" + colorReset},
+		{"no color within noise", 0.5, 20, barStyle{ASCII: true, Color: true}, "            "},
+		{"infinite delta", math.Inf(1), 20, barStyle{}, "████████████"},
+		{"infinite scale", math.Inf(1), math.Inf(1), barStyle{}, "            "},
+		{"nan delta", math.NaN(), 20, barStyle{}, "            "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderDeltaBar(tt.delta, tt.scale, tt.style); got != tt.want {
+				t.Errorf("renderDeltaBar(%v, %v) = %q, want %q", tt.delta, tt.scale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareResultsZeroTarget(t *testing.T) {
+	base := extractBenchmarks([]string{"BenchmarkA-8 1000000 4 ns/op", "BenchmarkA-8 1000000 4 ns/op"})
+	target := extractBenchmarks([]string{"BenchmarkA-8 1000000000 0 ns/op", "BenchmarkA-8 1000000000 0 ns/op"})
+	for _, policy := range []comparisonPolicy{ratioPolicy{}, differencePolicy{}, logPolicy{}} {
+		comparisons := compareResults(base, target, policy)
+		if len(comparisons) != 1 {
+			t.Fatalf("%s: %d comparisons, want 1", policy.name(), len(comparisons))
+		}
+		c := comparisons[0]
+		if math.IsInf(c.Delta, 0) || math.IsNaN(c.Delta) || c.Delta >= 0 {
+			t.Errorf("%s delta = %v, want finite and negative", policy.name(), c.Delta)
+		}
+		if c.DeltaPercent != -100 {
+			t.Errorf("%s: delta %v%%, want -100%%", policy.name(), c.DeltaPercent)
+		}
+		// -output and the ledger encode comparisons as JSON
+		if _, err := json.Marshal(comparisons); err != nil {
+			t.Errorf("%s: %v", policy.name(), err)
+		}
+	}
+	if c := compareResults(base, target, logPolicy{})[0]; math.Abs(c.Delta-math.Log2(1.0/5)) > 1e-9 {
+		t.Errorf("log delta = %v, want log2(1/5)", c.Delta)
+	}
+}
+
+func TestCompareResultsZeroBaseline(t *testing.T) {
+	base := extractBenchmarks([]string{
+		"BenchmarkA-8 1000000000 0 ns/op",
+		"BenchmarkB-8 1000000000 0 ns/op",
+	})
+	target := extractBenchmarks([]string{
+		"BenchmarkA-8 1000000000 2 ns/op",
+		"BenchmarkB-8 1000000000 0 ns/op",
+	})
+	for _, policy := range []comparisonPolicy{ratioPolicy{}, differencePolicy{}, logPolicy{}} {
+		for _, c := range compareResults(base, target, policy) {
+			if math.IsInf(c.DeltaPercent, 0) || math.IsNaN(c.DeltaPercent) || math.IsInf(c.Delta, 0) || math.IsNaN(c.Delta) {
+				t.Errorf("%s %s: delta %v%%, %s delta %v, want finite", c.Benchmark, policy.name(), c.DeltaPercent, policy.name(), c.Delta)
+			}
+			want := 0.0
+			if c.Benchmark == "BenchmarkA" {
+				want = 200
+			}
+			if c.DeltaPercent != want {
+				t.Errorf("%s: delta %v%%, want %v%%", c.Benchmark, c.DeltaPercent, want)
+			}
+		}
+	}
+
+	// Its bar renders rather than panicking on a negative Repeat count
+	comparisons := compareResults(base, target, ratioPolicy{})
+	scales := categoryScales(comparisons)
+	for _, c := range comparisons {
+		if bar := renderDeltaBar(c.DeltaPercent, scales[getBenchmarkCategory(c.Benchmark)], barStyle{}); utf8.RuneCountInString(bar) != barWidth {
+			t.Errorf("%s bar = %q", c.Benchmark, bar)
+		}
+	}
+}
+
+func TestCategoryScales(t *testing.T) {
+	comparisons := []Comparison{
+		{Benchmark: "BenchmarkGCThroughput", DeltaPercent: 5},
+		{Benchmark: "BenchmarkMapCreation", DeltaPercent: -30},
+		{Benchmark: "BenchmarkJSONEncode", DeltaPercent: 2},
+	}
+	scales := categoryScales(comparisons)
+	if scales["runtime"] != 30 {
+		t.Errorf("runtime scale = %v, want 30", scales["runtime"])
+	}
+	if scales["stdlib"] != 2 {
+		t.Errorf("stdlib scale = %v, want 2", scales["stdlib"])
+	}
+}
+
+func TestSummarizeComparisons(t *testing.T) {
+	comparisons := []Comparison{
+		{Benchmark: "BenchmarkA", BaselineNs: 100, TargetNs: 200, DeltaPercent: 100},
+		{Benchmark: "BenchmarkB", BaselineNs: 100, TargetNs: 110, DeltaPercent: 10},
+		{Benchmark: "BenchmarkC", BaselineNs: 200, TargetNs: 50, DeltaPercent: -75},
+		{Benchmark: "BenchmarkD", BaselineNs: 100, TargetNs: 100.5, DeltaPercent: 0.5},
+	}
+
+	s := summarizeComparisons(comparisons)
+	if s.Verdict != "regressed" || s.Regressions != 2 || s.Improvements != 1 || s.Insignificant != 1 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.WorstRegression == nil || s.WorstRegression.Benchmark != "BenchmarkA" {
+		t.Errorf("worst regression = %+v, want BenchmarkA", s.WorstRegression)
+	}
+	// geomean(2, 1.1, 0.25, 1.005) ≈ 0.8622
+	if math.Abs(s.GeomeanRatio-0.8622) > 0.001 {
+		t.Errorf("geomean ratio = %.4f, want ≈0.8622", s.GeomeanRatio)
+	}
+
+	if got := summarizeComparisons(nil); got.Verdict != "unchanged" || got.GeomeanRatio != 1 {
+		t.Errorf("empty summary = %+v", got)
+	}
+	improved := summarizeComparisons(comparisons[2:3])
+	if improved.Verdict != "improved" || improved.WorstRegression != nil {
+		t.Errorf("improved summary = %+v", improved)
+	}
+}
+
+func TestCompareResultsSignificance(t *testing.T) {
+	lines := func(name string, ns ...float64) []string {
+		var out []string
+		for _, v := range ns {
+			out = append(out, fmt.Sprintf("%s-16 \t 1000\t %.0f ns/op\t 0 B/op\t 0 allocs/op", name, v))
+		}
+		return out
+	}
+	var base, target []string
+	// Consistently 10% slower
+	base = append(base, lines("BenchmarkSlower", 100, 101, 99, 100, 102, 98)...)
+	target = append(target, lines("BenchmarkSlower", 110, 111, 109, 110, 112, 108)...)
+	// Median 5% faster, but the samples overlap
+	base = append(base, lines("BenchmarkNoisy", 100, 80, 120, 90, 110, 100)...)
+	target = append(target, lines("BenchmarkNoisy", 95, 125, 75, 95, 105, 85)...)
+	// Too few samples to test: falls back to the noise threshold
+	base = append(base, lines("BenchmarkSingle", 100)...)
+	target = append(target, lines("BenchmarkSingle", 105)...)
+
+	baseStats, targetStats := extractBenchmarks(base), extractBenchmarks(target)
+	if got := baseStats["BenchmarkSlower"].Samples; len(got) != 6 || got[0] != 100 || got[5] != 98 {
+		t.Errorf("samples = %v, want all six in file order", got)
+	}
+
+	results := make(map[string]Comparison)
+	for _, c := range compareResults(baseStats, targetStats, ratioPolicy{}) {
+		results[c.Benchmark] = c
+	}
+
+	slower := results["BenchmarkSlower"]
+	if slower.BaselineNs != 100 || slower.TargetNs != 110 || math.Abs(slower.DeltaPercent-10) > 1e-9 {
+		t.Errorf("slower: medians %v -> %v (%+.1f%%), want 100 -> 110", slower.BaselineNs, slower.TargetNs, slower.DeltaPercent)
+	}
+	if !slower.Significant || slower.PValue <= 0 || slower.PValue >= significanceLevel {
+		t.Errorf("slower: p = %v, significant = %v", slower.PValue, slower.Significant)
+	}
+
+	noisy := results["BenchmarkNoisy"]
+	if noisy.Significant || noisy.PValue < significanceLevel || math.Abs(noisy.DeltaPercent) <= noiseThresholdPercent {
+		t.Errorf("noisy: delta %+.1f%%, p = %v, significant = %v", noisy.DeltaPercent, noisy.PValue, noisy.Significant)
+	}
+
+	single := results["BenchmarkSingle"]
+	if single.PValue != 0 || single.BaselineSamples != 1 || !single.Significant {
+		t.Errorf("single: %+v, want untested and significant by threshold", single)
+	}
+
+	s := summarizeComparisons([]Comparison{slower, noisy, single})
+	if s.Regressions != 2 || s.Insignificant != 1 {
+		t.Errorf("summary = %+v, want the noisy change counted as insignificant", s)
+	}
+}
+
+func TestCompareResultsIntervals(t *testing.T) {
+	lines := func(name string, ns ...int) []string {
+		var out []string
+		for _, v := range ns {
+			out = append(out, fmt.Sprintf("%s-8 \t 1000\t %d ns/op", name, v))
+		}
+		return out
+	}
+	// Ranks say 4% slower, but one slow baseline sample widens its interval
+	// over the target's
+	base := lines("BenchmarkOutlier", 100, 101, 102, 100, 101, 99, 100, 250)
+	target := lines("BenchmarkOutlier", 104, 105, 104, 106, 105, 104, 103, 105)
+	// Clearly separated
+	base = append(base, lines("BenchmarkSlower", 100, 101, 99, 100, 102, 98)...)
+	target = append(target, lines("BenchmarkSlower", 110, 111, 109, 110, 112, 108)...)
+
+	results := make(map[string]Comparison)
+	for _, c := range compareResults(extractBenchmarks(base), extractBenchmarks(target), ratioPolicy{}) {
+		results[c.Benchmark] = c
+	}
+
+	outlier := results["BenchmarkOutlier"]
+	if outlier.PValue >= significanceLevel || outlier.BaselineCI == nil || outlier.TargetCI == nil {
+		t.Fatalf("outlier: p = %v, intervals %v %v", outlier.PValue, outlier.BaselineCI, outlier.TargetCI)
+	}
+	if outlier.TargetCI.Low > outlier.BaselineCI.High || outlier.Significant || outlier.withinNoise() {
+		t.Errorf("outlier: intervals %+v %+v, significant = %v, want overlapping and not significant",
+			*outlier.BaselineCI, *outlier.TargetCI, outlier.Significant)
+	}
+
+	slower := results["BenchmarkSlower"]
+	if !slower.Significant || slower.TargetCI.Low <= slower.BaselineCI.High {
+		t.Errorf("slower: intervals %+v %+v, significant = %v", *slower.BaselineCI, *slower.TargetCI, slower.Significant)
+	}
+	if mean := (slower.BaselineCI.Low + slower.BaselineCI.High) / 2; math.Abs(mean-100) > 1e-9 {
+		t.Errorf("baseline interval %+v not centered on the mean 100", *slower.BaselineCI)
+	}
+
+	// Separate intervals in the wrong order contradict the change
+	slower.TargetCI = &Interval{Low: 80, High: 90}
+	if slower.significant() {
+		t.Error("a slower median with a faster interval should not be significant")
+	}
+	// One sample: no interval, the test and threshold decide
+	slower.TargetCI = nil
+	if !slower.significant() {
+		t.Error("a missing interval should not block the verdict")
+	}
+}
+
+func TestIntervalCell(t *testing.T) {
+	if got := intervalCell(&Interval{Low: 98, High: 102}); got != "±  2.0%" {
+		t.Errorf("intervalCell = %q", got)
+	}
+	if got := intervalCell(&Interval{Low: -50, High: 51}); got != " ±>999%" {
+		t.Errorf("intervalCell of a huge interval = %q", got)
+	}
+	if got := intervalCell(nil); got != "       " {
+		t.Errorf("intervalCell(nil) = %q", got)
+	}
+}
+
+func TestLoadBenchmarkResult(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	raw := write("old.txt", "goos: linux\ngoarch: amd64\ncpu: Intel(R) Xeon(R)\n"+
+		"BenchmarkMapIteration-8 \t 1000\t 100 ns/op\nBenchmarkMapIteration-8 \t 1000\t 102 ns/op\nPASS\n")
+	result, err := loadBenchmarkResult(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Metadata.GoVersion != "old.txt" || result.Metadata.Runner.OS != "linux" || result.Metadata.Runner.Arch != "amd64" {
+		t.Errorf("metadata = %+v", result.Metadata)
+	}
+	if got := resultCPU(result); got != "Intel(R) Xeon(R)" {
+		t.Errorf("cpu = %q", got)
+	}
+	if stats := extractBenchmarks(result.Benchmarks)["BenchmarkMapIteration"]; stats == nil || len(stats.Samples) != 2 {
+		t.Errorf("stats = %+v, want two samples", stats)
+	}
+
+	// The runner's sidecar fills in what go test does not print
+	write("old_metadata.json", `{"system": {"cpu": "Xeon 8375C", "os": "linux", "arch": "amd64", "logical_cores": 16}}`)
+	if result, err = loadBenchmarkResult(raw); err != nil {
+		t.Fatal(err)
+	}
+	if r := result.Metadata.Runner; r.CPU != "Xeon 8375C" || r.Cores != 16 {
+		t.Errorf("runner = %+v, want sidecar values", r)
+	}
+
+	result, err = loadBenchmarkResult(write("new.json", `{"metadata": {"go_version": "1.25"}, "benchmarks": ["BenchmarkMapIteration-8 1000 90 ns/op"]}`))
+	if err != nil || result.Metadata.GoVersion != "1.25" || len(result.Benchmarks) != 1 {
+		t.Errorf("json = %+v, %v", result, err)
+	}
+
+	for name, content := range map[string]string{
+		"broken.json": `{"metadata": `,
+		"empty.txt":   "PASS\nok  \tpkg\t0.1s\n",
+	} {
+		if _, err := loadBenchmarkResult(write(name, content)); err == nil {
+			t.Errorf("%s: loaded, want error", name)
+		}
+	}
+}
+
+func TestLoadPooledResults(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	run := func(name, cpu string, ns ...string) string {
+		content := "goos: linux\ngoarch: amd64\ncpu: " + cpu + "\n"
+		for _, v := range ns {
+			content += "BenchmarkMapIteration-8 \t 1000\t " + v + " ns/op\n"
+		}
+		return write(name, content+"PASS\n")
+	}
+	a := run("run1.txt", "Xeon", "100", "101")
+	b := run("run2.txt", "Xeon", "110")
+	c := write("run3.json", `{"metadata": {"go_version": "1.25", "timestamp": "2026-01-26T12:00:00Z"},
+		"benchmarks": ["BenchmarkMapIteration-8 1000 105 ns/op", "BenchmarkGCLatency-8 1000 900 ns/op"]}`)
+
+	pooled, err := loadPooledResults(a + "," + b + ", " + c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := extractBenchmarks(pooled.Benchmarks)
+	if got := stats["BenchmarkMapIteration"].Samples; !slices.Equal(got, []float64{100, 101, 110, 105}) {
+		t.Errorf("pooled samples = %v", got)
+	}
+	if stats["BenchmarkGCLatency"] == nil {
+		t.Error("benchmark of one run only is missing")
+	}
+	if m := pooled.Metadata; m.GoVersion != "1.25" || m.Timestamp != "2026-01-26T12:00:00Z" || m.Runner.OS != "linux" {
+		t.Errorf("pooled metadata = %+v", m)
+	}
+
+	// A single path is loaded unchanged
+	if single, err := loadPooledResults(a); err != nil || single.Metadata.GoVersion != "run1.txt" {
+		t.Errorf("single = %+v, %v", single.Metadata, err)
+	}
+
+	// The sidecar of a --contention-profile run marks it profiled
+	profiled := run("profiled.txt", "Xeon", "140")
+	write("profiled_metadata.json", `{"contention_profile": true}`)
+	if r, err := loadBenchmarkResult(profiled); err != nil || !r.Metadata.ContentionProfile {
+		t.Errorf("profiled run: %+v, %v", r.Metadata, err)
+	}
+
+	other := run("other.txt", "EPYC", "95")
+	go124 := write("go124.json", `{"metadata": {"go_version": "1.24"}, "benchmarks": ["BenchmarkMapIteration-8 1000 99 ns/op"]}`)
+	for _, paths := range []string{a + "," + other, c + "," + go124, a + "," + filepath.Join(dir, "missing.txt"), a + "," + profiled} {
+		if _, err := loadPooledResults(paths); err == nil {
+			t.Errorf("loadPooledResults(%s) succeeded, want error", paths)
+		}
+	}
+}
+
+func TestLatestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("BenchmarkFoo-4 1000 100 ns/op\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Date(2026, 1, 26, 12, 0, 0, 0, time.UTC).Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("go1.9/run.txt", 0)
+	write("go1.24/run.txt", 0)
+	write("go1.25/old.txt", time.Hour)
+	latest := write("go1.25/new.txt", 0)
+	write("go1.25/new_retry.txt", -time.Hour)
+	target := write("go1.26rc1/run.txt", 0)
+	if err := os.MkdirAll(filepath.Join(dir, "go1.27"), 0755); err != nil { // no results yet
+		t.Fatal(err)
+	}
+
+	// 1.26rc1 sorts above 1.25 but holds the target; go1.27 has no results
+	file, version, err := latestBaseline(dir, target)
+	if err != nil || file != latest || version != "1.25" {
+		t.Errorf("latestBaseline = %s, %s, %v; want %s, 1.25", file, version, err, latest)
+	}
+
+	// A target outside the results directory leaves every version eligible
+	if file, version, err = latestBaseline(dir, filepath.Join(t.TempDir(), "pr.txt")); err != nil || version != "1.26rc1" {
+		t.Errorf("latestBaseline = %s, %s, %v; want 1.26rc1", file, version, err)
+	}
+
+	if _, _, err := latestBaseline(t.TempDir(), target); err == nil {
+		t.Error("empty results directory: want error")
+	}
+}
+
+func TestCompareResultsMetrics(t *testing.T) {
+	base := extractBenchmarks([]string{
+		"BenchmarkAlloc-8 1000 100 ns/op 64 B/op 2 allocs/op",
+		"BenchmarkAlloc-8 1000 101 ns/op 64 B/op 2 allocs/op",
+		"BenchmarkAlloc-8 1000 99 ns/op 64 B/op 2 allocs/op",
+		"BenchmarkAlloc-8 1000 100 ns/op 64 B/op 2 allocs/op",
+		"BenchmarkZero-8 1000 10 ns/op 0 B/op 0 allocs/op",
+		"BenchmarkAESCTR-8 1000 100 ns/op 10.00 MB/s 0 B/op 0 allocs/op",
+		"BenchmarkNoMem-8 1000 50 ns/op",
+	})
+	target := extractBenchmarks([]string{
+		"BenchmarkAlloc-8 1000 100 ns/op 32 B/op 1 allocs/op",
+		"BenchmarkAlloc-8 1000 101 ns/op 32 B/op 1 allocs/op",
+		"BenchmarkAlloc-8 1000 99 ns/op 32 B/op 1 allocs/op",
+		"BenchmarkAlloc-8 1000 100 ns/op 32 B/op 1 allocs/op",
+		"BenchmarkZero-8 1000 10 ns/op 16 B/op 2 allocs/op",
+		"BenchmarkAESCTR-8 1000 110 ns/op 9.00 MB/s 0 B/op 0 allocs/op",
+		"BenchmarkNoMem-8 1000 50 ns/op 48 B/op 3 allocs/op",
+	})
+	results := make(map[string]Comparison)
+	for _, c := range compareResults(base, target, ratioPolicy{}) {
+		results[c.Benchmark] = c
+	}
+
+	// Same speed, half the allocations: only the allocation metrics changed
+	alloc := results["BenchmarkAlloc"]
+	if alloc.significant() {
+		t.Errorf("alloc: ns/op change %+.1f%% reported as significant", alloc.DeltaPercent)
+	}
+	for unit, want := range map[string]float64{"B/op": -50, "allocs/op": -50} {
+		m := alloc.Metrics[unit]
+		if m == nil || m.DeltaPercent != want || !m.Significant || m.PValue == 0 {
+			t.Errorf("alloc %s = %+v, want significant %v%%", unit, m, want)
+		}
+	}
+	if _, ok := alloc.Metrics["MB/s"]; ok {
+		t.Error("alloc: MB/s compared although never reported")
+	}
+
+	// A change from zero is measured against 1
+	zero := results["BenchmarkZero"]
+	if m := zero.Metrics["allocs/op"]; m == nil || m.DeltaPercent != 200 || !m.Significant {
+		t.Errorf("zero allocs/op = %+v, want +200%%", m)
+	}
+
+	// Throughput drops are regressions
+	mbps := results["BenchmarkAESCTR"].Metrics["MB/s"]
+	if mbps == nil || math.Abs(mbps.DeltaPercent+10) > 1e-9 || !mbps.HigherIsBetter || mbps.regressionPercent() <= 0 {
+		t.Errorf("MB/s = %+v, want a 10%% drop counted as regression", mbps)
+	}
+	if got := metricCell(mbps); got != "-10%" {
+		t.Errorf("metricCell = %q, want -10%%", got)
+	}
+	if got := metricCell(results["BenchmarkAESCTR"].Metrics["B/op"]); got != "~" {
+		t.Errorf("unchanged metricCell = %q, want ~", got)
+	}
+
+	// Memory units are only compared when both sides ran with -benchmem
+	if m := results["BenchmarkNoMem"].Metrics; m["B/op"] != nil || m["allocs/op"] != nil {
+		t.Errorf("NoMem metrics = %v, want no B/op or allocs/op from a baseline without them", m)
+	}
+
+	// B/op and MB/s columns show the target value in a scaled unit
+	if got := scaledMetricCell(mbps, units.Throughput); got != "9.00 MB/s -10%" {
+		t.Errorf("scaledMetricCell = %q, want 9.00 MB/s -10%%", got)
+	}
+	kb := &MetricDelta{Baseline: 4096, Target: 4200, DeltaPercent: 2.5}
+	if got := scaledMetricCell(kb, units.Bytes); got != "4.20 KB ~" {
+		t.Errorf("scaledMetricCell = %q, want 4.20 KB ~", got)
+	}
+	gbps := &MetricDelta{Baseline: 2500, Target: 3000, DeltaPercent: 20, HigherIsBetter: true, Significant: true}
+	if got := scaledMetricCell(gbps, units.Throughput); got != "3.00 GB/s +20%" {
+		t.Errorf("scaledMetricCell = %q, want 3.00 GB/s +20%%", got)
+	}
+	if got := scaledMetricCell(nil, units.Bytes); got != "" {
+		t.Errorf("unreported scaledMetricCell = %q, want empty", got)
+	}
+}
+
+func TestCompareResultsCustomMetrics(t *testing.T) {
+	base := extractBenchmarks([]string{
+		"BenchmarkGCLatency-8 100 2000 ns/op 400 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkGCLatency-8 100 2010 ns/op 410 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkGCLatency-8 100 1990 ns/op 390 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkGCLatency-8 100 2000 ns/op 400 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkTLSResume-8 100 5000 ns/op 100.0 resumed-% 2000 handshakes/sec",
+		"BenchmarkTLSHandshake-8 100 9000 ns/op 12 p99-ns",
+	})
+	target := extractBenchmarks([]string{
+		"BenchmarkGCLatency-8 100 2000 ns/op 200 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkGCLatency-8 100 2010 ns/op 210 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkGCLatency-8 100 1990 ns/op 190 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkGCLatency-8 100 2000 ns/op 200 pause-ns/gc 3 gc-cycles/op",
+		"BenchmarkTLSResume-8 100 5000 ns/op 50.0 resumed-% 2400 handshakes/sec",
+		"BenchmarkTLSHandshake-8 100 9000 ns/op",
+	})
+	results := make(map[string]Comparison)
+	for _, c := range compareResults(base, target, ratioPolicy{}) {
+		results[c.Benchmark] = c
+	}
+
+	gc := results["BenchmarkGCLatency"]
+	if m := gc.Metrics["pause-ns/gc"]; m == nil || m.DeltaPercent != -50 || !m.Significant || m.PValue == 0 || m.HigherIsBetter {
+		t.Errorf("pause-ns/gc = %+v, want a significant -50%% improvement", m)
+	}
+	if m := gc.Metrics["gc-cycles/op"]; m == nil || m.Significant {
+		t.Errorf("gc-cycles/op = %+v, want an insignificant change", m)
+	}
+	if got, want := strings.Join(customMetricUnits(gc), ","), "gc-cycles/op,pause-ns/gc"; got != want {
+		t.Errorf("customMetricUnits = %s, want %s", got, want)
+	}
+
+	// Drops in resumption rate and throughput are regressions
+	tls := results["BenchmarkTLSResume"]
+	if m := tls.Metrics["resumed-%"]; m == nil || !m.HigherIsBetter || m.regressionPercent() != 50 {
+		t.Errorf("resumed-%% = %+v, want a 50%% drop counted as regression", m)
+	}
+	if m := tls.Metrics["handshakes/sec"]; m == nil || !m.HigherIsBetter || m.regressionPercent() >= 0 {
+		t.Errorf("handshakes/sec = %+v, want a rise counted as improvement", m)
+	}
+
+	// A metric only one side reported is not compared
+	if _, ok := results["BenchmarkTLSHandshake"].Metrics["p99-ns"]; ok {
+		t.Error("p99-ns compared although the target never reported it")
+	}
+}
+
+func TestSummarizeComparisonsCategories(t *testing.T) {
+	comparisons := []Comparison{
+		{Benchmark: "BenchmarkGCLatency", BaselineNs: 100, TargetNs: 200},
+		{Benchmark: "BenchmarkTinyAlloc", BaselineNs: 100, TargetNs: 50},
+		{Benchmark: "BenchmarkUnknownThing", BaselineNs: 100, TargetNs: 110},
+		{Benchmark: "BenchmarkUnknownOther", BaselineNs: 0, TargetNs: 110},
+	}
+	s := summarizeComparisons(comparisons)
+
+	var got []string
+	for _, c := range s.Categories {
+		got = append(got, fmt.Sprintf("%s:%d:%.2f", c.Category, c.Benchmarks, c.GeomeanRatio))
+	}
+	// geomean(2, 0.5) = 1 for runtime; the zero baseline is counted but
+	// left out of the mean
+	if want := "runtime:2:1.00,uncategorized:2:1.10"; strings.Join(got, ",") != want {
+		t.Errorf("categories = %v, want %s", got, want)
+	}
+	if len(summarizeComparisons(nil).Categories) != 0 {
+		t.Error("empty summary has categories")
+	}
+}
+
+func TestBenchmarkDrift(t *testing.T) {
+	base := extractBenchmarks([]string{
+		"BenchmarkKept-8 1000 100 ns/op",
+		"BenchmarkDropped-8 1000 100 ns/op",
+		"BenchmarkOld/Case-8 1000 100 ns/op",
+	})
+	target := extractBenchmarks([]string{
+		"BenchmarkKept-8 1000 100 ns/op",
+		"BenchmarkNew-8 1000 100 ns/op",
+		"BenchmarkAlsoNew-8 1000 100 ns/op",
+	})
+	d := benchmarkDrift(base, target)
+	if got, want := strings.Join(d.Added, ","), "BenchmarkAlsoNew,BenchmarkNew"; got != want {
+		t.Errorf("Added = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(d.Removed, ","), "BenchmarkDropped,BenchmarkOld/Case"; got != want {
+		t.Errorf("Removed = %s, want %s", got, want)
+	}
+
+	if d := benchmarkDrift(base, base); d.Added != nil || d.Removed != nil {
+		t.Errorf("drift of a suite against itself = %+v, want none", d)
+	}
+}
+
+func TestDisplayedValues(t *testing.T) {
+	c := Comparison{BaselineNs: 100, TargetNs: 80, DeltaPercent: -20}
+
+	base, target, change, unit := displayedValues(c, displayNsPerOp)
+	if base != 100 || target != 80 || change != "-20.0%" || unit != units.Nanosecond {
+		t.Errorf("ns: %v %v %s %s", base, target, change, unit.Symbol)
+	}
+
+	// 1e9/100 and 1e9/80 ops/s: 25% more operations per second
+	base, target, change, unit = displayedValues(c, displayOpsPerSec)
+	if base != 1e7 || target != 1.25e7 || change != "+25.0%" || unit != units.MegaOpPerSec {
+		t.Errorf("ops: %v %v %s %s", base, target, change, unit.Symbol)
+	}
+
+	// Other policies measure the displayed values their own way
+	c.Delta, c.DeltaPolicy = -20, policyDifference
+	if _, _, change, _ = displayedValues(c, displayNsPerOp); change != "-20.00 ns" {
+		t.Errorf("difference in ns = %q", change)
+	}
+	if _, _, change, _ = displayedValues(c, displayOpsPerSec); change != "+2.50 Mops/s" {
+		t.Errorf("difference in ops/s = %q", change)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// crossoverBenchmark is the size sweep crossovers are derived from: its
+// results are named BenchmarkLookup/<keys>/Size<n>/<strategy>
+const crossoverBenchmark = "BenchmarkLookup"
+
+// crossoverPairs lists the strategies compared, the one that wins on small
+// collections first
+var crossoverPairs = [][2]string{{"Linear", "Binary"}, {"Linear", "Map"}, {"Binary", "Map"}}
+
+// Crossover is the collection size from which one lookup strategy beats
+// another on this Go version and machine
+type Crossover struct {
+	Benchmark string `json:"benchmark"` // e.g. "BenchmarkLookup/Int"
+	Small     string `json:"small"`     // strategy faster below Size
+	Large     string `json:"large"`     // strategy faster from Size on
+	// Size is the smallest measured size from which Large stays faster at
+	// every larger size; when it is the smallest size of the sweep the
+	// crossover lies at or below it
+	Size int `json:"size"`
+}
+
+// parseLookupSize reads "Size8", "Size4K" or "Size1M"
+func parseLookupSize(s string) (int, bool) {
+	s, ok := strings.CutPrefix(s, "Size")
+	if !ok {
+		return 0, false
+	}
+	shift := 0
+	if rest, ok := strings.CutSuffix(s, "K"); ok {
+		s, shift = rest, 10
+	} else if rest, ok := strings.CutSuffix(s, "M"); ok {
+		s, shift = rest, 20
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n << shift, true
+}
+
+// findCrossovers derives the crossover sizes of every pair in
+// crossoverPairs from the crossoverBenchmark results. Pairs where the
+// large-collection strategy never stays ahead are left out.
+func findCrossovers(benchmarks map[string]Benchmark) []Crossover {
+	// group -> strategy -> size -> ns/op
+	sweeps := make(map[string]map[string]map[int]float64)
+	for name, bm := range benchmarks {
+		parts := strings.Split(name, "/")
+		if len(parts) != 4 || parts[0] != crossoverBenchmark || bm.NsPerOp <= 0 {
+			continue
+		}
+		size, ok := parseLookupSize(parts[2])
+		if !ok {
+			continue
+		}
+		group := parts[0] + "/" + parts[1]
+		if sweeps[group] == nil {
+			sweeps[group] = make(map[string]map[int]float64)
+		}
+		if sweeps[group][parts[3]] == nil {
+			sweeps[group][parts[3]] = make(map[int]float64)
+		}
+		sweeps[group][parts[3]][size] = bm.NsPerOp
+	}
+
+	groups := make([]string, 0, len(sweeps))
+	for group := range sweeps {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	var crossovers []Crossover
+	for _, group := range groups {
+		for _, pair := range crossoverPairs {
+			small, large := sweeps[group][pair[0]], sweeps[group][pair[1]]
+			var sizes []int
+			for size := range small {
+				if _, ok := large[size]; ok {
+					sizes = append(sizes, size)
+				}
+			}
+			if len(sizes) == 0 {
+				continue
+			}
+			sort.Ints(sizes)
+
+			// Walk down from the largest size while Large stays ahead
+			i := len(sizes)
+			for i > 0 && large[sizes[i-1]] < small[sizes[i-1]] {
+				i--
+			}
+			if i == len(sizes) {
+				continue
+			}
+			crossovers = append(crossovers, Crossover{Benchmark: group, Small: pair[0], Large: pair[1], Size: sizes[i]})
+		}
+	}
+	return crossovers
+}
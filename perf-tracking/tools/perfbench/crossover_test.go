@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLookupSize(t *testing.T) {
+	for in, want := range map[string]int{"Size8": 8, "Size4K": 4096, "Size1M": 1 << 20} {
+		if got, ok := parseLookupSize(in); !ok || got != want {
+			t.Errorf("parseLookupSize(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"8", "SizeK", "Size0", "Size4G"} {
+		if _, ok := parseLookupSize(in); ok {
+			t.Errorf("parseLookupSize(%q) succeeded", in)
+		}
+	}
+}
+
+func TestFindCrossovers(t *testing.T) {
+	benchmarks := make(map[string]Benchmark)
+	add := func(name string, ns float64) {
+		benchmarks[name] = Benchmark{Name: name, NsPerOp: ns}
+	}
+	// Int: binary search overtakes linear from 16 and the map overtakes
+	// linear from 16; the map is ahead of binary search at 8, but a dip at
+	// 16 puts that crossover at 32
+	for _, r := range []struct {
+		size                  string
+		linear, binary, mapNs float64
+	}{
+		{"Size8", 5, 10, 9},
+		{"Size16", 9, 12, 8},
+		{"Size32", 20, 14, 9},
+		{"Size64", 40, 16, 10},
+		{"Size1K", 0, 40, 12},
+	} {
+		add("BenchmarkLookup/Int/"+r.size+"/Linear", r.linear)
+		add("BenchmarkLookup/Int/"+r.size+"/Binary", r.binary)
+		add("BenchmarkLookup/Int/"+r.size+"/Map", r.mapNs)
+	}
+	benchmarks["BenchmarkLookup/Int/Size1K/Linear"] = Benchmark{} // not measured
+	add("BenchmarkLookup/Int/Size16/Binary", 7)
+	// String: the map is always faster than binary search, linear never
+	// loses to it in the sweep
+	add("BenchmarkLookup/String/Size8/Binary", 20)
+	add("BenchmarkLookup/String/Size8/Map", 15)
+	add("BenchmarkLookup/String/Size8/Linear", 10)
+	// Unrelated and malformed names are ignored
+	add("BenchmarkMapKey/Struct", 1)
+	add("BenchmarkLookup/Int/Huge/Map", 1)
+
+	want := []Crossover{
+		{Benchmark: "BenchmarkLookup/Int", Small: "Linear", Large: "Binary", Size: 16},
+		{Benchmark: "BenchmarkLookup/Int", Small: "Linear", Large: "Map", Size: 16},
+		{Benchmark: "BenchmarkLookup/Int", Small: "Binary", Large: "Map", Size: 32},
+		{Benchmark: "BenchmarkLookup/String", Small: "Binary", Large: "Map", Size: 8},
+	}
+	if got := findCrossovers(benchmarks); !reflect.DeepEqual(got, want) {
+		t.Errorf("findCrossovers =\n%+v\nwant\n%+v", got, want)
+	}
+}
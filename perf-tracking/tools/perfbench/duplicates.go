@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DuplicatePolicy decides how rebuildIndex resolves several JSON files that
+// claim the same version (e.g. go1.26.json and go1.26.0.json)
+type DuplicatePolicy string
+
+const (
+	DuplicateKeepNewest DuplicatePolicy = "keep-newest" // newest mtime wins, others are ignored
+	DuplicateMerge      DuplicatePolicy = "merge"       // pool samples into the newest file
+	DuplicateError      DuplicatePolicy = "error"       // refuse to build the index
+)
+
+// parseDuplicatePolicy validates an --on-duplicate value
+func parseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	switch p := DuplicatePolicy(s); p {
+	case DuplicateKeepNewest, DuplicateMerge, DuplicateError:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid --on-duplicate %q (want keep-newest, merge or error)", s)
+}
+
+// mergeVersionData pools the samples of several exports of the same version.
+// files must be ordered newest first; metadata, B/op, allocs/op and custom
+// metrics come from the newest file that has the benchmark. Means and
+// standard deviations are pooled exactly as if all samples had been parsed
+// from one file.
+func mergeVersionData(files []*VersionData) *VersionData {
+	merged := &VersionData{
+		Version:    files[0].Version,
+		Metadata:   files[0].Metadata,
+		Benchmarks: make(map[string]Benchmark),
+	}
+
+	type pool struct {
+		n            int
+		sum, sumSq   float64 // Σ n·mean and Σ n·(stddev² + mean²)
+		opsN         int     // samples of the files that have ops_per_sec
+		sumOps       float64 // Σ n·ops_per_sec over those files
+		newest       Benchmark
+		warnings     map[string]bool
+		warningOrder []string
+	}
+	pools := make(map[string]*pool)
+
+	for _, vd := range files {
+		for name, b := range vd.Benchmarks {
+			p, ok := pools[name]
+			if !ok {
+				p = &pool{newest: b, warnings: make(map[string]bool)}
+				pools[name] = p
+			}
+			n := max(b.Samples, 1)
+			p.n += n
+			p.sum += float64(n) * b.NsPerOp
+			p.sumSq += float64(n) * (b.NsPerOpStddev*b.NsPerOpStddev + b.NsPerOp*b.NsPerOp)
+			if b.OpsPerSec > 0 { // exports predating ops_per_sec lack it
+				p.opsN += n
+				p.sumOps += float64(n) * b.OpsPerSec
+			}
+			for _, w := range b.Warnings {
+				if !p.warnings[w] {
+					p.warnings[w] = true
+					p.warningOrder = append(p.warningOrder, w)
+				}
+			}
+		}
+	}
+
+	for name, p := range pools {
+		mean := p.sum / float64(p.n)
+		variance := math.Max(p.sumSq/float64(p.n)-mean*mean, 0)
+		stddev := math.Sqrt(variance)
+
+		b := p.newest
+		b.NsPerOp = mean
+		b.NsPerOpStddev = stddev
+		b.NsPerOpVariance = 0
+		if mean > 0 {
+			b.NsPerOpVariance = stddev / mean
+		}
+		b.OpsPerSec = 0
+		if p.opsN > 0 {
+			b.OpsPerSec = p.sumOps / float64(p.opsN)
+		}
+		// Percentiles and confidence intervals can't be pooled from
+		// summaries; re-export or run perfbench index on the raw results to
+		// recompute them
+		b.NsPerOpP50, b.NsPerOpP95 = 0, 0
+		b.OpsPerSecCILow, b.OpsPerSecCIHigh = 0, 0
+		b.Samples = p.n
+		b.Warnings = p.warningOrder
+		merged.Benchmarks[name] = b
+	}
+	merged.Crossovers = findCrossovers(merged.Benchmarks)
+	return merged
+}
+
+// writeMergedVersion writes merged data over path and renames the files it
+// absorbed to <name>.merged, so they are kept for reference but not pooled
+// a second time by the next rebuild.
+func writeMergedVersion(path string, merged *VersionData, absorbed []loadedVersionFile) error {
+	jsonData, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal merged %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write merged %s: %w", filepath.Base(path), err)
+	}
+
+	names := make([]string, 0, len(absorbed))
+	for _, lf := range absorbed {
+		names = append(names, lf.path)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.Rename(name, name+".merged"); err != nil {
+			return fmt.Errorf("failed to retire merged file %s: %w", filepath.Base(name), err)
+		}
+	}
+	return nil
+}
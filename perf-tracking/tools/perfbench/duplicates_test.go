@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMergeVersionData(t *testing.T) {
+	newest := &VersionData{
+		Version:  "1.25",
+		Metadata: VersionMetadata{CollectedAt: "2026-02-01T00:00:00Z"},
+		Benchmarks: map[string]Benchmark{
+			// samples {100, 200}
+			"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: 150, NsPerOpStddev: 50, Samples: 2, AllocsPerOp: 3,
+				OpsPerSec: 8e6, OpsPerSecCILow: 7e6, OpsPerSecCIHigh: 9e6},
+			"BenchmarkNew": {Name: "BenchmarkNew", NsPerOp: 10, Samples: 5},
+		},
+	}
+	older := &VersionData{
+		Version:  "1.25",
+		Metadata: VersionMetadata{CollectedAt: "2026-01-01T00:00:00Z"},
+		Benchmarks: map[string]Benchmark{
+			// samples {300, 400}
+			"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: 350, NsPerOpStddev: 50, Samples: 2, AllocsPerOp: 4,
+				OpsPerSec: 2e6, Warnings: []string{"allocs/op changes between samples (3-4)"}},
+			"BenchmarkOld": {Name: "BenchmarkOld", NsPerOp: 20, Samples: 3, OpsPerSec: 5e7},
+		},
+	}
+	oldest := &VersionData{ // exported before ops_per_sec existed
+		Version:  "1.25",
+		Metadata: VersionMetadata{CollectedAt: "2025-12-01T00:00:00Z"},
+		Benchmarks: map[string]Benchmark{
+			"BenchmarkOld": {Name: "BenchmarkOld", NsPerOp: 20, Samples: 3},
+		},
+	}
+
+	merged := mergeVersionData([]*VersionData{newest, older, oldest})
+
+	if merged.Metadata.CollectedAt != "2026-02-01T00:00:00Z" {
+		t.Errorf("metadata should come from the newest file, got %q", merged.Metadata.CollectedAt)
+	}
+
+	foo := merged.Benchmarks["BenchmarkFoo"]
+	// Population stats of {100, 200, 300, 400}: mean 250, stddev √12500
+	if foo.NsPerOp != 250 || foo.Samples != 4 {
+		t.Errorf("pooled mean/samples = %v/%d, want 250/4", foo.NsPerOp, foo.Samples)
+	}
+	if math.Abs(foo.NsPerOpStddev-math.Sqrt(12500)) > 1e-9 {
+		t.Errorf("pooled stddev = %v, want %v", foo.NsPerOpStddev, math.Sqrt(12500))
+	}
+	if math.Abs(foo.NsPerOpVariance-math.Sqrt(12500)/250) > 1e-9 {
+		t.Errorf("pooled CV = %v", foo.NsPerOpVariance)
+	}
+	if foo.OpsPerSec != 5e6 || foo.OpsPerSecCILow != 0 || foo.OpsPerSecCIHigh != 0 {
+		t.Errorf("pooled ops/sec = %v [%v, %v], want 5e6 without an interval",
+			foo.OpsPerSec, foo.OpsPerSecCILow, foo.OpsPerSecCIHigh)
+	}
+	if foo.AllocsPerOp != 3 || len(foo.Warnings) != 1 {
+		t.Errorf("allocs/warnings not taken from sources: %+v", foo)
+	}
+	if merged.Benchmarks["BenchmarkNew"].Samples != 5 {
+		t.Errorf("benchmark present in one file only should be kept as-is")
+	}
+	if old := merged.Benchmarks["BenchmarkOld"]; old.Samples != 6 || old.OpsPerSec != 5e7 {
+		t.Errorf("BenchmarkOld = %+v, want ops/sec pooled from the file that has it", old)
+	}
+}
+
+func TestRebuildIndexDuplicatePolicy(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		write := func(name string, ns float64, mtime time.Time) {
+			vd := VersionData{
+				Version: "1.25",
+				Benchmarks: map[string]Benchmark{
+					"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: ns, Samples: 10},
+				},
+			}
+			data, err := json.Marshal(vd)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatalf("failed to set mtime: %v", err)
+			}
+		}
+		now := time.Now()
+		write("go1.25.json", 100, now)
+		write("go1.25.0.json", 200, now.Add(-time.Hour))
+		return dir
+	}
+
+	t.Run("error", func(t *testing.T) {
+		dir := setup(t)
+		if err := rebuildIndex(dir, t.TempDir(), "linux-amd64", ExportOptions{OnDuplicate: DuplicateError}); err == nil {
+			t.Error("expected error for duplicate version")
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		dir := setup(t)
+		if err := rebuildIndex(dir, t.TempDir(), "linux-amd64", ExportOptions{OnDuplicate: DuplicateMerge}); err != nil {
+			t.Fatalf("rebuildIndex failed: %v", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, "go1.25.json"))
+		if err != nil {
+			t.Fatalf("failed to read merged file: %v", err)
+		}
+		var vd VersionData
+		if err := json.Unmarshal(data, &vd); err != nil {
+			t.Fatalf("failed to parse merged file: %v", err)
+		}
+		if foo := vd.Benchmarks["BenchmarkFoo"]; foo.NsPerOp != 150 || foo.Samples != 20 {
+			t.Errorf("merged BenchmarkFoo = %+v, want mean 150 over 20 samples", foo)
+		}
+
+		// The absorbed file is retired so a second rebuild does not pool it again
+		if _, err := os.Stat(filepath.Join(dir, "go1.25.0.json.merged")); err != nil {
+			t.Errorf("absorbed file not retired: %v", err)
+		}
+		if err := rebuildIndex(dir, t.TempDir(), "linux-amd64", ExportOptions{OnDuplicate: DuplicateMerge}); err != nil {
+			t.Fatalf("second rebuildIndex failed: %v", err)
+		}
+	})
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	for _, valid := range []string{"keep-newest", "merge", "error"} {
+		if _, err := parseDuplicatePolicy(valid); err != nil {
+			t.Errorf("parseDuplicatePolicy(%q) failed: %v", valid, err)
+		}
+	}
+	if _, err := parseDuplicatePolicy("newest"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
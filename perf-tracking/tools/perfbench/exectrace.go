@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/benchstats"
+	"golang.org/x/exp/trace"
+)
+
+// ExecTrace summarizes the runtime/trace execution trace of one go test run
+// of a benchmark, captured by collect_benchmarks.py --trace. Like GCTrace it
+// covers the whole run including b.N calibration, so compare it across
+// versions rather than per op.
+type ExecTrace struct {
+	// Time goroutines spent runnable before a P picked them up
+	SchedLatencyP50Micros float64 `json:"sched_latency_p50_us"`
+	SchedLatencyP99Micros float64 `json:"sched_latency_p99_us"`
+	// GC stop-the-world pauses (sweep and mark termination); other pauses,
+	// such as runtime.ReadMemStats in a benchmark, are left out
+	GCSTWCount  int     `json:"gc_stw_count"`
+	GCSTWMillis float64 `json:"gc_stw_ms"`
+	// Goroutine time parked in the network poller, summed over goroutines
+	NetPollWaitMillis float64 `json:"netpoll_wait_ms"`
+}
+
+// execTraceExt is the extension of each trace file in the sidecar directory
+const execTraceExt = ".trace"
+
+// execTraceDir returns the directory holding the execution traces for a
+// benchmark result file, one <escaped name>.trace file per benchmark
+func execTraceDir(benchFile string) string {
+	return strings.TrimSuffix(benchFile, filepath.Ext(benchFile)) + "_exectrace"
+}
+
+// loadExecTraces summarizes every trace in the sidecar directory of
+// benchFile, keyed by benchmark name. Returns nil without error when the run
+// had no --trace. A trace that fails to parse is reported and skipped.
+func loadExecTraces(benchFile string) (map[string]*ExecTrace, error) {
+	files, errs := benchmarkFiles(execTraceDir(benchFile), execTraceExt)
+	if len(files) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	traces := make(map[string]*ExecTrace, len(files))
+	for name, path := range files {
+		summary, err := summarizeExecTraceFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		traces[name] = summary
+	}
+	return traces, errors.Join(errs...)
+}
+
+// benchmarkFiles lists the per-benchmark files with extension ext in a
+// sidecar directory, keyed by benchmark name; the runner percent-escapes
+// names so sub-benchmarks stay one file. A missing directory yields none.
+func benchmarkFiles(dir, ext string) (map[string]string, []error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list %s: %w", dir, err)}
+	}
+	files := make(map[string]string, len(paths))
+	var errs []error
+	for _, path := range paths {
+		name, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(path), ext))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unexpected file name %s: %w", path, err))
+			continue
+		}
+		files[name] = path
+	}
+	return files, errs
+}
+
+func summarizeExecTraceFile(path string) (*ExecTrace, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }() // read-only
+
+	summary, err := summarizeExecTrace(bufio.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trace %s: %w", path, err)
+	}
+	return summary, nil
+}
+
+// summarizeExecTrace reads a Go 1.22+ execution trace and derives the
+// ExecTrace metrics from goroutine state transitions and STW ranges.
+func summarizeExecTrace(r io.Reader) (*ExecTrace, error) {
+	reader, err := trace.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		summary   ExecTrace
+		runnable  = make(map[trace.GoID]trace.Time) // became runnable at
+		netWait   = make(map[trace.GoID]trace.Time) // blocked on network at
+		stwStart  = make(map[trace.ResourceID]trace.Time)
+		latencies []float64 // microseconds
+		netTotal  time.Duration
+		stwTotal  time.Duration
+	)
+	for {
+		ev, err := reader.ReadEvent()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch ev.Kind() {
+		case trace.EventStateTransition:
+			st := ev.StateTransition()
+			if st.Resource.Kind != trace.ResourceGoroutine {
+				continue
+			}
+			id := st.Resource.Goroutine()
+			from, to := st.Goroutine()
+			if from == trace.GoRunnable && to == trace.GoRunning {
+				if at, ok := runnable[id]; ok {
+					latencies = append(latencies, float64(ev.Time().Sub(at))/float64(time.Microsecond))
+				}
+			}
+			if from == trace.GoWaiting {
+				if at, ok := netWait[id]; ok {
+					netTotal += ev.Time().Sub(at)
+				}
+			}
+			delete(runnable, id)
+			delete(netWait, id)
+			switch {
+			case to == trace.GoRunnable:
+				runnable[id] = ev.Time()
+			case to == trace.GoWaiting && st.Reason == "network":
+				netWait[id] = ev.Time()
+			}
+
+		case trace.EventRangeBegin, trace.EventRangeEnd:
+			rng := ev.Range()
+			if !isGCStopTheWorld(rng.Name) {
+				continue
+			}
+			if ev.Kind() == trace.EventRangeBegin {
+				summary.GCSTWCount++
+				stwStart[rng.Scope] = ev.Time()
+			} else if at, ok := stwStart[rng.Scope]; ok {
+				stwTotal += ev.Time().Sub(at)
+				delete(stwStart, rng.Scope)
+			}
+		}
+	}
+
+	slices.Sort(latencies)
+	summary.SchedLatencyP50Micros = benchstats.Percentile(latencies, 50)
+	summary.SchedLatencyP99Micros = benchstats.Percentile(latencies, 99)
+	summary.GCSTWMillis = float64(stwTotal) / float64(time.Millisecond)
+	summary.NetPollWaitMillis = float64(netTotal) / float64(time.Millisecond)
+	return &summary, nil
+}
+
+// isGCStopTheWorld reports whether a range name is a GC stop-the-world
+// pause, e.g. "stop-the-world (GC mark termination)"
+func isGCStopTheWorld(name string) bool {
+	reason, ok := strings.CutPrefix(name, "stop-the-world (")
+	return ok && strings.HasPrefix(reason, "GC ")
+}
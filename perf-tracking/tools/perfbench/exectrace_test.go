@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/trace"
+	"testing"
+	"time"
+)
+
+// recordExecTrace traces a forced GC and a goroutine blocked in a loopback
+// read for at least 10ms.
+func recordExecTrace(t *testing.T) []byte {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		_, err = conn.Read(make([]byte, 1))
+		done <- err
+	}()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		trace.Stop()
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	runtime.GC()
+	_, err = conn.Write([]byte{1})
+	if err == nil {
+		err = <-done
+	}
+	conn.Close()
+	trace.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSummarizeExecTrace(t *testing.T) {
+	summary, err := summarizeExecTrace(bytes.NewReader(recordExecTrace(t)))
+	if err != nil {
+		t.Fatalf("summarizeExecTrace failed: %v", err)
+	}
+	// runtime.GC runs a full cycle: sweep and mark termination
+	if summary.GCSTWCount < 2 || summary.GCSTWMillis <= 0 {
+		t.Errorf("GC STW = %d pauses, %vms; want at least 2 pauses", summary.GCSTWCount, summary.GCSTWMillis)
+	}
+	if summary.NetPollWaitMillis < 10 {
+		t.Errorf("NetPollWaitMillis = %v, want >= 10", summary.NetPollWaitMillis)
+	}
+	if summary.SchedLatencyP50Micros <= 0 || summary.SchedLatencyP99Micros < summary.SchedLatencyP50Micros {
+		t.Errorf("sched latency p50 %v, p99 %v", summary.SchedLatencyP50Micros, summary.SchedLatencyP99Micros)
+	}
+
+	if _, err := summarizeExecTrace(bytes.NewReader([]byte("not a trace"))); err == nil {
+		t.Error("expected an error for a non-trace input")
+	}
+}
+
+func TestLoadExecTraces(t *testing.T) {
+	dir := t.TempDir()
+	benchFile := filepath.Join(dir, "2026-01-26_21-55-10.txt")
+	benchOutput := `goos: linux
+goarch: amd64
+cpu: Test CPU
+BenchmarkHTTPService/Sequential-16   	   10000	    118810 ns/op
+BenchmarkHTTPService/Parallel-16     	   10000	    115918 ns/op
+BenchmarkTCPConnect/Sequential-16    	   10000	     50000 ns/op
+`
+	if err := os.WriteFile(benchFile, []byte(benchOutput), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err := loadExecTraces(benchFile)
+	if err != nil || traces != nil {
+		t.Fatalf("missing sidecar: got %v, %v; want nil, nil", traces, err)
+	}
+
+	traceDir := execTraceDir(benchFile)
+	if err := os.Mkdir(traceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := recordExecTrace(t)
+	files := map[string][]byte{
+		"BenchmarkHTTPService.trace":               data,
+		"BenchmarkTCPConnect%2FSequential.trace":   data,
+		"BenchmarkTCPConnect%2FParallel.trace":     []byte("truncated"),
+		"BenchmarkHTTPService_unrelated_notes.txt": []byte("ignored"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(traceDir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	traces, err = loadExecTraces(benchFile)
+	if err == nil {
+		t.Error("expected an error for the truncated trace")
+	}
+	if len(traces) != 2 || traces["BenchmarkHTTPService"] == nil || traces["BenchmarkTCPConnect/Sequential"] == nil {
+		t.Fatalf("traces = %v, want the two valid ones", traces)
+	}
+
+	// Sub-benchmarks inherit the trace of the benchmark that was traced
+	vd, err := parseBenchmarkFile(benchFile, "1.25")
+	if err != nil {
+		t.Fatalf("parseBenchmarkFile failed: %v", err)
+	}
+	for _, name := range []string{"BenchmarkHTTPService/Sequential", "BenchmarkHTTPService/Parallel", "BenchmarkTCPConnect/Sequential"} {
+		if got := vd.Benchmarks[name].ExecTrace; got == nil || got.GCSTWCount == 0 {
+			t.Errorf("%s: ExecTrace = %+v, want a summary", name, got)
+		}
+	}
+	if got := vd.Benchmarks["BenchmarkHTTPService/Sequential"].GCTrace; got != nil {
+		t.Errorf("GCTrace = %+v without a gctrace sidecar", got)
+	}
+}
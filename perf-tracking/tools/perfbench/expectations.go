@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Outcomes of an expected improvement in a comparison
+const (
+	expectationMet         = "met"          // significant, at least the expected speedup
+	expectationPartial     = "partial"      // significant improvement, but smaller
+	expectationNotObserved = "not observed" // unchanged or slower
+	expectationMissing     = "missing"      // no compared benchmark matches
+)
+
+// Expectation is an improvement a benchmark's doc comment promises, such as
+// "Go 1.26 shows ~2x faster performance" on BenchmarkIOReadAll, in a form
+// compare -check-expectations can verify. Read from a file like:
+//
+//	{"expectations": [
+//	  {"benchmark": "BenchmarkIOReadAll", "since": "1.26", "speedup": 2, "note": "io.ReadAll ~2x faster"},
+//	  {"benchmark": "BenchmarkSHA/SHA1", "since": "1.26", "speedup": 2, "goarch": "amd64"}
+//	]}
+type Expectation struct {
+	// Benchmark is a full name, or a prefix covering its sub-benchmarks
+	Benchmark string `json:"benchmark"`
+	// Since is the Go version the improvement ships in
+	Since string `json:"since"`
+	// Speedup is the expected baseline/target ns/op, 2 for "2x faster" and
+	// 1.3 for "30% faster"
+	Speedup float64 `json:"speedup"`
+	// GOOS and GOARCH limit the expectation to the target's platform, for
+	// gains from hardware support such as SHA-NI
+	GOOS   string `json:"goos,omitempty"`
+	GOARCH string `json:"goarch,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// loadExpectations reads an expectations file
+func loadExpectations(path string) ([]Expectation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expectations: %w", err)
+	}
+	var file struct {
+		Expectations []Expectation `json:"expectations"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse expectations %s: %w", path, err)
+	}
+	for i, e := range file.Expectations {
+		switch {
+		case !strings.HasPrefix(e.Benchmark, "Benchmark"):
+			return nil, fmt.Errorf("%s: expectation %d: invalid benchmark %q", path, i+1, e.Benchmark)
+		case !knownGoVersion(e.Since):
+			return nil, fmt.Errorf("%s: %s: invalid since %q (want a Go version, e.g. 1.26)", path, e.Benchmark, e.Since)
+		case e.Speedup <= 1:
+			return nil, fmt.Errorf("%s: %s: speedup %v is no improvement (want more than 1)", path, e.Benchmark, e.Speedup)
+		}
+	}
+	return file.Expectations, nil
+}
+
+// knownGoVersion reports whether v names a Go version, with or without the
+// go prefix, rather than the file name a raw result is labeled with
+func knownGoVersion(v string) bool {
+	return versionDirPattern.MatchString("go" + strings.TrimPrefix(v, "go"))
+}
+
+// matches reports whether the expectation covers the benchmark name
+func (e Expectation) matches(name string) bool {
+	return name == e.Benchmark || strings.HasPrefix(name, e.Benchmark+"/")
+}
+
+// appliesTo reports whether a comparison of baseline with target should
+// show the improvement: the target's platform qualifies and the versions
+// straddle Since. Runs without a known version are checked against every
+// expectation.
+func (e Expectation) appliesTo(baseline, target Metadata) bool {
+	if e.GOOS != "" && target.Runner.OS != "" && e.GOOS != target.Runner.OS {
+		return false
+	}
+	if e.GOARCH != "" && target.Runner.Arch != "" && e.GOARCH != target.Runner.Arch {
+		return false
+	}
+	base, tgt := strings.TrimPrefix(baseline.GoVersion, "go"), strings.TrimPrefix(target.GoVersion, "go")
+	if !knownGoVersion(base) || !knownGoVersion(tgt) {
+		return true
+	}
+	return compareVersionStrings(base, e.Since) < 0 && compareVersionStrings(tgt, e.Since) >= 0
+}
+
+// ExpectationResult is the outcome of an expectation for one benchmark
+type ExpectationResult struct {
+	Expected  Expectation
+	Benchmark string  // the compared benchmark, empty when missing
+	Speedup   float64 // observed baseline/target ns/op
+	Status    string
+}
+
+// checkExpectations checks the expectations that apply to a comparison of
+// baseline with target, one result per matching benchmark in comparison
+// order. It also returns how many expectations did not apply.
+func checkExpectations(expectations []Expectation, comparisons []Comparison, baseline, target Metadata) ([]ExpectationResult, int) {
+	var results []ExpectationResult
+	skipped := 0
+	for _, e := range expectations {
+		if !e.appliesTo(baseline, target) {
+			skipped++
+			continue
+		}
+		found := false
+		for _, c := range comparisons {
+			if !e.matches(c.Benchmark) || c.TargetNs <= 0 {
+				continue
+			}
+			found = true
+			r := ExpectationResult{Expected: e, Benchmark: c.Benchmark, Speedup: c.BaselineNs / c.TargetNs}
+			switch {
+			case !c.significant() || c.DeltaPercent >= 0:
+				r.Status = expectationNotObserved
+			case r.Speedup >= e.Speedup:
+				r.Status = expectationMet
+			default:
+				r.Status = expectationPartial
+			}
+			results = append(results, r)
+		}
+		if !found {
+			results = append(results, ExpectationResult{Expected: e, Status: expectationMissing})
+		}
+	}
+	return results, skipped
+}
+
+// printExpectations prints the outcome of every applicable expectation
+func printExpectations(w io.Writer, results []ExpectationResult, skipped int, baseline, target Metadata) {
+	fmt.Fprintf(w, "\n=== Expected Improvements (%s -> %s) ===\n\n", baseline.GoVersion, target.GoVersion)
+	nameWidth := len("Benchmark")
+	for _, r := range results {
+		nameWidth = max(nameWidth, len(r.Benchmark), len(r.Expected.Benchmark))
+	}
+
+	fmt.Fprintf(w, "%-*s %-7s %9s %9s  %-12s %s\n", nameWidth, "Benchmark", "Since", "Expected", "Observed", "Status", "Note")
+	met := 0
+	for _, r := range results {
+		name, observed := r.Benchmark, fmt.Sprintf("%.2fx", r.Speedup)
+		if r.Status == expectationMissing {
+			name, observed = r.Expected.Benchmark, "-"
+		}
+		if r.Status == expectationMet {
+			met++
+		}
+		line := fmt.Sprintf("%-*s %-7s %8.2fx %9s  %-12s %s", nameWidth, name, r.Expected.Since, r.Expected.Speedup,
+			observed, r.Status, r.Expected.Note)
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+	fmt.Fprintf(w, "\n%d of %d expected improvements observed", met, len(results))
+	if skipped > 0 {
+		fmt.Fprintf(w, "; %d expectations do not apply to this comparison", skipped)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckExpectations(t *testing.T) {
+	expectations := []Expectation{
+		{Benchmark: "BenchmarkIOReadAll", Since: "1.26", Speedup: 2},
+		{Benchmark: "BenchmarkSmallAllocation", Since: "1.26", Speedup: 1.3},
+		{Benchmark: "BenchmarkRSAKeyGen", Since: "1.26", Speedup: 3},
+		{Benchmark: "BenchmarkSHA/SHA1", Since: "1.26", Speedup: 2, GOARCH: "amd64"},
+		{Benchmark: "BenchmarkSHA/SHA3_256", Since: "1.26", Speedup: 2, GOARCH: "arm64"},
+		{Benchmark: "BenchmarkSwissMapLarge", Since: "1.24", Speedup: 1.3},
+		{Benchmark: "BenchmarkGCThroughput", Since: "1.26", Speedup: 1.1},
+	}
+	comparison := func(name string, base, target, p float64) Comparison {
+		return Comparison{Benchmark: name, BaselineNs: base, TargetNs: target, DeltaPercent: (target/base - 1) * 100,
+			BaselineSamples: 10, TargetSamples: 10, PValue: p}
+	}
+	comparisons := []Comparison{
+		comparison("BenchmarkIOReadAll/1KB", 1000, 450, 0.001),
+		comparison("BenchmarkIOReadAll/1MB", 1e6, 800e3, 0.001),
+		comparison("BenchmarkSmallAllocation", 20, 19, 0.4),
+		comparison("BenchmarkSHA/SHA1", 100, 48, 0.001),
+		comparison("BenchmarkSHA/SHA3_256", 100, 50, 0.001),
+		comparison("BenchmarkSwissMapLarge", 100, 50, 0.001),
+		comparison("BenchmarkGCThroughput", 100, 105, 0.001),
+	}
+	baseline := Metadata{GoVersion: "1.25"}
+	target := Metadata{GoVersion: "go1.26.1"}
+	target.Runner.Arch = "amd64"
+
+	results, skipped := checkExpectations(expectations, comparisons, baseline, target)
+	// SHA3 needs arm64 and Swiss maps shipped in 1.24, before the baseline
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want 2", skipped)
+	}
+	var got []string
+	for _, r := range results {
+		name := r.Benchmark
+		if name == "" {
+			name = r.Expected.Benchmark
+		}
+		got = append(got, name+"="+r.Status)
+	}
+	want := []string{
+		"BenchmarkIOReadAll/1KB=met",
+		"BenchmarkIOReadAll/1MB=partial",
+		"BenchmarkSmallAllocation=not observed",
+		"BenchmarkRSAKeyGen=missing",
+		"BenchmarkSHA/SHA1=met",
+		"BenchmarkGCThroughput=not observed",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("results:\n got %v\nwant %v", got, want)
+	}
+
+	// Raw results named after their files check every expectation
+	results, skipped = checkExpectations(expectations[5:6], comparisons, Metadata{GoVersion: "old.txt"}, Metadata{GoVersion: "new.txt"})
+	if skipped != 0 || len(results) != 1 || results[0].Status != expectationMet {
+		t.Errorf("unknown versions: %+v, skipped %d", results, skipped)
+	}
+
+	var out bytes.Buffer
+	printExpectations(&out, results, 0, baseline, target)
+	if !strings.Contains(out.String(), "1 of 1 expected improvements observed") {
+		t.Errorf("report:\n%s", out.String())
+	}
+}
+
+func TestLoadExpectations(t *testing.T) {
+	// The expectations shipped with the benchmarks must load and name
+	// tracked benchmarks
+	expectations, err := loadExpectations(filepath.Join("..", "..", "benchmarks", "expectations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expectations) == 0 {
+		t.Fatal("no expectations")
+	}
+	for _, e := range expectations {
+		if getBenchmarkDescription(e.Benchmark) == "" {
+			t.Errorf("%s is not a tracked benchmark", e.Benchmark)
+		}
+	}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"name.json":    `{"expectations": [{"benchmark": "IOReadAll", "since": "1.26", "speedup": 2}]}`,
+		"since.json":   `{"expectations": [{"benchmark": "BenchmarkIOReadAll", "since": "next", "speedup": 2}]}`,
+		"speedup.json": `{"expectations": [{"benchmark": "BenchmarkIOReadAll", "since": "1.26", "speedup": 0.5}]}`,
+		"syntax.json":  `{"expectations": [`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadExpectations(path); err == nil {
+			t.Errorf("%s: loaded, want error", name)
+		}
+	}
+}
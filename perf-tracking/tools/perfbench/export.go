@@ -0,0 +1,1685 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/benchparse"
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/benchstats"
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+// VersionData represents all benchmarks for a single Go version
+type VersionData struct {
+	Version    string               `json:"version"`
+	Metadata   VersionMetadata      `json:"metadata"`
+	Benchmarks map[string]Benchmark `json:"benchmarks"`
+
+	// Raw result files archived with --keep-raw, relative to this file's
+	// directory and newest first; the first one is what this export used
+	RawFiles []string `json:"raw_files,omitempty"`
+
+	// Crossovers are the lookup strategy crossover sizes, see findCrossovers
+	Crossovers []Crossover `json:"crossovers,omitempty"`
+}
+
+type VersionMetadata struct {
+	GoVersionFull      string          `json:"go_version_full"`
+	CollectedAt        string          `json:"collected_at"`
+	BenchmarkSourceSHA string          `json:"benchmark_source_sha,omitempty"`
+	System             SystemInfo      `json:"system"`
+	BenchmarkConfig    BenchmarkConfig `json:"benchmark_config"`
+	Build              *BuildMetrics   `json:"build,omitempty"`
+	// ContentionProfile marks a run collected with --contention-profile,
+	// whose ns/op includes the profiling overhead
+	ContentionProfile bool `json:"contention_profile,omitempty"`
+}
+
+type SystemInfo struct {
+	CPU  string `json:"cpu"`
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+
+	// Collected natively by the runner (see RunMetadata); zero when unknown
+	PhysicalCores int    `json:"physical_cores,omitempty"`
+	LogicalCores  int    `json:"logical_cores,omitempty"`
+	BaseClockMHz  int    `json:"base_clock_mhz,omitempty"`
+	BoostClockMHz int    `json:"boost_clock_mhz,omitempty"`
+	MemoryBytes   int64  `json:"memory_bytes,omitempty"`
+	PowerSource   string `json:"power_source,omitempty"` // "ac" or "battery"
+
+	Pinning *PinningInfo `json:"pinning,omitempty"`
+	Tuning  *TuningInfo  `json:"tuning,omitempty"`
+}
+
+// PinningInfo describes how benchmark processes were bound to CPUs
+type PinningInfo struct {
+	Method   string `json:"method"` // "taskset" or "cpuset"
+	CPUs     []int  `json:"cpus"`
+	Cgroup   string `json:"cgroup,omitempty"`
+	Isolated bool   `json:"isolated"`
+}
+
+// TuningInfo records the system tuning the runner applied for the run.
+// Only settings that were successfully applied are present.
+type TuningInfo struct {
+	Nice               *int   `json:"nice,omitempty"`
+	Governor           string `json:"governor,omitempty"`
+	TurboDisabled      bool   `json:"turbo_disabled,omitempty"`
+	IdleStatesDisabled bool   `json:"idle_states_disabled,omitempty"`
+}
+
+// RunMetadata is the sidecar written by collect_benchmarks.py next to each
+// result file (<timestamp>.txt -> <timestamp>_metadata.json)
+type RunMetadata struct {
+	// Last commit touching perf-tracking/benchmarks when the run was collected
+	BenchmarkSourceSHA string        `json:"benchmark_source_sha,omitempty"`
+	System             SystemInfo    `json:"system"`
+	Build              *BuildMetrics `json:"build,omitempty"`
+	// Mutex and block profiling were on (--contention-profile)
+	ContentionProfile bool `json:"contention_profile,omitempty"`
+}
+
+// BuildMetrics records how long the toolchain takes to build a representative
+// module (perf-tracking/buildtarget) from an empty build cache, and the size
+// of the resulting binary. Tracked per version next to the runtime benchmarks.
+type BuildMetrics struct {
+	Module          string    `json:"module"`
+	WallSeconds     float64   `json:"wall_seconds"` // median of the cold builds
+	WallSecondsRuns []float64 `json:"wall_seconds_runs,omitempty"`
+	BinaryBytes     int64     `json:"binary_bytes"`
+}
+
+type BenchmarkConfig struct {
+	Iterations int    `json:"iterations"`
+	Benchtime  string `json:"benchtime"`
+}
+
+type Benchmark struct {
+	Name            string   `json:"name"`
+	NsPerOp         float64  `json:"ns_per_op"`
+	NsPerOpStddev   float64  `json:"ns_per_op_stddev"`
+	NsPerOpVariance float64  `json:"ns_per_op_variance"`
+	NsPerOpP50      float64  `json:"ns_per_op_p50,omitempty"`
+	NsPerOpP95      float64  `json:"ns_per_op_p95,omitempty"`
+	OpsPerSec       float64  `json:"ops_per_sec,omitempty"` // see opsPerSec
+	OpsPerSecCILow  float64  `json:"ops_per_sec_ci_low,omitempty"`
+	OpsPerSecCIHigh float64  `json:"ops_per_sec_ci_high,omitempty"`
+	BytesPerOp      int64    `json:"bytes_per_op"`
+	AllocsPerOp     int64    `json:"allocs_per_op"`
+	Iterations      int64    `json:"iterations"`
+	Samples         int      `json:"samples"`
+	Description     string   `json:"description,omitempty"`
+	Category        string   `json:"category,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"` // plausibility problems, see checkPlausibility
+
+	// Custom metrics from b.ReportMetric (e.g. latency percentiles), mean
+	// across the samples that reported them
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+
+	// GC activity from a separate GODEBUG=gctrace=1 run, when requested
+	GCTrace *GCTrace `json:"gctrace,omitempty"`
+
+	// Scheduler, GC pause and netpoller summary of a separate -trace run
+	ExecTrace *ExecTrace `json:"exectrace,omitempty"`
+
+	// Top allocation call sites of a separate -memprofile run
+	AllocProfile *AllocProfile `json:"alloc_profile,omitempty"`
+}
+
+// BenchmarkSample represents a single benchmark run
+type BenchmarkSample struct {
+	NsPerOp     float64
+	MBPerSec    float64
+	BytesPerOp  int64
+	AllocsPerOp int64
+	Iterations  int64
+	Metrics     map[string]float64
+}
+
+// parseBenchmarkFile parses a raw benchmark result file
+func parseBenchmarkFile(filename, version string) (*VersionData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = file.Close() }() // read-only; close errors don't affect parsed data
+
+	versionData := &VersionData{
+		Version:    version,
+		Benchmarks: make(map[string]Benchmark),
+	}
+
+	parsed, err := benchparse.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	cpu, goos, goarch := parsed.Config["cpu"], parsed.Config["goos"], parsed.Config["goarch"]
+
+	// Collect samples for each benchmark
+	samples := make(map[string][]BenchmarkSample)
+	for _, r := range parsed.Results {
+		samples[r.Name] = append(samples[r.Name], BenchmarkSample{
+			NsPerOp:     r.NsPerOp,
+			MBPerSec:    r.MBPerSec,
+			BytesPerOp:  r.BytesPerOp,
+			AllocsPerOp: r.AllocsPerOp,
+			Iterations:  1, // We don't track iterations per sample
+			Metrics:     r.Metrics,
+		})
+	}
+
+	// Calculate statistics for each benchmark
+	for name, sampleList := range samples {
+		ns := make([]float64, len(sampleList))
+		for i, s := range sampleList {
+			ns[i] = s.NsPerOp
+		}
+		summary := benchstats.Summarize(ns)
+
+		ops, opsLow, opsHigh := opsPerSec(ns)
+
+		// Use last sample for bytes/allocs (they should be consistent)
+		lastSample := sampleList[len(sampleList)-1]
+
+		versionData.Benchmarks[name] = Benchmark{
+			Name:            name,
+			NsPerOp:         summary.Mean,
+			NsPerOpStddev:   summary.StdDev,
+			NsPerOpVariance: summary.CV,
+			NsPerOpP50:      summary.P50,
+			NsPerOpP95:      summary.P95,
+			OpsPerSec:       ops,
+			OpsPerSecCILow:  opsLow,
+			OpsPerSecCIHigh: opsHigh,
+			BytesPerOp:      lastSample.BytesPerOp,
+			AllocsPerOp:     lastSample.AllocsPerOp,
+			Metrics:         meanMetrics(sampleList),
+			Samples:         len(sampleList),
+			Description:     getBenchmarkDescription(name),
+			Category:        getBenchmarkCategory(name),
+			Warnings:        checkPlausibility(name, sampleList),
+		}
+	}
+
+	// Set metadata
+	fileInfo, _ := os.Stat(filename)
+
+	// Note: version will be set by caller, so use it if available, else empty
+	goVersionStr := versionData.Version
+	if goVersionStr == "" {
+		goVersionStr = "unknown"
+	}
+
+	versionData.Metadata = VersionMetadata{
+		GoVersionFull: fmt.Sprintf("go version go%s %s/%s", goVersionStr, goos, goarch),
+		CollectedAt:   fileInfo.ModTime().Format(time.RFC3339),
+		System: SystemInfo{
+			CPU:  cpu,
+			OS:   goos,
+			Arch: goarch,
+		},
+		BenchmarkConfig: BenchmarkConfig{
+			Iterations: 20,
+			Benchtime:  "3s",
+		},
+	}
+
+	runMeta, err := loadRunMetadata(filename)
+	if err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	} else if runMeta != nil {
+		versionData.Metadata.BenchmarkSourceSHA = runMeta.BenchmarkSourceSHA
+		versionData.Metadata.Build = runMeta.Build
+		versionData.Metadata.ContentionProfile = runMeta.ContentionProfile
+		mergeRunSystemInfo(&versionData.Metadata.System, runMeta.System)
+	}
+
+	traces, err := loadGCTrace(filename)
+	if err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	}
+	execTraces, err := loadExecTraces(filename)
+	if err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	}
+	allocProfiles, err := loadAllocProfiles(filename)
+	if err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	}
+	for name, bm := range versionData.Benchmarks {
+		bm.GCTrace = traceFor(traces, name)
+		bm.ExecTrace = traceFor(execTraces, name)
+		bm.AllocProfile = traceFor(allocProfiles, name)
+		versionData.Benchmarks[name] = bm
+	}
+	versionData.Crossovers = findCrossovers(versionData.Benchmarks)
+
+	return versionData, nil
+}
+
+// opsPerSec converts ns/op samples to operations per second and returns
+// their mean with its 95% confidence interval. The mean of the rates is
+// what a throughput-minded reader expects from repeated runs; it can differ
+// slightly from 1e9/ns_per_op when the samples vary.
+func opsPerSec(nsSamples []float64) (mean, low, high float64) {
+	rates := make([]float64, 0, len(nsSamples))
+	for _, ns := range nsSamples {
+		if ns > 0 {
+			rates = append(rates, 1e9/ns)
+		}
+	}
+	if len(rates) == 0 {
+		return 0, 0, 0
+	}
+	low, high = benchstats.MeanCI(rates)
+	return benchstats.Mean(rates), math.Max(low, 0), high
+}
+
+// meanMetrics averages each custom metric over the samples reporting it.
+func meanMetrics(samples []BenchmarkSample) map[string]float64 {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, s := range samples {
+		for unit, v := range s.Metrics {
+			sums[unit] += v
+			counts[unit]++
+		}
+	}
+	if len(sums) == 0 {
+		return nil
+	}
+	for unit := range sums {
+		sums[unit] /= float64(counts[unit])
+	}
+	return sums
+}
+
+// mergeRunSystemInfo copies runner-collected details into dst. CPU, OS and
+// Arch stay as printed by go test, which is authoritative for the binary.
+func mergeRunSystemInfo(dst *SystemInfo, src SystemInfo) {
+	dst.PhysicalCores = src.PhysicalCores
+	dst.LogicalCores = src.LogicalCores
+	dst.BaseClockMHz = src.BaseClockMHz
+	dst.BoostClockMHz = src.BoostClockMHz
+	dst.MemoryBytes = src.MemoryBytes
+	dst.PowerSource = src.PowerSource
+	dst.Pinning = src.Pinning
+	dst.Tuning = src.Tuning
+}
+
+// runMetadataPath returns the sidecar metadata path for a benchmark result file
+func runMetadataPath(benchFile string) string {
+	return strings.TrimSuffix(benchFile, filepath.Ext(benchFile)) + "_metadata.json"
+}
+
+// contentionProfiled reports whether the sidecar of benchFile records a run
+// collected with --contention-profile
+func contentionProfiled(benchFile string) bool {
+	meta, err := loadRunMetadata(benchFile)
+	return err == nil && meta != nil && meta.ContentionProfile
+}
+
+// onOff words a setting for messages
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// loadRunMetadata reads the runner's sidecar metadata for benchFile.
+// Returns nil without error when the sidecar does not exist (older runs).
+func loadRunMetadata(benchFile string) (*RunMetadata, error) {
+	path := runMetadataPath(benchFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run metadata %s: %w", path, err)
+	}
+
+	var meta RunMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse run metadata %s: %w", path, err)
+	}
+	return &meta, nil
+}
+
+// benchmarkBaseName strips the sub-benchmark path and CPU suffix,
+// e.g. "BenchmarkAESCTR/Size1KB-16" -> "BenchmarkAESCTR"
+func benchmarkBaseName(name string) string {
+	baseName := name
+	if idx := strings.Index(name, "/"); idx != -1 {
+		baseName = name[:idx]
+	}
+	if idx := strings.LastIndex(baseName, "-"); idx != -1 {
+		// Check if the suffix after '-' is a number (CPU count)
+		if idx+1 < len(baseName) {
+			isNumeric := true
+			for i := idx + 1; i < len(baseName); i++ {
+				if baseName[i] < '0' || baseName[i] > '9' {
+					isNumeric = false
+					break
+				}
+			}
+			if isNumeric {
+				baseName = baseName[:idx]
+			}
+		}
+	}
+	return baseName
+}
+
+// benchmarkDescriptions holds a human-readable description for every tracked
+// benchmark; a benchmark without an entry is not part of the published suite.
+var benchmarkDescriptions = map[string]string{
+	// Runtime/GC benchmarks
+	"BenchmarkSmallAllocation":       "64-byte allocation performance",
+	"BenchmarkMapCreation":           "Map creation with initial capacity",
+	"BenchmarkSwissMapCreation":      "Swiss map creation (Go 1.24+)",
+	"BenchmarkSwissMapLarge":         "Large Swiss map operations (Go 1.24+)",
+	"BenchmarkSwissMapPresized":      "Swiss map with presizing comparison (Go 1.24+)",
+	"BenchmarkSwissMapIteration":     "Swiss map iteration performance (Go 1.24+)",
+	"BenchmarkMapRange":              "Map iteration under eviction and at low load factors",
+	"BenchmarkMapKey":                "Map lookup with struct keys vs string-encoded keys",
+	"BenchmarkLookup":                "Linear scan vs binary search vs map lookup, 8 to 1M keys",
+	"BenchmarkSmallAllocSpecialized": "Specialized allocations per size class (16B-32KB)",
+	"BenchmarkAllocScan":             "Pointer-free vs pointer-full allocations (16B-4KB)",
+	"BenchmarkTinyAlloc":             "Tiny (<16B) and zero-size allocations",
+	"BenchmarkValueVsPointer":        "Passing structs and receivers by value vs pointer (16B-4KB)",
+	"BenchmarkClosure":               "Closure calls, creation and capture allocations",
+	"BenchmarkSliceConcat":           "Merging slices: append vs presized copy vs slices.Concat",
+	"BenchmarkSyncMap":               "sync.Map concurrent access patterns",
+	"BenchmarkGCThroughput":          "GC throughput with mixed allocation patterns",
+	"BenchmarkGCLatency":             "Average GC pause latency",
+	"BenchmarkGCLatencyP99":          "99th percentile GC pause latency",
+	"BenchmarkSmallObjectScanning":   "GC scanning of small object graphs",
+	"BenchmarkMediumObjectScanning":  "GC scanning of medium object graphs",
+	"BenchmarkLargeObjectScanning":   "GC scanning of large object graphs",
+	"BenchmarkAtomicIncrement":       "Atomic counter increment operations",
+	"BenchmarkMutexContention":       "Mutex contention under concurrent load",
+	"BenchmarkChannelThroughput":     "Channel send/receive throughput",
+	"BenchmarkTimeout":               "Timeout idioms: time.After vs timers vs context.WithTimeout",
+	"BenchmarkFanOut":                "Uneven tasks over a shared channel, static chunks and work stealing",
+	"BenchmarkGCMixedWorkload":       "GC performance with mixed allocation patterns",
+	"BenchmarkGCSmallObjects":        "GC performance with many small objects",
+	"BenchmarkGoroutineCreate":       "Goroutine creation and initialization",
+	"BenchmarkStackGrowth":           "Stack growth and shrinking performance",
+	"BenchmarkSyscall":               "Cheap syscalls and clock reads (getpid, time.Now, /dev/null I/O)",
+	"BenchmarkStartup":               "Process exec to first output: minimal binary vs large init graph",
+
+	// Standard library benchmarks
+	"BenchmarkJSONEncode":       "JSON encoding of structured data",
+	"BenchmarkJSONDecode":       "JSON decoding into Go structs",
+	"BenchmarkJSONDecodeStream": "Streaming JSON decoder performance",
+	"BenchmarkIOReadAll":        "io.ReadAll buffer reading performance",
+	"BenchmarkAESCTR":           "AES-CTR mode encryption throughput",
+	"BenchmarkAESGCM":           "AES-GCM authenticated encryption throughput",
+	"BenchmarkSHA":              "SHA hashing throughput (SHA-1, SHA-256, SHA-512, SHA3)",
+	"BenchmarkRSAKeyGen":        "RSA key generation performance",
+	"BenchmarkX509Verify":       "x509 certificate chain and hostname verification",
+	"BenchmarkRegexp":           "Regular expression matching and compilation",
+	"BenchmarkBufferedIO":       "Buffered I/O reader/writer performance",
+	"BenchmarkCRC32":            "CRC32 checksum calculation (IEEE, Castagnoli)",
+	"BenchmarkFNVHash":          "FNV-1a hash function performance",
+	"BenchmarkBinaryEncode":     "Binary encoding methods (encoding/binary)",
+	"BenchmarkBase64":           "encoding/base64 std/URL, padded/raw encode and decode at 64B-64KB",
+	"BenchmarkHex":              "encoding/hex encode and decode at 64B-64KB",
+	"BenchmarkStringsJoin":      "strings.Join with multiple strings",
+	"BenchmarkStringConcat":     "String concatenation: +, Sprintf, Builder, append",
+	"BenchmarkFileWatch":        "Detecting file changes: os.Stat polling vs fsnotify",
+	"BenchmarkRandomRead":       "Random 4KB file reads at queue depth 1/16/128: pread vs io_uring",
+	"BenchmarkDirWalk":          "Directory tree traversal of 1K/10K files: filepath.Walk vs WalkDir vs os.ReadDir vs parallel",
+	"BenchmarkArchive":          "archive/tar and archive/zip creation and extraction, stored and deflate-compressed",
+	"BenchmarkBytesSearch":      "bytes.Index/Contains/IndexByte on 64B and 4MB haystacks, early vs late match",
+	"BenchmarkIDGen":            "UUIDv4/ULID generation: crypto/rand vs pooled vs math/rand/v2, serial and parallel",
+	"BenchmarkParseIntBytes":    "Integers from []byte: strconv.ParseInt(string(b)) vs unsafe.String vs manual parsing",
+	"BenchmarkStructCopy":       "Struct copying: assignment vs field-by-field vs reflection vs gob/JSON round trip",
+	"BenchmarkSQLPrepared":      "database/sql prepared vs unprepared queries (SQLite)",
+	"BenchmarkSQLPoolAcquire":   "database/sql connection pool acquire/release",
+	"BenchmarkSQLScan":          "database/sql row scanning into structs vs sql.RawBytes",
+
+	// Legacy names for backwards compatibility
+	"BenchmarkReadAll":          "io.ReadAll with small buffers",
+	"BenchmarkReadAllLarge":     "io.ReadAll with large buffers (1MB+)",
+	"BenchmarkAESCTREncrypt":    "AES-CTR encryption throughput",
+	"BenchmarkSHA1Hash":         "SHA-1 hashing throughput",
+	"BenchmarkSHA3Hash":         "SHA-3 hashing throughput",
+	"BenchmarkRSAKeyGeneration": "RSA 2048-bit key generation",
+	"BenchmarkRegexpMatch":      "Regular expression matching",
+	"BenchmarkRegexpCompile":    "Regular expression compilation",
+
+	// Networking benchmarks
+	"BenchmarkTCPConnect":       "TCP connection establishment time",
+	"BenchmarkTCPKeepAlive":     "TCP keep-alive behavior and configuration",
+	"BenchmarkTCPThroughput":    "TCP data transfer throughput",
+	"BenchmarkTLSHandshake":     "TLS 1.3 handshake performance",
+	"BenchmarkTLSResume":        "TLS session resumption",
+	"BenchmarkTLSThroughput":    "TLS encrypted data transfer throughput",
+	"BenchmarkHTTP2":            "HTTP/2 request handling (sequential/parallel)",
+	"BenchmarkHTTPRequest":      "HTTP/1.1 request latency (GET/POST)",
+	"BenchmarkConnectionPool":   "Connection pool lifecycle and reuse",
+	"BenchmarkHTTPStreaming":    "HTTP chunked 10MB upload/download streaming",
+	"BenchmarkHTTPMiddleware":   "Nested vs flattened HTTP middleware stack overhead",
+	"BenchmarkHTTPService":      "End-to-end JSON API request over a pooled client",
+	"BenchmarkIdleConnScavenge": "Request bursts after keep-alive idle connections expire",
+
+	// Optional stdlib vs third-party comparisons (perf-tracking/alternatives)
+	"BenchmarkAltJSONDecode": "encoding/json vs jsoniter and goccy/go-json decoding",
+	"BenchmarkAltHTTPRouter": "net/http.ServeMux vs chi route matching",
+	"BenchmarkAltGzip":       "compress/gzip vs klauspost/compress gzip",
+
+	// Legacy runtime benchmarks for backwards compatibility
+	"BenchmarkLargeAllocation": "1MB allocation performance",
+	"BenchmarkMapAllocation":   "Map with 100 entries",
+	"BenchmarkSliceAppend":     "Slice growth with 1000 appends",
+	"BenchmarkGCPressure":      "GC behavior under allocation pressure",
+}
+
+// getBenchmarkDescription returns a human-readable description
+func getBenchmarkDescription(name string) string {
+	baseName := benchmarkBaseName(name)
+
+	// Try base name first, then fall back to full name for backwards compatibility
+	if desc, ok := benchmarkDescriptions[baseName]; ok {
+		return desc
+	}
+	return benchmarkDescriptions[name]
+}
+
+// guideTopics maps every topic a benchmark can be tagged with to the guide
+// article, relative to docs/, that covers it
+var guideTopics = map[string]string{
+	"atomics":        "01-common-patterns/atomic-ops.md",
+	"batching":       "01-common-patterns/batching-ops.md",
+	"buffered-io":    "01-common-patterns/buffered-io.md",
+	"context":        "01-common-patterns/context.md",
+	"gc":             "01-common-patterns/gc.md",
+	"immutable-data": "01-common-patterns/immutable-data.md",
+	"lazy-init":      "01-common-patterns/lazy-init.md",
+	"pooling":        "01-common-patterns/object-pooling.md",
+	"prealloc":       "01-common-patterns/mem-prealloc.md",
+	"set-membership": "01-common-patterns/set-membership.md",
+	"stack-alloc":    "01-common-patterns/stack-alloc.md",
+	"worker-pool":    "01-common-patterns/worker-pool.md",
+	"zero-copy":      "01-common-patterns/zero-copy.md",
+	"connections":    "02-networking/long-lived-connections.md",
+	"http2":          "02-networking/tcp-http2-grpc.md",
+	"tls":            "02-networking/tls-for-speed.md",
+}
+
+// benchmarkTags links tracked benchmarks to the guideTopics they illustrate,
+// so an article can embed just its benchmarks from index.json. Benchmarks
+// no article discusses have no entry.
+var benchmarkTags = map[string][]string{
+	// Runtime/GC benchmarks
+	"BenchmarkSmallAllocation":       {"gc", "stack-alloc"},
+	"BenchmarkMapCreation":           {"prealloc"},
+	"BenchmarkSwissMapCreation":      {"prealloc"},
+	"BenchmarkSwissMapLarge":         {"set-membership"},
+	"BenchmarkSwissMapPresized":      {"prealloc"},
+	"BenchmarkMapKey":                {"set-membership"},
+	"BenchmarkLookup":                {"set-membership"},
+	"BenchmarkSmallAllocSpecialized": {"gc"},
+	"BenchmarkAllocScan":             {"gc"},
+	"BenchmarkTinyAlloc":             {"gc", "stack-alloc"},
+	"BenchmarkValueVsPointer":        {"stack-alloc"},
+	"BenchmarkClosure":               {"stack-alloc"},
+	"BenchmarkSliceConcat":           {"prealloc"},
+	"BenchmarkSyncMap":               {"atomics"},
+	"BenchmarkGCThroughput":          {"gc"},
+	"BenchmarkGCLatency":             {"gc"},
+	"BenchmarkGCLatencyP99":          {"gc"},
+	"BenchmarkSmallObjectScanning":   {"gc"},
+	"BenchmarkMediumObjectScanning":  {"gc"},
+	"BenchmarkLargeObjectScanning":   {"gc"},
+	"BenchmarkAtomicIncrement":       {"atomics"},
+	"BenchmarkMutexContention":       {"atomics"},
+	"BenchmarkChannelThroughput":     {"worker-pool"},
+	"BenchmarkTimeout":               {"context"},
+	"BenchmarkFanOut":                {"worker-pool"},
+	"BenchmarkGCMixedWorkload":       {"gc"},
+	"BenchmarkGCSmallObjects":        {"gc"},
+	"BenchmarkGoroutineCreate":       {"worker-pool"},
+	"BenchmarkStackGrowth":           {"stack-alloc"},
+	"BenchmarkStartup":               {"lazy-init"},
+
+	// Standard library benchmarks
+	"BenchmarkJSONDecodeStream": {"buffered-io"},
+	"BenchmarkIOReadAll":        {"buffered-io", "prealloc"},
+	"BenchmarkAESGCM":           {"tls"},
+	"BenchmarkX509Verify":       {"tls"},
+	"BenchmarkBufferedIO":       {"buffered-io"},
+	"BenchmarkStringsJoin":      {"prealloc"},
+	"BenchmarkStringConcat":     {"prealloc"},
+	"BenchmarkRandomRead":       {"batching"},
+	"BenchmarkArchive":          {"buffered-io"},
+	"BenchmarkIDGen":            {"pooling"},
+	"BenchmarkParseIntBytes":    {"zero-copy"},
+	"BenchmarkStructCopy":       {"immutable-data"},
+	"BenchmarkSQLPoolAcquire":   {"pooling"},
+	"BenchmarkSQLScan":          {"zero-copy"},
+
+	// Networking benchmarks
+	"BenchmarkTCPConnect":       {"connections"},
+	"BenchmarkTCPKeepAlive":     {"connections"},
+	"BenchmarkTCPThroughput":    {"buffered-io"},
+	"BenchmarkTLSHandshake":     {"tls"},
+	"BenchmarkTLSResume":        {"tls"},
+	"BenchmarkTLSThroughput":    {"tls"},
+	"BenchmarkHTTP2":            {"http2"},
+	"BenchmarkHTTPRequest":      {"connections"},
+	"BenchmarkConnectionPool":   {"connections", "pooling"},
+	"BenchmarkHTTPStreaming":    {"buffered-io"},
+	"BenchmarkHTTPService":      {"connections"},
+	"BenchmarkIdleConnScavenge": {"connections"},
+}
+
+// getBenchmarkTags returns the guide topics of a benchmark, looked up like
+// its description
+func getBenchmarkTags(name string) []string {
+	if tags, ok := benchmarkTags[benchmarkBaseName(name)]; ok {
+		return tags
+	}
+	return benchmarkTags[name]
+}
+
+// benchmarkCategories lists every category getBenchmarkCategory returns, in
+// display order
+var benchmarkCategories = []string{"runtime", "stdlib", "networking", "alternatives", "uncategorized"}
+
+// getBenchmarkCategory maps benchmark names to their category
+func getBenchmarkCategory(name string) string {
+	baseName := benchmarkBaseName(name)
+
+	// Runtime/GC benchmarks
+	runtimeBenchmarks := map[string]bool{
+		"BenchmarkSmallAllocation":       true,
+		"BenchmarkMapCreation":           true,
+		"BenchmarkSwissMapCreation":      true,
+		"BenchmarkSwissMapLarge":         true,
+		"BenchmarkSwissMapPresized":      true,
+		"BenchmarkSwissMapIteration":     true,
+		"BenchmarkMapRange":              true,
+		"BenchmarkMapKey":                true,
+		"BenchmarkLookup":                true,
+		"BenchmarkSmallAllocSpecialized": true,
+		"BenchmarkAllocScan":             true,
+		"BenchmarkTinyAlloc":             true,
+		"BenchmarkValueVsPointer":        true,
+		"BenchmarkClosure":               true,
+		"BenchmarkSliceConcat":           true,
+		"BenchmarkSyncMap":               true,
+		"BenchmarkGCThroughput":          true,
+		"BenchmarkGCLatency":             true,
+		"BenchmarkGCLatencyP99":          true,
+		"BenchmarkGCSmallObjects":        true,
+		"BenchmarkGCMixedWorkload":       true,
+		"BenchmarkSmallObjectScanning":   true,
+		"BenchmarkMediumObjectScanning":  true,
+		"BenchmarkLargeObjectScanning":   true,
+		"BenchmarkAtomicIncrement":       true,
+		"BenchmarkMutexContention":       true,
+		"BenchmarkChannelThroughput":     true,
+		"BenchmarkTimeout":               true,
+		"BenchmarkFanOut":                true,
+		"BenchmarkStackGrowth":           true,
+		"BenchmarkGoroutineCreate":       true,
+		"BenchmarkSyscall":               true,
+		"BenchmarkStartup":               true,
+		// Legacy benchmarks (backwards compatibility)
+		"BenchmarkLargeAllocation": true,
+		"BenchmarkMapAllocation":   true,
+		"BenchmarkSliceAppend":     true,
+		"BenchmarkGCPressure":      true,
+	}
+
+	// Standard library benchmarks
+	stdlibBenchmarks := map[string]bool{
+		"BenchmarkJSONEncode":       true,
+		"BenchmarkJSONDecode":       true,
+		"BenchmarkJSONDecodeStream": true,
+		"BenchmarkIOReadAll":        true,
+		"BenchmarkAESCTR":           true,
+		"BenchmarkAESGCM":           true,
+		"BenchmarkSHA":              true,
+		"BenchmarkRSAKeyGen":        true,
+		"BenchmarkX509Verify":       true,
+		"BenchmarkRegexp":           true,
+		"BenchmarkBufferedIO":       true,
+		"BenchmarkCRC32":            true,
+		"BenchmarkFNVHash":          true,
+		"BenchmarkBinaryEncode":     true,
+		"BenchmarkBase64":           true,
+		"BenchmarkHex":              true,
+		"BenchmarkStringsJoin":      true,
+		"BenchmarkStringConcat":     true,
+		"BenchmarkFileWatch":        true,
+		"BenchmarkRandomRead":       true,
+		"BenchmarkDirWalk":          true,
+		"BenchmarkArchive":          true,
+		"BenchmarkBytesSearch":      true,
+		"BenchmarkIDGen":            true,
+		"BenchmarkParseIntBytes":    true,
+		"BenchmarkStructCopy":       true,
+		"BenchmarkSQLPrepared":      true,
+		"BenchmarkSQLPoolAcquire":   true,
+		"BenchmarkSQLScan":          true,
+		// Legacy names for backwards compatibility
+		"BenchmarkReadAll":          true,
+		"BenchmarkReadAllLarge":     true,
+		"BenchmarkAESCTREncrypt":    true,
+		"BenchmarkSHA1Hash":         true,
+		"BenchmarkSHA3Hash":         true,
+		"BenchmarkRSAKeyGeneration": true,
+		"BenchmarkRegexpMatch":      true,
+		"BenchmarkRegexpCompile":    true,
+	}
+
+	// Networking benchmarks
+	networkingBenchmarks := map[string]bool{
+		"BenchmarkTCPConnect":       true, // TCP connection benchmarks
+		"BenchmarkTCPKeepAlive":     true, // TCP keep-alive benchmarks
+		"BenchmarkTCPThroughput":    true, // TCP throughput benchmarks
+		"BenchmarkTLSHandshake":     true, // TLS handshake benchmarks
+		"BenchmarkTLSResume":        true, // TLS session resumption
+		"BenchmarkTLSThroughput":    true, // TLS throughput benchmarks
+		"BenchmarkHTTP2":            true, // HTTP/2 benchmarks
+		"BenchmarkHTTPRequest":      true, // HTTP request benchmarks
+		"BenchmarkConnectionPool":   true, // Connection pool benchmarks
+		"BenchmarkHTTPStreaming":    true, // HTTP body streaming benchmarks
+		"BenchmarkHTTPMiddleware":   true, // Middleware chain overhead
+		"BenchmarkHTTPService":      true, // JSON service macro benchmark
+		"BenchmarkIdleConnScavenge": true, // Idle connection expiry benchmarks
+	}
+
+	// Stdlib vs third-party comparisons
+	alternativesBenchmarks := map[string]bool{
+		"BenchmarkAltJSONDecode": true,
+		"BenchmarkAltHTTPRouter": true,
+		"BenchmarkAltGzip":       true,
+	}
+
+	// Try base name first
+	if runtimeBenchmarks[baseName] {
+		return "runtime"
+	}
+	if stdlibBenchmarks[baseName] {
+		return "stdlib"
+	}
+	if networkingBenchmarks[baseName] {
+		return "networking"
+	}
+	if alternativesBenchmarks[baseName] {
+		return "alternatives"
+	}
+
+	// Fall back to full name for backwards compatibility
+	if runtimeBenchmarks[name] {
+		return "runtime"
+	}
+	if stdlibBenchmarks[name] {
+		return "stdlib"
+	}
+	if networkingBenchmarks[name] {
+		return "networking"
+	}
+
+	// Default to uncategorized for backwards compatibility
+	return "uncategorized"
+}
+
+// getBenchmarkSourceFile maps benchmark names to their source file paths
+func getBenchmarkSourceFile(name string) string {
+	// Extract base benchmark name (remove sub-benchmark path and CPU suffix)
+	baseName := name
+	if idx := strings.Index(name, "/"); idx != -1 {
+		baseName = name[:idx]
+	}
+	if idx := strings.LastIndex(baseName, "-"); idx != -1 {
+		if idx+1 < len(baseName) {
+			isNumeric := true
+			for i := idx + 1; i < len(baseName); i++ {
+				if baseName[i] < '0' || baseName[i] > '9' {
+					isNumeric = false
+					break
+				}
+			}
+			if isNumeric {
+				baseName = baseName[:idx]
+			}
+		}
+	}
+
+	// Stdlib vs third-party comparisons (separate module)
+	if strings.HasPrefix(baseName, "BenchmarkAltJSON") {
+		return "perf-tracking/alternatives/json_test.go"
+	}
+	if strings.HasPrefix(baseName, "BenchmarkAltHTTP") {
+		return "perf-tracking/alternatives/http_test.go"
+	}
+	if strings.HasPrefix(baseName, "BenchmarkAltGzip") {
+		return "perf-tracking/alternatives/compress_test.go"
+	}
+
+	// Syscall floor benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkSyscall") {
+		return "perf-tracking/benchmarks/runtime/syscall_test.go"
+	}
+
+	// Value vs pointer copy benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkValueVsPointer") {
+		return "perf-tracking/benchmarks/runtime/copy_test.go"
+	}
+
+	// Closure capture benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkClosure") {
+		return "perf-tracking/benchmarks/runtime/closure_test.go"
+	}
+
+	// Map iteration under mutation and map key encoding
+	if strings.HasPrefix(baseName, "BenchmarkMapRange") ||
+		strings.HasPrefix(baseName, "BenchmarkMapKey") {
+		return "perf-tracking/benchmarks/runtime/sync_test.go"
+	}
+
+	// Lookup strategy crossover benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkLookup") {
+		return "perf-tracking/benchmarks/runtime/lookup_test.go"
+	}
+
+	// Timeout idiom benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkTimeout") {
+		return "perf-tracking/benchmarks/runtime/timeout_test.go"
+	}
+
+	// Worker pool scheduling benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkFanOut") {
+		return "perf-tracking/benchmarks/runtime/workers_test.go"
+	}
+
+	// Slice assembly benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkSliceConcat") {
+		return "perf-tracking/benchmarks/runtime/slice_test.go"
+	}
+
+	// Process startup benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkStartup") {
+		return "perf-tracking/benchmarks/runtime/startup_test.go"
+	}
+
+	// Allocation benchmarks of the core package, which the collector does
+	// not run
+	if strings.HasPrefix(baseName, "BenchmarkSmallAllocation") ||
+		strings.HasPrefix(baseName, "BenchmarkLargeAllocation") ||
+		strings.HasPrefix(baseName, "BenchmarkMapAllocation") ||
+		strings.HasPrefix(baseName, "BenchmarkSliceAppend") ||
+		strings.HasPrefix(baseName, "BenchmarkGCPressure") {
+		return "perf-tracking/benchmarks/core/allocation_test.go"
+	}
+
+	// Allocator, stack and goroutine benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkSmallAlloc") ||
+		strings.HasPrefix(baseName, "BenchmarkAllocScan") ||
+		strings.HasPrefix(baseName, "BenchmarkTinyAlloc") ||
+		strings.HasPrefix(baseName, "BenchmarkGoroutine") ||
+		strings.HasPrefix(baseName, "BenchmarkStack") {
+		return "perf-tracking/benchmarks/runtime/memory_test.go"
+	}
+
+	// Map and synchronization benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkSwiss") ||
+		strings.HasPrefix(baseName, "BenchmarkSync") ||
+		strings.HasPrefix(baseName, "BenchmarkMutex") ||
+		strings.HasPrefix(baseName, "BenchmarkAtomic") ||
+		strings.HasPrefix(baseName, "BenchmarkChannel") {
+		return "perf-tracking/benchmarks/runtime/sync_test.go"
+	}
+
+	// Runtime/GC benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkGC") ||
+		strings.HasPrefix(baseName, "BenchmarkMap") ||
+		strings.HasPrefix(baseName, "BenchmarkSmallObject") ||
+		strings.HasPrefix(baseName, "BenchmarkMediumObject") ||
+		strings.HasPrefix(baseName, "BenchmarkLargeObject") {
+		return "perf-tracking/benchmarks/runtime/gc_test.go"
+	}
+
+	// Crypto benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkAES") ||
+		strings.HasPrefix(baseName, "BenchmarkSHA") ||
+		strings.HasPrefix(baseName, "BenchmarkRSA") {
+		return "perf-tracking/benchmarks/stdlib/crypto_test.go"
+	}
+
+	// Encoding benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkJSON") ||
+		strings.HasPrefix(baseName, "BenchmarkBinary") ||
+		strings.HasPrefix(baseName, "BenchmarkBase64") ||
+		strings.HasPrefix(baseName, "BenchmarkHex") {
+		return "perf-tracking/benchmarks/stdlib/encoding_test.go"
+	}
+
+	// Hashing benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkCRC") ||
+		strings.HasPrefix(baseName, "BenchmarkFNV") {
+		return "perf-tracking/benchmarks/stdlib/hash_test.go"
+	}
+
+	// I/O benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkIO") ||
+		strings.HasPrefix(baseName, "BenchmarkReadAll") ||
+		strings.HasPrefix(baseName, "BenchmarkBuffered") {
+		return "perf-tracking/benchmarks/stdlib/io_test.go"
+	}
+
+	// Text processing and string building benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkRegexp") ||
+		strings.HasPrefix(baseName, "BenchmarkStrings") ||
+		strings.HasPrefix(baseName, "BenchmarkStringConcat") {
+		return "perf-tracking/benchmarks/stdlib/text_test.go"
+	}
+
+	// Certificate verification benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkX509") {
+		return "perf-tracking/benchmarks/stdlib/x509_test.go"
+	}
+
+	// File watching benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkFileWatch") {
+		return "perf-tracking/benchmarks/stdlib/fswatch_test.go"
+	}
+
+	// Random file read benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkRandomRead") {
+		return "perf-tracking/benchmarks/stdlib/randread_test.go"
+	}
+
+	// Directory traversal benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkDirWalk") {
+		return "perf-tracking/benchmarks/stdlib/walk_test.go"
+	}
+
+	// Archive benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkArchive") {
+		return "perf-tracking/benchmarks/stdlib/archive_test.go"
+	}
+
+	// Byte search benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkBytesSearch") {
+		return "perf-tracking/benchmarks/stdlib/bytesearch_test.go"
+	}
+
+	// ID generation benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkIDGen") {
+		return "perf-tracking/benchmarks/stdlib/idgen_test.go"
+	}
+
+	// Integer parsing benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkParseIntBytes") {
+		return "perf-tracking/benchmarks/stdlib/parseint_test.go"
+	}
+
+	// Struct copying benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkStructCopy") {
+		return "perf-tracking/benchmarks/stdlib/structcopy_test.go"
+	}
+
+	// database/sql benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkSQL") {
+		return "perf-tracking/benchmarks/database/sql_test.go"
+	}
+
+	// HTTP body streaming benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkHTTPStreaming") {
+		return "perf-tracking/benchmarks/networking/streaming_test.go"
+	}
+
+	// HTTP middleware chain benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkHTTPMiddleware") {
+		return "perf-tracking/benchmarks/networking/middleware_test.go"
+	}
+
+	// JSON service macro benchmark
+	if strings.HasPrefix(baseName, "BenchmarkHTTPService") {
+		return "perf-tracking/benchmarks/networking/service_test.go"
+	}
+
+	// Idle connection scavenging benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkIdleConn") {
+		return "perf-tracking/benchmarks/networking/idle_test.go"
+	}
+
+	// Networking benchmarks
+	if strings.HasPrefix(baseName, "BenchmarkTCP") {
+		return "perf-tracking/benchmarks/networking/tcp_test.go"
+	}
+	if strings.HasPrefix(baseName, "BenchmarkTLS") {
+		return "perf-tracking/benchmarks/networking/tls_test.go"
+	}
+	if strings.HasPrefix(baseName, "BenchmarkHTTP") {
+		return "perf-tracking/benchmarks/networking/http_test.go"
+	}
+	if strings.HasPrefix(baseName, "BenchmarkConnection") {
+		return "perf-tracking/benchmarks/networking/pool_test.go"
+	}
+
+	// Legacy/unknown
+	return "perf-tracking/benchmarks/core/allocation_test.go"
+}
+
+// ExportOptions controls how parsed results are turned into published JSON
+type ExportOptions struct {
+	CPUFallback string // used when the benchmark file lacks a cpu: line
+	Anonymize   bool   // scrub hostnames, usernames and serial-like ids from metadata
+	Strict      bool   // fail instead of warn when plausibility checks flag a benchmark
+
+	// OnDuplicate decides what rebuildIndex does when several JSON files
+	// claim the same version
+	OnDuplicate DuplicatePolicy
+
+	// Repository sets the source links written to index.json
+	Repository RepoOptions
+
+	// KeepRaw archives the newest KeepRaw raw result files per version next
+	// to the exported JSON (see archiveRawResults); 0 disables archiving
+	KeepRaw int
+
+	// Filter limits the export to the benchmarks it matches
+	Filter BenchmarkFilter
+}
+
+// applyExportOptions post-processes parsed version data before it is written
+func applyExportOptions(versionData *VersionData, opts ExportOptions) {
+	if versionData.Metadata.System.CPU == "" && opts.CPUFallback != "" {
+		versionData.Metadata.System.CPU = opts.CPUFallback
+	}
+	if opts.Anonymize {
+		anonymizeMetadata(&versionData.Metadata)
+	}
+	if opts.Filter.active() {
+		filterBenchmarks(versionData.Benchmarks, opts.Filter)
+		versionData.Crossovers = findCrossovers(versionData.Benchmarks)
+	}
+}
+
+// exportVersion exports a single version's benchmarks to JSON
+func exportVersion(inputFile, version, outputFile string, opts ExportOptions) error {
+	fmt.Printf("Exporting Go %s...\n", version)
+	fmt.Printf("  Input:  %s\n", inputFile)
+
+	versionData, err := parseBenchmarkFile(inputFile, version)
+	if err != nil {
+		return fmt.Errorf("failed to parse benchmark file: %w", err)
+	}
+	applyExportOptions(versionData, opts)
+	if err := reportPlausibility(versionData, opts.Strict); err != nil {
+		return err
+	}
+
+	// Write JSON
+	jsonData, err := json.MarshalIndent(versionData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	fmt.Printf("  Output: %s\n", outputFile)
+	fmt.Printf("  ✓ Exported %d benchmarks\n\n", len(versionData.Benchmarks))
+
+	return nil
+}
+
+// IndexData represents the index.json file
+type IndexData struct {
+	Versions    []VersionInfo   `json:"versions"`
+	Benchmarks  []BenchmarkInfo `json:"benchmarks"`
+	Repository  RepositoryInfo  `json:"repository"`
+	LastUpdated string          `json:"last_updated"`
+}
+
+type RepositoryInfo struct {
+	URL        string `json:"url"`
+	SourcePath string `json:"source_path"`
+}
+
+type VersionInfo struct {
+	Version     string `json:"version"`
+	File        string `json:"file"`
+	CollectedAt string `json:"collected_at"`
+	Provisional bool   `json:"provisional,omitempty"` // pre-release data, superseded by the final release
+}
+
+type BenchmarkInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	SourceFile  string   `json:"source_file"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags,omitempty"` // guide topics, e.g. "gc" or "zero-copy"
+	Reliability string   `json:"reliability"`    // "reliable", "noisy", or "unstable"
+	MaxCV       float64  `json:"max_cv"`         // maximum coefficient of variation observed across all exported versions
+
+	// Sub-benchmark structure, so variants of one benchmark can be grouped
+	Group      string     `json:"group"`                // top-level benchmark, e.g. "BenchmarkAESCTR"
+	Variant    []string   `json:"variant,omitempty"`    // this entry's sub-benchmark path, e.g. ["Size1KB"]
+	Dimensions [][]string `json:"dimensions,omitempty"` // known values per sub-benchmark level across the group
+
+	// Display unit for ns_per_op, e.g. "µs"; stored values stay in ns
+	DisplayUnit string `json:"display_unit"`
+}
+
+// PlatformsData represents the top-level platforms.json file
+type PlatformsData struct {
+	Platforms   []PlatformInfo `json:"platforms"`
+	LastUpdated string         `json:"last_updated"`
+}
+
+// PlatformInfo describes a single platform entry
+type PlatformInfo struct {
+	Name    string `json:"name"`
+	Display string `json:"display"`
+	Index   string `json:"index"`
+}
+
+// platformDisplayName returns a human-readable name for a platform string.
+func platformDisplayName(platform string) string {
+	displayNames := map[string]string{
+		"darwin":  "macOS",
+		"linux":   "Linux",
+		"windows": "Windows",
+		"freebsd": "FreeBSD",
+	}
+
+	parts := strings.SplitN(platform, "-", 2)
+	if len(parts) != 2 {
+		return platform
+	}
+
+	osName := parts[0]
+	arch := parts[1]
+	if display, ok := displayNames[osName]; ok {
+		osName = display
+	}
+	return osName + " " + arch
+}
+
+// getReliability classifies a benchmark based on its max coefficient of variation
+// observed across all exported versions.
+//
+//	reliable: CV < 5%   — trustworthy for comparison
+//	noisy:    5% ≤ CV < 15% — environment-sensitive
+//	unstable: CV ≥ 15%  — high variance, not suitable for direct comparison
+func getReliability(maxCV float64) string {
+	switch {
+	case maxCV >= 0.15:
+		return "unstable"
+	case maxCV >= 0.05:
+		return "noisy"
+	default:
+		return "reliable"
+	}
+}
+
+// mainResultFiles returns the main benchmark result files in versionDir,
+// newest first, excluding retry, rerun, failure-list, gctrace and backup files.
+func mainResultFiles(versionDir string) []string {
+	files, err := filepath.Glob(filepath.Join(versionDir, "*.txt"))
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+
+	var mainFiles []string
+	for _, f := range files {
+		base := filepath.Base(f)
+		if !strings.Contains(base, "_retry") &&
+			!strings.Contains(base, "_rerun") &&
+			!strings.Contains(base, "_failed_benchmarks") &&
+			!strings.Contains(base, "_failed_packages") &&
+			!strings.HasSuffix(base, "_gctrace.txt") &&
+			!strings.HasSuffix(base, ".backup") {
+			mainFiles = append(mainFiles, f)
+		}
+	}
+
+	// Sort by modification time, newest first.
+	// Pre-cache mtimes so the comparator never calls os.Stat on a file
+	// that may have disappeared, which would yield nil and panic.
+	mainMtimes := make(map[string]time.Time, len(mainFiles))
+	for _, f := range mainFiles {
+		if fi, statErr := os.Stat(f); statErr == nil {
+			mainMtimes[f] = fi.ModTime()
+		}
+		// Zero time is a safe fallback; missing files sort last.
+	}
+	sort.Slice(mainFiles, func(i, j int) bool {
+		return mainMtimes[mainFiles[i]].After(mainMtimes[mainFiles[j]])
+	})
+	return mainFiles
+}
+
+// exportAll exports all versions found in the results directory, then rebuilds
+// the index from all go*.json files present in the output platform directory.
+// This makes every export additive: pre-existing version files are never dropped.
+// defaultPlatform is used when the platform cannot be auto-detected from the
+// benchmark files (e.g. files lack OS/arch metadata).
+// cpuOverride is used as a fallback when benchmark files lack a cpu: line.
+func exportAll(resultsDir, outputDir, defaultPlatform string, opts ExportOptions) error {
+	fmt.Println("=== Exporting All Versions ===")
+
+	entries, err := os.ReadDir(resultsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read results directory: %w", err)
+	}
+
+	var exportedVersions, failedVersions []string
+	var platform string
+
+	// Phase 1: export each go*/ dir found in resultsDir.
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+			continue
+		}
+
+		version := strings.TrimPrefix(entry.Name(), "go")
+		versionDir := filepath.Join(resultsDir, entry.Name())
+
+		mainFiles := mainResultFiles(versionDir)
+		if len(mainFiles) == 0 {
+			continue
+		}
+
+		latestFile := mainFiles[0]
+
+		// Runs with contention profiling on are slower on contended paths;
+		// only those profiled like the exported one are pooled for its CV
+		profiled := contentionProfiled(latestFile)
+		var skipped int
+		mainFiles = slices.DeleteFunc(mainFiles, func(f string) bool {
+			if contentionProfiled(f) != profiled {
+				skipped++
+				return true
+			}
+			return false
+		})
+		if skipped > 0 {
+			fmt.Printf("  Warning: go%s: %d run(s) with contention profiling %s left out of the inter-run CV\n",
+				version, skipped, onOff(!profiled))
+		}
+
+		// Compute inter-run CV across all main files for this version.
+		// This catches benchmarks that appear stable within a single run
+		// (low within-run CV) but differ significantly between runs.
+		// The resulting CV is written into the exported JSON so that
+		// rebuildIndex can use it when computing per-benchmark reliability.
+		interRunMaxCV := map[string]float64{}
+		if len(mainFiles) > 1 {
+			interRunMeans := map[string][]float64{}
+			for _, f := range mainFiles {
+				fd, err := parseBenchmarkFile(f, version)
+				if err != nil {
+					continue
+				}
+				for name, bench := range fd.Benchmarks {
+					interRunMeans[name] = append(interRunMeans[name], bench.NsPerOp)
+				}
+			}
+			for name, means := range interRunMeans {
+				if len(means) < 2 {
+					continue
+				}
+				interRunMaxCV[name] = benchstats.SampleStdDev(means) / benchstats.Mean(means)
+			}
+		}
+
+		// Detect platform from the first available version file.
+		if platform == "" {
+			probeData, probeErr := parseBenchmarkFile(latestFile, version)
+			if probeErr == nil && probeData.Metadata.System.OS != "" && probeData.Metadata.System.Arch != "" {
+				platform = probeData.Metadata.System.OS + "-" + probeData.Metadata.System.Arch
+			}
+		}
+
+		platformDir := filepath.Join(outputDir, platform)
+		outputFile := filepath.Join(platformDir, fmt.Sprintf("go%s.json", version))
+
+		if err := exportVersion(latestFile, version, outputFile, opts); err != nil {
+			fmt.Printf("  Error: %v\n", err)
+			failedVersions = append(failedVersions, version)
+			continue
+		}
+
+		// Promote inter-run CV into the exported JSON where it exceeds
+		// the within-run CV, so rebuildIndex sees the full variance signal.
+		if len(interRunMaxCV) > 0 {
+			if err := applyInterRunCV(outputFile, interRunMaxCV); err != nil {
+				fmt.Printf("  Warning: could not apply inter-run CV: %v\n", err)
+			}
+		}
+
+		if opts.KeepRaw > 0 {
+			rawFiles, err := archiveRawResults(mainFiles, platformDir, version, opts.KeepRaw)
+			if err == nil {
+				err = updateVersionFile(outputFile, func(vd *VersionData) bool {
+					vd.RawFiles = rawFiles
+					return true
+				})
+			}
+			if err != nil {
+				fmt.Printf("  Error: failed to archive raw results: %v\n", err)
+				failedVersions = append(failedVersions, version)
+				continue
+			}
+			fmt.Printf("  Archived %d raw result file(s)\n", len(rawFiles))
+		}
+
+		exportedVersions = append(exportedVersions, version)
+	}
+
+	if opts.Strict && len(failedVersions) > 0 {
+		return fmt.Errorf("export failed for %d version(s) in strict mode: %s", len(failedVersions), strings.Join(failedVersions, ", "))
+	}
+
+	if platform == "" {
+		platform = defaultPlatform
+		fmt.Printf("  Platform not detected from files; using default: %s\n", platform)
+	}
+
+	// Phase 2: rebuild index from ALL go*.json files in the platform output
+	// directory (both newly written and pre-existing), so no version is lost.
+	platformDir := filepath.Join(outputDir, platform)
+	if err := rebuildIndex(platformDir, outputDir, platform, opts); err != nil {
+		return fmt.Errorf("failed to rebuild index: %w", err)
+	}
+
+	// Read back the rebuilt index for accurate summary counts.
+	var indexData IndexData
+	if data, err := os.ReadFile(filepath.Join(platformDir, "index.json")); err == nil {
+		if unmarshalErr := json.Unmarshal(data, &indexData); unmarshalErr != nil {
+			fmt.Printf("  Warning: could not parse rebuilt index for summary: %v\n", unmarshalErr)
+		}
+	}
+
+	exportedStrs := make([]string, len(exportedVersions))
+	for i, v := range exportedVersions {
+		exportedStrs[i] = "go" + v
+	}
+	totalStrs := make([]string, len(indexData.Versions))
+	for i, v := range indexData.Versions {
+		totalStrs[i] = "go" + v.Version
+	}
+
+	fmt.Println("=== Export Summary ===")
+	fmt.Printf("Platform:          %s\n", platform)
+	fmt.Printf("Exported this run: %d (%s)\n", len(exportedVersions), strings.Join(exportedStrs, ", "))
+	fmt.Printf("Total in index:    %d (%s)\n", len(indexData.Versions), strings.Join(totalStrs, ", "))
+	fmt.Printf("Benchmarks:        %d\n", len(indexData.Benchmarks))
+	fmt.Printf("✓ Export complete!\n")
+
+	return nil
+}
+
+// applyInterRunCV updates NsPerOpVariance in the exported JSON for any benchmark
+// where the inter-run CV exceeds the within-run CV already stored.
+func applyInterRunCV(outputFile string, interRunMaxCV map[string]float64) error {
+	return updateVersionFile(outputFile, func(vd *VersionData) bool {
+		updated := false
+		for name, irCV := range interRunMaxCV {
+			if b, ok := vd.Benchmarks[name]; ok && irCV > b.NsPerOpVariance {
+				b.NsPerOpVariance = irCV
+				vd.Benchmarks[name] = b
+				updated = true
+			}
+		}
+		return updated
+	})
+}
+
+// updateVersionFile reads an exported version JSON, applies update, and
+// writes it back if update reports a change.
+func updateVersionFile(outputFile string, update func(*VersionData) bool) error {
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", outputFile, err)
+	}
+
+	var vd VersionData
+	if err := json.Unmarshal(data, &vd); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", outputFile, err)
+	}
+
+	if !update(&vd) {
+		return nil
+	}
+
+	jsonData, err := json.MarshalIndent(vd, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", outputFile, err)
+	}
+	return os.WriteFile(outputFile, jsonData, 0644)
+}
+
+// loadedVersionFile is an exported version JSON read back by rebuildIndex
+type loadedVersionFile struct {
+	path string
+	data *VersionData
+}
+
+// rebuildIndex scans all go<version>.json files in platformDir, computes
+// benchmarkMaxCV across all versions, and writes a complete index.json.
+// Files claiming the same version are resolved according to opts.OnDuplicate.
+// It also keeps platforms.json current via updatePlatformsJSON.
+func rebuildIndex(platformDir, outputDir, platform string, opts ExportOptions) error {
+	repository, err := opts.Repository.repositoryInfo()
+	if err != nil {
+		return err
+	}
+
+	jsonFiles, err := filepath.Glob(filepath.Join(platformDir, "go*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to glob json files: %w", err)
+	}
+
+	// Keep only files whose name starts with go<digit> (e.g. go1.24.json).
+	var validFiles []string
+	for _, f := range jsonFiles {
+		base := filepath.Base(f)
+		if len(base) > 2 && base[2] >= '0' && base[2] <= '9' {
+			validFiles = append(validFiles, f)
+		}
+	}
+
+	// Pre-cache mtimes so the comparator never calls os.Stat on a file that
+	// may have disappeared between glob and sort, which would yield nil and panic.
+	fileMtimes := make(map[string]time.Time, len(validFiles))
+	for _, f := range validFiles {
+		if fi, statErr := os.Stat(f); statErr == nil {
+			fileMtimes[f] = fi.ModTime()
+		}
+		// Zero time is a safe fallback; missing files sort last on tie-break.
+	}
+
+	// Sort ascending by version number, newest mtime first within the same version.
+	// This ensures that when two files share the same JSON version string (e.g.
+	// go1.26.json and go1.26.0.json both contain "version":"1.26"), the most
+	// recently written file leads its group and policy decides what happens
+	// to the rest.
+	sort.Slice(validFiles, func(i, j int) bool {
+		vi := versionFromJSONFilename(filepath.Base(validFiles[i]))
+		vj := versionFromJSONFilename(filepath.Base(validFiles[j]))
+		cmp := compareVersionStrings(vi, vj)
+		if cmp != 0 {
+			return cmp < 0
+		}
+		// Tie-break: prefer most recently modified file.
+		return fileMtimes[validFiles[i]].After(fileMtimes[validFiles[j]])
+	})
+
+	// Load every file, grouping by the version stored inside the JSON.
+	// validFiles is sorted, so each group is ordered newest first.
+	var versionOrder []string
+	byVersion := make(map[string][]loadedVersionFile)
+	for _, f := range validFiles {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			fmt.Printf("  Warning: skipping %s: %v\n", filepath.Base(f), err)
+			continue
+		}
+		var vd VersionData
+		if err := json.Unmarshal(data, &vd); err != nil {
+			fmt.Printf("  Warning: skipping %s (parse error): %v\n", filepath.Base(f), err)
+			continue
+		}
+		if _, seen := byVersion[vd.Version]; !seen {
+			versionOrder = append(versionOrder, vd.Version)
+		}
+		byVersion[vd.Version] = append(byVersion[vd.Version], loadedVersionFile{path: f, data: &vd})
+	}
+
+	var versions []VersionInfo
+	benchmarkNames := make(map[string]bool)
+	benchmarkMaxCV := map[string]float64{}
+	benchmarkMinNs := map[string]float64{}
+
+	for _, version := range versionOrder {
+		group := byVersion[version]
+		winner := group[0]
+
+		if len(group) > 1 {
+			names := make([]string, len(group))
+			for i, lf := range group {
+				names[i] = filepath.Base(lf.path)
+			}
+
+			switch opts.OnDuplicate {
+			case DuplicateError:
+				return fmt.Errorf("version %s is claimed by %d files: %s", version, len(group), strings.Join(names, ", "))
+			case DuplicateMerge:
+				datas := make([]*VersionData, len(group))
+				for i, lf := range group {
+					datas[i] = lf.data
+				}
+				winner.data = mergeVersionData(datas)
+				if err := writeMergedVersion(winner.path, winner.data, group[1:]); err != nil {
+					return err
+				}
+				fmt.Printf("  Duplicate go%s: merged %s into %s\n", version, strings.Join(names[1:], ", "), names[0])
+			default:
+				fmt.Printf("  Duplicate go%s: using %s (newest), ignoring %s\n", version, names[0], strings.Join(names[1:], ", "))
+			}
+		}
+
+		vd := winner.data
+		versions = append(versions, VersionInfo{
+			Version:     vd.Version,
+			File:        filepath.Base(winner.path),
+			CollectedAt: vd.Metadata.CollectedAt,
+			Provisional: isPreRelease(vd.Version),
+		})
+
+		for name, bench := range vd.Benchmarks {
+			benchmarkNames[name] = true
+			if bench.NsPerOpVariance > benchmarkMaxCV[name] {
+				benchmarkMaxCV[name] = bench.NsPerOpVariance
+			}
+			if minNs, ok := benchmarkMinNs[name]; !ok || bench.NsPerOp < minNs {
+				benchmarkMinNs[name] = bench.NsPerOp
+			}
+		}
+	}
+
+	allNames := make([]string, 0, len(benchmarkNames))
+	for name := range benchmarkNames {
+		allNames = append(allNames, name)
+	}
+	dimensions := variantDimensions(allNames)
+
+	var benchmarks []BenchmarkInfo
+	for name := range benchmarkNames {
+		group, variant := splitBenchmarkName(name)
+		benchmarks = append(benchmarks, BenchmarkInfo{
+			Name:        name,
+			Description: getBenchmarkDescription(name),
+			SourceFile:  getBenchmarkSourceFile(name),
+			Category:    getBenchmarkCategory(name),
+			Tags:        getBenchmarkTags(name),
+			Reliability: getReliability(benchmarkMaxCV[name]),
+			MaxCV:       benchmarkMaxCV[name],
+			Group:       group,
+			Variant:     variant,
+			Dimensions:  dimensions[group],
+			DisplayUnit: units.Time(benchmarkMinNs[name]).Symbol,
+		})
+	}
+	sort.Slice(benchmarks, func(i, j int) bool {
+		return benchmarks[i].Name < benchmarks[j].Name
+	})
+
+	indexData := IndexData{
+		Versions:    versions,
+		Benchmarks:  benchmarks,
+		Repository:  repository,
+		LastUpdated: time.Now().Format(time.RFC3339),
+	}
+
+	indexJSON, err := json.MarshalIndent(indexData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal index JSON: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(platformDir, "index.json"), indexJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+
+	return updatePlatformsJSON(outputDir, platform)
+}
+
+// versionFromJSONFilename extracts the version string from a filename like "go1.24.json".
+func versionFromJSONFilename(filename string) string {
+	s := strings.TrimPrefix(filename, "go")
+	return strings.TrimSuffix(s, ".json")
+}
+
+// goVersion is a parsed Go version such as "1.24.1", "1.26rc1" or "1.21beta2"
+type goVersion struct {
+	parts  []int
+	stage  int // preBeta, preRC or preRelease
+	preNum int // N in betaN/rcN
+}
+
+const (
+	preBeta = iota
+	preRC
+	preRelease
+)
+
+// parseGoVersion splits a version into numeric parts and a pre-release
+// suffix on the last part. Unparseable parts are treated as 0.
+func parseGoVersion(v string) goVersion {
+	gv := goVersion{stage: preRelease}
+	for _, part := range strings.Split(v, ".") {
+		digits := len(part) - len(strings.TrimLeft(part, "0123456789"))
+		n, _ := strconv.Atoi(part[:digits])
+		gv.parts = append(gv.parts, n)
+
+		suffix := part[digits:]
+		for _, pre := range []struct {
+			prefix string
+			stage  int
+		}{{"beta", preBeta}, {"rc", preRC}} {
+			if strings.HasPrefix(suffix, pre.prefix) {
+				gv.stage = pre.stage
+				gv.preNum, _ = strconv.Atoi(strings.TrimPrefix(suffix, pre.prefix))
+			}
+		}
+	}
+	return gv
+}
+
+// isPreRelease reports whether v is a beta or release candidate
+func isPreRelease(v string) bool {
+	return parseGoVersion(v).stage != preRelease
+}
+
+// compareVersionStrings compares two Go version strings (e.g. "1.23", "1.24.1",
+// "1.26rc1"). Returns negative if a < b, 0 if equal, positive if a > b.
+// Pre-releases order before the release they lead up to: 1.26beta1 < 1.26rc1
+// < 1.26 == 1.26.0 < 1.26.1. Missing parts are treated as 0.
+func compareVersionStrings(a, b string) int {
+	va, vb := parseGoVersion(a), parseGoVersion(b)
+	maxLen := max(len(va.parts), len(vb.parts))
+	for i := 0; i < maxLen; i++ {
+		var pa, pb int
+		if i < len(va.parts) {
+			pa = va.parts[i]
+		}
+		if i < len(vb.parts) {
+			pb = vb.parts[i]
+		}
+		if pa != pb {
+			if pa < pb {
+				return -1
+			}
+			return 1
+		}
+	}
+	if va.stage != vb.stage {
+		if va.stage < vb.stage {
+			return -1
+		}
+		return 1
+	}
+	if va.preNum != vb.preNum {
+		if va.preNum < vb.preNum {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// updatePlatformsJSON reads an existing platforms.json (if present), merges/updates
+// the current platform entry, and writes back the updated file.
+func updatePlatformsJSON(outputDir, platform string) error {
+	platformsFile := filepath.Join(outputDir, "platforms.json")
+
+	var platformsData PlatformsData
+
+	// Read existing platforms.json if present
+	if data, err := os.ReadFile(platformsFile); err == nil {
+		_ = json.Unmarshal(data, &platformsData)
+	}
+
+	// Update or add the current platform entry
+	newEntry := PlatformInfo{
+		Name:    platform,
+		Display: platformDisplayName(platform),
+		Index:   platform + "/index.json",
+	}
+
+	found := false
+	for i, p := range platformsData.Platforms {
+		if p.Name == platform {
+			platformsData.Platforms[i] = newEntry
+			found = true
+			break
+		}
+	}
+	if !found {
+		platformsData.Platforms = append(platformsData.Platforms, newEntry)
+	}
+
+	// Sort platforms by name for stable output
+	sort.Slice(platformsData.Platforms, func(i, j int) bool {
+		return platformsData.Platforms[i].Name < platformsData.Platforms[j].Name
+	})
+
+	platformsData.LastUpdated = time.Now().Format(time.RFC3339)
+
+	jsonData, err := json.MarshalIndent(platformsData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal platforms JSON: %w", err)
+	}
+
+	if err := os.WriteFile(platformsFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write platforms.json: %w", err)
+	}
+
+	return nil
+}
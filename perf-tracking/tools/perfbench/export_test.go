@@ -2,8 +2,14 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetBenchmarkCategory(t *testing.T) {
@@ -329,6 +335,18 @@ func TestGetBenchmarkCategory(t *testing.T) {
 			wantCategory:  "networking",
 		},
 
+		// Stdlib vs third-party comparisons
+		{
+			name:          "Alternative JSON decoders with variant",
+			benchmarkName: "BenchmarkAltJSONDecode/Jsoniter-8",
+			wantCategory:  "alternatives",
+		},
+		{
+			name:          "Alternative gzip",
+			benchmarkName: "BenchmarkAltGzip/Compress/Klauspost",
+			wantCategory:  "alternatives",
+		},
+
 		// Unknown/uncategorized benchmarks
 		{
 			name:          "Unknown benchmark",
@@ -352,6 +370,64 @@ func TestGetBenchmarkCategory(t *testing.T) {
 	}
 }
 
+// TestGetBenchmarkSourceFile checks that every benchmark of the suites is
+// mapped to the file declaring it, so source links and annotations point
+// at its definition.
+func TestGetBenchmarkSourceFile(t *testing.T) {
+	root := filepath.Join("..", "..")
+	for _, dir := range []string{"benchmarks", "alternatives"} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			want := "perf-tracking/" + filepath.ToSlash(rel)
+			for _, m := range benchmarkFuncPattern.FindAllStringSubmatch(string(src), -1) {
+				if got := getBenchmarkSourceFile(m[1] + "/Sub-8"); got != want {
+					t.Errorf("getBenchmarkSourceFile(%s) = %s, want %s", m[1], got, want)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBenchmarkTags(t *testing.T) {
+	for name, tags := range benchmarkTags {
+		if getBenchmarkDescription(name) == "" {
+			t.Errorf("%s is tagged but not a tracked benchmark", name)
+		}
+		for _, tag := range tags {
+			if _, ok := guideTopics[tag]; !ok {
+				t.Errorf("%s: unknown topic %q", name, tag)
+			}
+		}
+	}
+	for topic, article := range guideTopics {
+		if _, err := os.Stat(filepath.Join("..", "..", "..", "docs", article)); err != nil {
+			t.Errorf("topic %s: %v", topic, err)
+		}
+	}
+
+	// Sub-benchmarks and CPU suffixes share their benchmark's tags
+	if got := getBenchmarkTags("BenchmarkConnectionPool/Reuse-8"); !slices.Equal(got, []string{"connections", "pooling"}) {
+		t.Errorf("getBenchmarkTags(BenchmarkConnectionPool/Reuse-8) = %v", got)
+	}
+	if got := getBenchmarkTags("BenchmarkHTTPMiddleware"); got != nil {
+		t.Errorf("untagged benchmark has tags %v", got)
+	}
+}
+
 func TestGetBenchmarkDescription(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -739,6 +815,15 @@ func TestCompareVersionStrings(t *testing.T) {
 		// Empty strings treated as zero
 		{"", "1.0", -1},
 		{"1.0", "", 1},
+		// Pre-releases: beta < rc < release
+		{"1.26rc1", "1.26", -1},
+		{"1.26", "1.26rc1", 1},
+		{"1.26beta1", "1.26rc1", -1},
+		{"1.26rc1", "1.26rc2", -1},
+		{"1.26rc2", "1.26rc2", 0},
+		{"1.26rc2", "1.26.0", -1},
+		{"1.25.7", "1.26beta1", -1},
+		{"1.26rc1", "1.26.1", -1},
 	}
 
 	for _, tt := range tests {
@@ -755,6 +840,19 @@ func TestCompareVersionStrings(t *testing.T) {
 	}
 }
 
+func TestIsPreRelease(t *testing.T) {
+	for v, want := range map[string]bool{
+		"1.26":      false,
+		"1.26.1":    false,
+		"1.26rc1":   true,
+		"1.21beta2": true,
+	} {
+		if got := isPreRelease(v); got != want {
+			t.Errorf("isPreRelease(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
 func TestVersionFromJSONFilename(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -804,10 +902,12 @@ func TestRebuildIndex(t *testing.T) {
 	writeVersion("go1.24.json", "1.24", map[string]Benchmark{
 		"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: 100, NsPerOpVariance: 0.02},
 		"BenchmarkBar": {Name: "BenchmarkBar", NsPerOp: 200, NsPerOpVariance: 0.12},
+		"BenchmarkGC":  {Name: "BenchmarkGC", NsPerOp: 980_000, NsPerOpVariance: 0.02},
 	})
 	writeVersion("go1.25.json", "1.25", map[string]Benchmark{
 		"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: 95, NsPerOpVariance: 0.03},
 		"BenchmarkBar": {Name: "BenchmarkBar", NsPerOp: 190, NsPerOpVariance: 0.08},
+		"BenchmarkGC":  {Name: "BenchmarkGC", NsPerOp: 2_500_000, NsPerOpVariance: 0.02},
 	})
 
 	// Stale duplicate for 1.25 — should be skipped (older mtime via write order).
@@ -815,7 +915,7 @@ func TestRebuildIndex(t *testing.T) {
 		"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: 90, NsPerOpVariance: 0.01},
 	})
 
-	if err := rebuildIndex(platformDir, tmpDir, "linux-amd64"); err != nil {
+	if err := rebuildIndex(platformDir, tmpDir, "linux-amd64", ExportOptions{OnDuplicate: DuplicateKeepNewest}); err != nil {
 		t.Fatalf("rebuildIndex failed: %v", err)
 	}
 
@@ -841,14 +941,15 @@ func TestRebuildIndex(t *testing.T) {
 
 	// benchmarkMaxCV for BenchmarkBar is max(0.12, 0.08) = 0.12 → noisy.
 	// benchmarkMaxCV for BenchmarkFoo is max(0.02, 0.03) = 0.03 → reliable.
-	reliabilityFor := func(name string) string {
+	infoFor := func(name string) BenchmarkInfo {
 		for _, b := range idx.Benchmarks {
 			if b.Name == name {
-				return b.Reliability
+				return b
 			}
 		}
-		return ""
+		return BenchmarkInfo{}
 	}
+	reliabilityFor := func(name string) string { return infoFor(name).Reliability }
 
 	if r := reliabilityFor("BenchmarkBar"); r != "noisy" {
 		t.Errorf("BenchmarkBar reliability = %q, want %q", r, "noisy")
@@ -857,12 +958,121 @@ func TestRebuildIndex(t *testing.T) {
 		t.Errorf("BenchmarkFoo reliability = %q, want %q", r, "reliable")
 	}
 
+	// Display unit follows the fastest version so no value drops below 1.
+	if u := infoFor("BenchmarkGC").DisplayUnit; u != "µs" {
+		t.Errorf("BenchmarkGC display unit = %q, want %q", u, "µs")
+	}
+	if u := infoFor("BenchmarkFoo").DisplayUnit; u != "ns" {
+		t.Errorf("BenchmarkFoo display unit = %q, want %q", u, "ns")
+	}
+
 	// platforms.json should have been created.
 	if _, err := os.Stat(tmpDir + "/platforms.json"); err != nil {
 		t.Errorf("platforms.json not created: %v", err)
 	}
 }
 
+func TestRebuildIndexPreRelease(t *testing.T) {
+	platformDir := t.TempDir()
+	for _, v := range []string{"1.26", "1.26rc1", "1.25", "1.26beta1"} {
+		data, err := json.Marshal(VersionData{Version: v, Benchmarks: map[string]Benchmark{}})
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		if err := os.WriteFile(platformDir+"/go"+v+".json", data, 0644); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	if err := rebuildIndex(platformDir, t.TempDir(), "linux-amd64", ExportOptions{OnDuplicate: DuplicateKeepNewest}); err != nil {
+		t.Fatalf("rebuildIndex failed: %v", err)
+	}
+	data, err := os.ReadFile(platformDir + "/index.json")
+	if err != nil {
+		t.Fatalf("failed to read index.json: %v", err)
+	}
+	var idx IndexData
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatalf("failed to unmarshal index.json: %v", err)
+	}
+
+	want := []struct {
+		version     string
+		provisional bool
+	}{{"1.25", false}, {"1.26beta1", true}, {"1.26rc1", true}, {"1.26", false}}
+	if len(idx.Versions) != len(want) {
+		t.Fatalf("got %d versions, want %d", len(idx.Versions), len(want))
+	}
+	for i, w := range want {
+		if idx.Versions[i].Version != w.version || idx.Versions[i].Provisional != w.provisional {
+			t.Errorf("versions[%d] = %+v, want %s (provisional=%v)", i, idx.Versions[i], w.version, w.provisional)
+		}
+	}
+}
+
+func TestParseBenchmarkFileRunMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	benchFile := tmpDir + "/2026-01-26_21-55-10.txt"
+	results := "goos: linux\ngoarch: amd64\ncpu: Test CPU\n" +
+		"BenchmarkFoo-4   \t1000\t100.0 ns/op\t0 B/op\t0 allocs/op\n"
+	if err := os.WriteFile(benchFile, []byte(results), 0644); err != nil {
+		t.Fatalf("failed to write results: %v", err)
+	}
+
+	// No sidecar: older runs must still export without pinning info.
+	vd, err := parseBenchmarkFile(benchFile, "1.25")
+	if err != nil {
+		t.Fatalf("parseBenchmarkFile failed: %v", err)
+	}
+	if vd.Metadata.System.Pinning != nil {
+		t.Errorf("expected no pinning without sidecar, got %+v", vd.Metadata.System.Pinning)
+	}
+	if vd.Metadata.Build != nil {
+		t.Errorf("expected no build metrics without sidecar, got %+v", vd.Metadata.Build)
+	}
+
+	sidecar := `{"system": {"pinning": {"method": "taskset", "cpus": [2, 3], "isolated": true},
+		"tuning": {"nice": -10, "governor": "performance", "turbo_disabled": true},
+		"cpu": "ignored", "logical_cores": 8, "physical_cores": 4, "memory_bytes": 17179869184,
+		"power_source": "battery"},
+		"build": {"module": "perf-tracking/buildtarget", "wall_seconds": 10.5,
+		"wall_seconds_runs": [12.3, 10.5, 9.9], "binary_bytes": 13607145}}`
+	if err := os.WriteFile(tmpDir+"/2026-01-26_21-55-10_metadata.json", []byte(sidecar), 0644); err != nil {
+		t.Fatalf("failed to write sidecar: %v", err)
+	}
+
+	vd, err = parseBenchmarkFile(benchFile, "1.25")
+	if err != nil {
+		t.Fatalf("parseBenchmarkFile failed: %v", err)
+	}
+	p := vd.Metadata.System.Pinning
+	if p == nil {
+		t.Fatal("expected pinning info from sidecar")
+	}
+	if p.Method != "taskset" || len(p.CPUs) != 2 || p.CPUs[0] != 2 || p.CPUs[1] != 3 || !p.Isolated {
+		t.Errorf("unexpected pinning info: %+v", p)
+	}
+	tuning := vd.Metadata.System.Tuning
+	if tuning == nil {
+		t.Fatal("expected tuning info from sidecar")
+	}
+	if tuning.Nice == nil || *tuning.Nice != -10 || tuning.Governor != "performance" ||
+		!tuning.TurboDisabled || tuning.IdleStatesDisabled {
+		t.Errorf("unexpected tuning info: %+v", tuning)
+	}
+	sys := vd.Metadata.System
+	if sys.LogicalCores != 8 || sys.PhysicalCores != 4 || sys.MemoryBytes != 17179869184 || sys.PowerSource != "battery" {
+		t.Errorf("unexpected native system info: %+v", sys)
+	}
+	if vd.Metadata.System.CPU != "Test CPU" {
+		t.Errorf("CPU = %q, want %q", vd.Metadata.System.CPU, "Test CPU")
+	}
+	build := vd.Metadata.Build
+	if build == nil || build.WallSeconds != 10.5 || len(build.WallSecondsRuns) != 3 || build.BinaryBytes != 13607145 {
+		t.Errorf("unexpected build metrics: %+v", build)
+	}
+}
+
 // TestAllBenchmarksWithDescriptionsHaveCategories ensures that every benchmark
 // with a description also has a category assigned
 func TestAllBenchmarksWithDescriptionsHaveCategories(t *testing.T) {
@@ -875,7 +1085,15 @@ func TestAllBenchmarksWithDescriptionsHaveCategories(t *testing.T) {
 		"BenchmarkSwissMapLarge",
 		"BenchmarkSwissMapPresized",
 		"BenchmarkSwissMapIteration",
+		"BenchmarkMapRange",
+		"BenchmarkMapKey",
+		"BenchmarkLookup",
 		"BenchmarkSmallAllocSpecialized",
+		"BenchmarkAllocScan",
+		"BenchmarkTinyAlloc",
+		"BenchmarkValueVsPointer",
+		"BenchmarkClosure",
+		"BenchmarkSliceConcat",
 		"BenchmarkSyncMap",
 		"BenchmarkGCThroughput",
 		"BenchmarkGCLatency",
@@ -886,10 +1104,14 @@ func TestAllBenchmarksWithDescriptionsHaveCategories(t *testing.T) {
 		"BenchmarkAtomicIncrement",
 		"BenchmarkMutexContention",
 		"BenchmarkChannelThroughput",
+		"BenchmarkTimeout",
+		"BenchmarkFanOut",
 		"BenchmarkGCMixedWorkload",
 		"BenchmarkGCSmallObjects",
 		"BenchmarkGoroutineCreate",
 		"BenchmarkStackGrowth",
+		"BenchmarkSyscall",
+		"BenchmarkStartup",
 
 		// Standard library benchmarks (actual names)
 		"BenchmarkJSONEncode",
@@ -900,12 +1122,16 @@ func TestAllBenchmarksWithDescriptionsHaveCategories(t *testing.T) {
 		"BenchmarkAESGCM",
 		"BenchmarkSHA",
 		"BenchmarkRSAKeyGen",
+		"BenchmarkX509Verify",
 		"BenchmarkRegexp",
 		"BenchmarkBufferedIO",
 		"BenchmarkCRC32",
 		"BenchmarkFNVHash",
 		"BenchmarkBinaryEncode",
+		"BenchmarkBase64",
+		"BenchmarkHex",
 		"BenchmarkStringsJoin",
+		"BenchmarkStringConcat",
 		// Legacy names for backwards compatibility
 		"BenchmarkReadAll",
 		"BenchmarkReadAllLarge",
@@ -926,6 +1152,44 @@ func TestAllBenchmarksWithDescriptionsHaveCategories(t *testing.T) {
 		"BenchmarkHTTP2",
 		"BenchmarkHTTPRequest",
 		"BenchmarkConnectionPool",
+		"BenchmarkHTTPStreaming",
+		"BenchmarkHTTPMiddleware",
+		"BenchmarkHTTPService",
+		"BenchmarkIdleConnScavenge",
+
+		// File watching benchmarks
+		"BenchmarkFileWatch",
+
+		// Random file read benchmarks
+		"BenchmarkRandomRead",
+
+		// Directory traversal benchmarks
+		"BenchmarkDirWalk",
+
+		// Archive benchmarks
+		"BenchmarkArchive",
+
+		// Byte search benchmarks
+		"BenchmarkBytesSearch",
+
+		// ID generation benchmarks
+		"BenchmarkIDGen",
+
+		// Integer parsing benchmarks
+		"BenchmarkParseIntBytes",
+
+		// Struct copying benchmarks
+		"BenchmarkStructCopy",
+
+		// database/sql benchmarks
+		"BenchmarkSQLPrepared",
+		"BenchmarkSQLPoolAcquire",
+		"BenchmarkSQLScan",
+
+		// Stdlib vs third-party comparisons
+		"BenchmarkAltJSONDecode",
+		"BenchmarkAltHTTPRouter",
+		"BenchmarkAltGzip",
 
 		// Legacy runtime benchmarks
 		"BenchmarkLargeAllocation",
@@ -945,3 +1209,64 @@ func TestAllBenchmarksWithDescriptionsHaveCategories(t *testing.T) {
 		})
 	}
 }
+
+func TestOpsPerSec(t *testing.T) {
+	// Rates 1e7 and 5e6 ops/s: mean 7.5e6, sample stddev 2.5e6·√2, t(1) = 12.706
+	mean, low, high := opsPerSec([]float64{100, 200, 0})
+	if mean != 7.5e6 {
+		t.Errorf("mean = %v, want 7.5e6", mean)
+	}
+	if wantHigh := 7.5e6 + 12.706*2.5e6; low != 0 || math.Abs(high-wantHigh) > 1e-3 {
+		t.Errorf("interval = [%v, %v], want [0, %v] (the lower bound clamped)", low, high, wantHigh)
+	}
+	if mean, low, high := opsPerSec(nil); mean != 0 || low != 0 || high != 0 {
+		t.Errorf("opsPerSec(nil) = %v, %v, %v, want zeros", mean, low, high)
+	}
+}
+
+func TestExportAllKeepsProfiledRunsApart(t *testing.T) {
+	dir := t.TempDir()
+	versionDir := filepath.Join(dir, "results", "go1.26")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// An older normal run and the newest one, with contention profiling on
+	// and twice as slow
+	for i, run := range []struct {
+		name, ns string
+		profiled bool
+	}{{"2026-01-26_10-00-00", "100", false}, {"2026-01-26_12-00-00", "200", true}} {
+		content := "goos: linux\ngoarch: amd64\ncpu: Xeon\n"
+		for range 5 {
+			content += "BenchmarkMutexContention-8 \t 1000\t " + run.ns + " ns/op\n"
+		}
+		path := filepath.Join(versionDir, run.name+".txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sidecar := fmt.Sprintf(`{"contention_profile": %t}`, run.profiled)
+		if err := os.WriteFile(runMetadataPath(path), []byte(sidecar), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Date(2026, 1, 26, 10+2*i, 0, 0, 0, time.UTC)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outputDir := filepath.Join(dir, "data")
+	if err := exportAll(filepath.Join(dir, "results"), outputDir, "", ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	vd, err := readVersionData(filepath.Join(outputDir, "linux-amd64", "go1.26.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vd.Metadata.ContentionProfile {
+		t.Error("export of a profiled run does not record contention_profile")
+	}
+	// Pooled with the normal run, the inter-run CV would be 0.47
+	if v := vd.Benchmarks["BenchmarkMutexContention"].NsPerOpVariance; v != 0 {
+		t.Errorf("ns/op variance = %v, want 0 from the profiled run alone", v)
+	}
+}
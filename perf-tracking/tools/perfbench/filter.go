@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// BenchmarkFilter selects benchmarks by a regexp over the full name,
+// sub-benchmarks included, and by category. The zero value matches every
+// benchmark.
+type BenchmarkFilter struct {
+	Pattern    *regexp.Regexp
+	Categories []string // any of benchmarkCategories
+}
+
+// parseBenchmarkFilter builds a filter from the -bench and -category flags;
+// categories is a comma-separated list
+func parseBenchmarkFilter(pattern, categories string) (BenchmarkFilter, error) {
+	var f BenchmarkFilter
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return f, fmt.Errorf("invalid -bench pattern: %w", err)
+		}
+		f.Pattern = re
+	}
+	for c := range strings.SplitSeq(categories, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !slices.Contains(benchmarkCategories, c) {
+			return f, fmt.Errorf("unknown category %q (want one of %s)", c, strings.Join(benchmarkCategories, ", "))
+		}
+		f.Categories = append(f.Categories, c)
+	}
+	return f, nil
+}
+
+// Match reports whether the benchmark name passes the filter
+func (f BenchmarkFilter) Match(name string) bool {
+	if f.Pattern != nil && !f.Pattern.MatchString(name) {
+		return false
+	}
+	return len(f.Categories) == 0 || slices.Contains(f.Categories, getBenchmarkCategory(name))
+}
+
+// active reports whether the filter can exclude anything
+func (f BenchmarkFilter) active() bool {
+	return f.Pattern != nil || len(f.Categories) > 0
+}
+
+// filterBenchmarks removes the entries of m whose name f rejects
+func filterBenchmarks[V any](m map[string]V, f BenchmarkFilter) {
+	if f.active() {
+		maps.DeleteFunc(m, func(name string, _ V) bool { return !f.Match(name) })
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBenchmarkFilter(t *testing.T) {
+	names := []string{
+		"BenchmarkTLSHandshake/TLS13",
+		"BenchmarkTLSResume",
+		"BenchmarkGCLatency",
+		"BenchmarkAESCTR/Size1KB",
+		"BenchmarkUnknownThing",
+	}
+	for _, tt := range []struct {
+		pattern, categories string
+		want                []string
+	}{
+		{"", "", names},
+		{"BenchmarkTLS.*", "", names[:2]},
+		{"/TLS13$", "", names[:1]},
+		{"", "runtime", []string{"BenchmarkGCLatency"}},
+		{"", "stdlib, uncategorized", []string{"BenchmarkAESCTR/Size1KB", "BenchmarkUnknownThing"}},
+		{"Benchmark[AG]", "stdlib", []string{"BenchmarkAESCTR/Size1KB"}},
+	} {
+		f, err := parseBenchmarkFilter(tt.pattern, tt.categories)
+		if err != nil {
+			t.Fatalf("parseBenchmarkFilter(%q, %q): %v", tt.pattern, tt.categories, err)
+		}
+		var got []string
+		for _, name := range names {
+			if f.Match(name) {
+				got = append(got, name)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("-bench %q -category %q matched %v, want %v", tt.pattern, tt.categories, got, tt.want)
+		}
+	}
+
+	for _, bad := range [][2]string{{"Benchmark(", ""}, {"", "network"}} {
+		if _, err := parseBenchmarkFilter(bad[0], bad[1]); err == nil {
+			t.Errorf("parseBenchmarkFilter(%q, %q) succeeded, want error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestApplyExportOptionsFilter(t *testing.T) {
+	vd := &VersionData{Benchmarks: map[string]Benchmark{
+		"BenchmarkTLSHandshake/TLS13":      {NsPerOp: 1},
+		"BenchmarkGCLatency":               {NsPerOp: 1},
+		"BenchmarkLookup/Int/Size8/Map":    {NsPerOp: 1},
+		"BenchmarkLookup/Int/Size8/Linear": {NsPerOp: 2},
+	}}
+	vd.Crossovers = findCrossovers(vd.Benchmarks)
+	if len(vd.Crossovers) != 1 {
+		t.Fatalf("crossovers = %+v, want one", vd.Crossovers)
+	}
+
+	f, err := parseBenchmarkFilter("", "runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyExportOptions(vd, ExportOptions{Filter: f})
+	if _, ok := vd.Benchmarks["BenchmarkTLSHandshake/TLS13"]; ok || len(vd.Benchmarks) != 3 {
+		t.Errorf("benchmarks after filter = %v", vd.Benchmarks)
+	}
+
+	f, _ = parseBenchmarkFilter("GC", "")
+	applyExportOptions(vd, ExportOptions{Filter: f})
+	if len(vd.Benchmarks) != 1 || len(vd.Crossovers) != 0 {
+		t.Errorf("after -bench GC: %d benchmarks, crossovers %+v", len(vd.Benchmarks), vd.Crossovers)
+	}
+}
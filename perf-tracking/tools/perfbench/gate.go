@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exitRegression is the exit status of -fail-on-regression when a benchmark
+// regressed beyond its threshold, distinct from 1 for usage and I/O errors
+const exitRegression = 2
+
+// RegressionThresholds holds the slowdown, in percent, that -fail-on-regression
+// tolerates. The most specific entry wins: the full benchmark name, then its
+// top-level name (so "BenchmarkTLSHandshake" covers all its sub-benchmarks),
+// then its category, then Default.
+//
+// Metrics gates the comparedMetrics and custom metrics, by unit, with one
+// threshold each for all benchmarks; metrics without an entry are not gated.
+// For MB/s and other higher-is-better metrics the threshold applies to a
+// drop.
+//
+// MinEffectNs additionally requires an ns/op slowdown to exceed an absolute
+// size, so a statistically sound +0.3 ns on a 2 ns benchmark, +15%, passes.
+//
+// Read from the -thresholds file:
+//
+//	{"default": 5, "categories": {"networking": 15}, "benchmarks": {"BenchmarkGCLatency": 25},
+//	 "metrics": {"allocs/op": 0, "B/op": 10},
+//	 "min_effect_ns": {"default": 2, "benchmarks": {"BenchmarkJSONDecode": 50}}}
+type RegressionThresholds struct {
+	Default     float64            `json:"default"`
+	Categories  map[string]float64 `json:"categories,omitempty"`
+	Benchmarks  map[string]float64 `json:"benchmarks,omitempty"`
+	Metrics     map[string]float64 `json:"metrics,omitempty"`
+	MinEffectNs MinEffects         `json:"min_effect_ns"`
+}
+
+// MinEffects holds the smallest ns/op slowdown, in nanoseconds, that fails
+// the gate, resolved like the percentage thresholds. Default falls back to
+// -min-effect.
+type MinEffects struct {
+	Default    float64            `json:"default"`
+	Categories map[string]float64 `json:"categories,omitempty"`
+	Benchmarks map[string]float64 `json:"benchmarks,omitempty"`
+}
+
+// parseThresholdPercent parses a -threshold value such as "5%" or "2.5"
+func parseThresholdPercent(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid threshold %q (want a non-negative percentage, e.g. 5%%)", s)
+	}
+	return v, nil
+}
+
+// parseMinEffect parses a -min-effect value such as "2ns", "1.5us" or "0.5",
+// which is in nanoseconds, returning nanoseconds
+func parseMinEffect(in string) (float64, error) {
+	s := strings.TrimSpace(in)
+	scale := 1.0
+	for _, u := range []struct {
+		suffix string
+		ns     float64
+	}{{"ns", 1}, {"us", 1e3}, {"µs", 1e3}, {"ms", 1e6}} {
+		if rest, ok := strings.CutSuffix(s, u.suffix); ok {
+			s, scale = strings.TrimSpace(rest), u.ns
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("invalid minimum effect %q (want a non-negative duration, e.g. 2ns or 1.5us)", in)
+	}
+	return v * scale, nil
+}
+
+// loadThresholds reads a thresholds file. def and minEffect are used when
+// the file has no "default" entry at the top level or in "min_effect_ns".
+func loadThresholds(path string, def, minEffect float64) (RegressionThresholds, error) {
+	thresholds := RegressionThresholds{Default: def, MinEffectNs: MinEffects{Default: minEffect}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return thresholds, fmt.Errorf("failed to read thresholds: %w", err)
+	}
+	if err := json.Unmarshal(data, &thresholds); err != nil {
+		return thresholds, fmt.Errorf("failed to parse thresholds %s: %w", path, err)
+	}
+	if err := thresholds.validate(); err != nil {
+		return thresholds, fmt.Errorf("%s: %w", path, err)
+	}
+	return thresholds, nil
+}
+
+// validate rejects negative thresholds and unknown categories, which would
+// otherwise silently never match
+func (t RegressionThresholds) validate() error {
+	if err := validateLevels("threshold", t.Default, t.Categories, t.Benchmarks); err != nil {
+		return err
+	}
+	m := t.MinEffectNs
+	if err := validateLevels("minimum effect", m.Default, m.Categories, m.Benchmarks); err != nil {
+		return err
+	}
+	for unit, v := range t.Metrics {
+		if err := validateMetricUnit(unit); err != nil {
+			return err
+		}
+		if v < 0 {
+			return fmt.Errorf("negative threshold %v for %s", v, unit)
+		}
+	}
+	return nil
+}
+
+// validateLevels checks the default, per-category and per-benchmark values
+// of one kind of limit
+func validateLevels(kind string, def float64, categories, benchmarks map[string]float64) error {
+	if def < 0 {
+		return fmt.Errorf("negative default %s %v", kind, def)
+	}
+	for category, v := range categories {
+		if !slices.Contains(benchmarkCategories, category) {
+			return fmt.Errorf("unknown category %q", category)
+		}
+		if v < 0 {
+			return fmt.Errorf("negative %s %v for category %s", kind, v, category)
+		}
+	}
+	for name, v := range benchmarks {
+		if v < 0 {
+			return fmt.Errorf("negative %s %v for %s", kind, v, name)
+		}
+	}
+	return nil
+}
+
+// validateMetricUnit accepts the comparedMetrics and custom units, which by
+// convention qualify the quantity, as in p99-ns, req/s or resumed-%. ns/op
+// has thresholds of its own, and a bare word like "allocs" is a typo.
+func validateMetricUnit(unit string) error {
+	if isComparedMetric(unit) {
+		return nil
+	}
+	if unit == "ns/op" || !strings.ContainsAny(unit, "/-%") || strings.ContainsAny(unit, " \t") {
+		return fmt.Errorf("invalid metric %q (want a unit such as B/op, allocs/op, MB/s or p99-ns)", unit)
+	}
+	return nil
+}
+
+func isComparedMetric(unit string) bool {
+	for _, m := range comparedMetrics {
+		if m.Unit == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMetricThresholds parses a -metric-thresholds value such as
+// "allocs/op=0,B/op=10%"
+func parseMetricThresholds(s string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for entry := range strings.SplitSeq(s, ",") {
+		unit, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid metric threshold %q (want unit=percent, e.g. allocs/op=0)", entry)
+		}
+		if err := validateMetricUnit(unit); err != nil {
+			return nil, err
+		}
+		v, err := parseThresholdPercent(value)
+		if err != nil {
+			return nil, err
+		}
+		thresholds[unit] = v
+	}
+	return thresholds, nil
+}
+
+// forBenchmark returns the threshold that applies to the benchmark name
+func (t RegressionThresholds) forBenchmark(name string) float64 {
+	return mostSpecific(name, t.Benchmarks, t.Categories, t.Default)
+}
+
+// minEffectFor returns the minimum ns/op slowdown that applies to the
+// benchmark name
+func (t RegressionThresholds) minEffectFor(name string) float64 {
+	m := t.MinEffectNs
+	return mostSpecific(name, m.Benchmarks, m.Categories, m.Default)
+}
+
+// mostSpecific looks name up by full name, top-level name and category,
+// falling back to def
+func mostSpecific(name string, benchmarks, categories map[string]float64, def float64) float64 {
+	if v, ok := benchmarks[name]; ok {
+		return v
+	}
+	if v, ok := benchmarks[benchmarkBaseName(name)]; ok {
+		return v
+	}
+	if v, ok := categories[getBenchmarkCategory(name)]; ok {
+		return v
+	}
+	return def
+}
+
+// GateFailure is a regression beyond its threshold
+type GateFailure struct {
+	Benchmark    string
+	Metric       string // "ns/op" or the unit of another metric
+	DeltaPercent float64
+	Threshold    float64
+	EffectNs     float64 // ns/op slowdown, for ns/op failures
+	MinEffectNs  float64 // the minimum effect it exceeded, 0 if none applied
+}
+
+// gateRegressions returns the significant regressions larger than their
+// threshold, worst first: slowdowns in ns/op and, for the metrics with a
+// threshold, growth in B/op, allocs/op or p99-ns and drops in MB/s or req/s.
+// Changes that fail the significance test never fail the gate, however large,
+// and ns/op slowdowns must also exceed their minimum effect in nanoseconds.
+func gateRegressions(comparisons []Comparison, thresholds RegressionThresholds) []GateFailure {
+	var failures []GateFailure
+	for _, c := range comparisons {
+		threshold := thresholds.forBenchmark(c.Benchmark)
+		minEffect := thresholds.minEffectFor(c.Benchmark)
+		effect := c.TargetNs - c.BaselineNs
+		if c.significant() && c.DeltaPercent > threshold && (minEffect == 0 || effect > minEffect) {
+			failures = append(failures, GateFailure{
+				Benchmark:    c.Benchmark,
+				Metric:       "ns/op",
+				DeltaPercent: c.DeltaPercent,
+				Threshold:    threshold,
+				EffectNs:     effect,
+				MinEffectNs:  minEffect,
+			})
+		}
+		for unit, m := range c.Metrics {
+			threshold, ok := thresholds.Metrics[unit]
+			if ok && m.Significant && m.regressionPercent() > threshold {
+				failures = append(failures, GateFailure{
+					Benchmark:    c.Benchmark,
+					Metric:       unit,
+					DeltaPercent: m.DeltaPercent,
+					Threshold:    threshold,
+				})
+			}
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if a, b := math.Abs(failures[i].DeltaPercent), math.Abs(failures[j].DeltaPercent); a != b {
+			return a > b
+		}
+		return failures[i].Benchmark+failures[i].Metric < failures[j].Benchmark+failures[j].Metric
+	})
+	return failures
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseThresholdPercent(t *testing.T) {
+	for in, want := range map[string]float64{"5%": 5, "2.5": 2.5, " 0% ": 0} {
+		if got, err := parseThresholdPercent(in); err != nil || got != want {
+			t.Errorf("parseThresholdPercent(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "five", "-1%", "5%%"} {
+		if _, err := parseThresholdPercent(in); err == nil {
+			t.Errorf("parseThresholdPercent(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestLoadThresholds(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Without "default" the -threshold value is kept
+	th, err := loadThresholds(write("ok.json", `{"categories": {"networking": 15}}`), 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Default != 5 || th.Categories["networking"] != 15 || th.MinEffectNs.Default != 2 {
+		t.Errorf("thresholds = %+v", th)
+	}
+
+	th, err = loadThresholds(write("default.json", `{"default": 3}`), 5, 0)
+	if err != nil || th.Default != 3 {
+		t.Errorf("file default: %+v, %v; want 3", th, err)
+	}
+
+	// A min_effect_ns section without "default" keeps -min-effect too
+	th, err = loadThresholds(write("effect.json", `{"min_effect_ns": {"benchmarks": {"BenchmarkJSONDecode": 50}}}`), 5, 2)
+	if err != nil || th.MinEffectNs.Default != 2 || th.MinEffectNs.Benchmarks["BenchmarkJSONDecode"] != 50 {
+		t.Errorf("min effects: %+v, %v", th.MinEffectNs, err)
+	}
+
+	for name, content := range map[string]string{
+		"category.json": `{"categories": {"network": 10}}`,
+		"negative.json": `{"benchmarks": {"BenchmarkTCPConnect": -1}}`,
+		"syntax.json":   `{"default": }`,
+		"metric.json":   `{"metrics": {"ns/op": 5}}`,
+		"effect.json":   `{"min_effect_ns": {"categories": {"runtime": -2}}}`,
+	} {
+		if _, err := loadThresholds(write(name, content), 5, 0); err == nil {
+			t.Errorf("%s: loaded, want error", name)
+		}
+	}
+}
+
+func TestParseMinEffect(t *testing.T) {
+	for in, want := range map[string]float64{"2ns": 2, "0.5": 0.5, "1.5us": 1500, "3 µs": 3000, "1ms": 1e6, "0": 0} {
+		if got, err := parseMinEffect(in); err != nil || got != want {
+			t.Errorf("parseMinEffect(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "2s", "-1ns", "fast", "ns", "NaN", "nanns", "+Infus"} {
+		if _, err := parseMinEffect(in); err == nil {
+			t.Errorf("parseMinEffect(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestGateRegressionsMinEffect(t *testing.T) {
+	thresholds := RegressionThresholds{
+		Default: 5,
+		MinEffectNs: MinEffects{
+			Default:    2,
+			Categories: map[string]float64{"networking": 1000},
+			Benchmarks: map[string]float64{"BenchmarkJSONDecode": 0},
+		},
+	}
+	comparison := func(name string, base, target float64) Comparison {
+		return Comparison{Benchmark: name, BaselineNs: base, TargetNs: target, DeltaPercent: (target/base - 1) * 100,
+			BaselineSamples: 5, TargetSamples: 5, PValue: 0.01}
+	}
+	comparisons := []Comparison{
+		comparison("BenchmarkMapIteration", 2, 3),      // +50% but only 1 ns
+		comparison("BenchmarkMapCreation", 100, 110),   // +10%, 10 ns
+		comparison("BenchmarkTCPConnect", 5000, 5600),  // +12%, below networking's 1 µs
+		comparison("BenchmarkJSONDecode", 3, 3.5),      // minimum lifted by name
+		comparison("BenchmarkSmallAllocation", 40, 41), // 1 ns but within 5%
+	}
+
+	var got []string
+	for _, f := range gateRegressions(comparisons, thresholds) {
+		got = append(got, f.Benchmark)
+		if f.Benchmark == "BenchmarkMapCreation" && (f.EffectNs != 10 || f.MinEffectNs != 2) {
+			t.Errorf("MapCreation failure = %+v", f)
+		}
+	}
+	if want := "BenchmarkJSONDecode,BenchmarkMapCreation"; strings.Join(got, ",") != want {
+		t.Errorf("failures = %v, want %s", got, want)
+	}
+}
+
+func TestGateRegressions(t *testing.T) {
+	thresholds := RegressionThresholds{
+		Default:    5,
+		Categories: map[string]float64{"networking": 15},
+		Benchmarks: map[string]float64{
+			"BenchmarkGCLatency":          25,
+			"BenchmarkTLSHandshake/TLS13": 2,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		delta   float64
+		want    float64 // threshold applied
+		failing bool
+		noisy   bool // fails the significance test
+	}{
+		{name: "BenchmarkMapCreation", delta: 4, want: 5},
+		{name: "BenchmarkMapIteration", delta: 6, want: 5, failing: true},
+		{name: "BenchmarkMapCreation-8", delta: 30, want: 5, noisy: true},
+		{name: "BenchmarkTCPConnect", delta: 12, want: 15},
+		{name: "BenchmarkGCLatency-16", delta: 20, want: 25},
+		{name: "BenchmarkTLSHandshake/TLS12", delta: 10, want: 15},
+		{name: "BenchmarkTLSHandshake/TLS13", delta: 3, want: 2, failing: true},
+		{name: "BenchmarkSmallAllocation", delta: -40, want: 5},
+	}
+
+	var comparisons []Comparison
+	for _, tt := range tests {
+		if got := thresholds.forBenchmark(tt.name); got != tt.want {
+			t.Errorf("forBenchmark(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+		c := Comparison{Benchmark: tt.name, DeltaPercent: tt.delta, BaselineSamples: 5, TargetSamples: 5, PValue: 0.01}
+		if tt.noisy {
+			c.PValue = 0.2
+		}
+		comparisons = append(comparisons, c)
+	}
+
+	failures := gateRegressions(comparisons, thresholds)
+	var got, want []string
+	for _, f := range failures {
+		got = append(got, f.Benchmark)
+	}
+	for _, tt := range tests {
+		if tt.failing {
+			want = append(want, tt.name)
+		}
+	}
+	// Worst first, which happens to be table order here
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("failures = %v, want %v", got, want)
+	}
+	if len(failures) > 1 && failures[1].Threshold != 2 {
+		t.Errorf("TLS13 threshold = %v, want 2", failures[1].Threshold)
+	}
+}
+
+func TestGateRegressionsMetrics(t *testing.T) {
+	metric := func(delta float64, higherIsBetter, significant bool) *MetricDelta {
+		return &MetricDelta{DeltaPercent: delta, HigherIsBetter: higherIsBetter, Significant: significant}
+	}
+	comparisons := []Comparison{
+		{Benchmark: "BenchmarkA", DeltaPercent: 0.5, Metrics: map[string]*MetricDelta{
+			"allocs/op": metric(100, false, true),
+			"B/op":      metric(4, false, true),
+		}},
+		{Benchmark: "BenchmarkB", DeltaPercent: 0.5, Metrics: map[string]*MetricDelta{
+			"MB/s":      metric(-8, true, true),
+			"allocs/op": metric(50, false, false),
+		}},
+		{Benchmark: "BenchmarkC", DeltaPercent: 0.5, Metrics: map[string]*MetricDelta{
+			"MB/s": metric(30, true, true),
+			"B/op": metric(-40, false, true),
+		}},
+	}
+
+	metrics, err := parseMetricThresholds("allocs/op=0, B/op=5%,MB/s=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := gateRegressions(comparisons, RegressionThresholds{Default: 5, Metrics: metrics})
+	var got []string
+	for _, f := range failures {
+		got = append(got, f.Benchmark+" "+f.Metric)
+	}
+	// B/op +4% is within 5%, the insignificant allocs/op and the
+	// improvements never fail
+	if want := "BenchmarkA allocs/op,BenchmarkB MB/s"; strings.Join(got, ",") != want {
+		t.Errorf("failures = %v, want %s", got, want)
+	}
+
+	// Without metric thresholds only ns/op is gated
+	if failures := gateRegressions(comparisons, RegressionThresholds{Default: 5}); len(failures) != 0 {
+		t.Errorf("failures = %+v, want none", failures)
+	}
+
+	for _, in := range []string{"allocs=0", "allocs/op", "B/op=-1", "ns/op=5"} {
+		if _, err := parseMetricThresholds(in); err == nil {
+			t.Errorf("parseMetricThresholds(%q) succeeded, want error", in)
+		}
+	}
+
+	// Custom metrics are gated like the built-in ones, in their direction
+	metrics, err = parseMetricThresholds("resumed-%=10,p99-ns=20%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures = gateRegressions([]Comparison{
+		{Benchmark: "BenchmarkTLSResume", Metrics: map[string]*MetricDelta{
+			"resumed-%": metric(-30, true, true),
+		}},
+		{Benchmark: "BenchmarkTLSHandshake", Metrics: map[string]*MetricDelta{
+			"p99-ns": metric(15, false, true),
+		}},
+	}, RegressionThresholds{Default: 5, Metrics: metrics})
+	if len(failures) != 1 || failures[0].Benchmark != "BenchmarkTLSResume" || failures[0].Metric != "resumed-%" {
+		t.Errorf("failures = %+v, want BenchmarkTLSResume resumed-%%", failures)
+	}
+}
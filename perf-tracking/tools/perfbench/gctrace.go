@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GCTrace summarizes the GODEBUG=gctrace=1 output of one go test run of a
+// benchmark, captured by collect_benchmarks.py --gctrace. It covers the
+// whole run, including b.N calibration, so compare it across versions rather
+// than dividing by ns/op.
+type GCTrace struct {
+	Cycles int `json:"cycles"`
+	// Share of CPU time spent in GC since process start, from the last cycle
+	CPUPercent float64 `json:"cpu_percent"`
+	// Wall time of both stop-the-world phases summed over all cycles
+	STWMillis float64 `json:"stw_ms"`
+}
+
+// gcTraceSectionPrefix starts each benchmark's section in the sidecar,
+// followed by the benchmark name as passed to --gctrace
+const gcTraceSectionPrefix = "=== "
+
+// "gc 12 @1.234s 3%: 0.021+1.2+0.004 ms clock, ..."
+var gcTraceLine = regexp.MustCompile(`^gc (\d+) @[\d.]+s (\d+)%: ([\d.]+)\+[\d.]+\+([\d.]+) ms clock`)
+
+// gcTracePath returns the gctrace sidecar path for a benchmark result file
+func gcTracePath(benchFile string) string {
+	return strings.TrimSuffix(benchFile, filepath.Ext(benchFile)) + "_gctrace.txt"
+}
+
+// loadGCTrace reads the gctrace sidecar for benchFile, keyed by benchmark
+// name. Returns nil without error when the run had no --gctrace.
+func loadGCTrace(benchFile string) (map[string]*GCTrace, error) {
+	path := gcTracePath(benchFile)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gctrace %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }() // read-only
+
+	traces := make(map[string]*GCTrace)
+	var current *GCTrace
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, gcTraceSectionPrefix); ok {
+			current = &GCTrace{}
+			traces[strings.TrimSpace(name)] = current
+			continue
+		}
+		m := gcTraceLine.FindStringSubmatch(line)
+		if m == nil || current == nil {
+			continue
+		}
+		current.Cycles++
+		current.CPUPercent, _ = strconv.ParseFloat(m[2], 64)
+		sweepTerm, _ := strconv.ParseFloat(m[3], 64)
+		markTerm, _ := strconv.ParseFloat(m[4], 64)
+		current.STWMillis += sweepTerm + markTerm
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read gctrace %s: %w", path, err)
+	}
+	return traces, nil
+}
+
+// traceFor returns the trace covering the benchmark result name (as parsed,
+// without CPU suffix): the one for the benchmark itself or for one of its
+// parents. It serves GC traces, execution traces and allocation profiles.
+func traceFor[T any](traces map[string]*T, name string) *T {
+	for {
+		if t, ok := traces[name]; ok {
+			return t
+		}
+		idx := strings.LastIndex(name, "/")
+		if idx == -1 {
+			return nil
+		}
+		name = name[:idx]
+	}
+}
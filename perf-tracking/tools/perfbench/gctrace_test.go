@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadGCTrace(t *testing.T) {
+	dir := t.TempDir()
+	benchFile := filepath.Join(dir, "2026-01-26_21-55-10.txt")
+	benchOutput := `goos: linux
+goarch: amd64
+cpu: Test CPU
+BenchmarkHTTPService/Sequential-16   	   10000	    118810 ns/op
+BenchmarkHTTPService/Parallel-16     	   10000	    115918 ns/op
+BenchmarkGCThroughput-16             	    1000	   1000000 ns/op
+`
+	if err := os.WriteFile(benchFile, []byte(benchOutput), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err := loadGCTrace(benchFile)
+	if err != nil || traces != nil {
+		t.Fatalf("missing sidecar: got %v, %v; want nil, nil", traces, err)
+	}
+
+	sidecar := `=== BenchmarkHTTPService
+gc 1 @0.004s 1%: 0.011+0.41+0.002 ms clock, 0.011+0.10/0.13/0+0.002 ms cpu, 3->4->1 MB, 4 MB goal, 0 MB stacks, 0 MB globals, 1 P
+goos: linux
+gc 2 @0.010s 2%: 0.020+0.50+0.008 ms clock, 0.020+0.10/0.13/0+0.008 ms cpu, 4->4->1 MB, 4 MB goal, 0 MB stacks, 0 MB globals, 1 P
+gc 3 @0.020s 4%: 0.030+0.60+0.010 ms clock, 0.030+0.10/0.13/0+0.010 ms cpu, 4->4->1 MB, 4 MB goal, 0 MB stacks, 0 MB globals, 1 P (forced)
+=== BenchmarkGCThroughput/Small
+`
+	if err := os.WriteFile(gcTracePath(benchFile), []byte(sidecar), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err = loadGCTrace(benchFile)
+	if err != nil {
+		t.Fatalf("loadGCTrace failed: %v", err)
+	}
+	svc := traces["BenchmarkHTTPService"]
+	if svc == nil || svc.Cycles != 3 || svc.CPUPercent != 4 {
+		t.Fatalf("BenchmarkHTTPService trace = %+v", svc)
+	}
+	if want := 0.011 + 0.002 + 0.020 + 0.008 + 0.030 + 0.010; math.Abs(svc.STWMillis-want) > 1e-9 {
+		t.Errorf("STWMillis = %v, want %v", svc.STWMillis, want)
+	}
+	if small := traces["BenchmarkGCThroughput/Small"]; small == nil || small.Cycles != 0 {
+		t.Errorf("empty section should yield a zero trace, got %+v", small)
+	}
+
+	// Sub-benchmarks inherit the trace of the benchmark that was traced
+	vd, err := parseBenchmarkFile(benchFile, "1.25")
+	if err != nil {
+		t.Fatalf("parseBenchmarkFile failed: %v", err)
+	}
+	for _, name := range []string{"BenchmarkHTTPService/Sequential", "BenchmarkHTTPService/Parallel"} {
+		if got := vd.Benchmarks[name].GCTrace; got == nil || got.Cycles != 3 {
+			t.Errorf("%s: GCTrace = %+v, want the BenchmarkHTTPService trace", name, got)
+		}
+	}
+	if got := vd.Benchmarks["BenchmarkGCThroughput"].GCTrace; got != nil {
+		t.Errorf("parent must not inherit a sub-benchmark trace, got %+v", got)
+	}
+}
+
+func TestGCTraceSidecarIsNotAResultFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"2026-01-26_21-55-10.txt", "2026-01-26_21-55-10_gctrace.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := mainResultFiles(dir)
+	if len(files) != 1 || filepath.Base(files[0]) != "2026-01-26_21-55-10.txt" {
+		t.Errorf("mainResultFiles = %v, want only the result file", files)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench
+
+go 1.25.5
+
+require (
+	github.com/google/pprof v0.0.0-20250317173921-a4b03ec1a45e
+	golang.org/x/exp v0.0.0-20251219203646-944ab1f22d93
+	golang.org/x/perf v0.0.0-20251208221838-04cf7a2dca90
+)
+
+require github.com/aclements/go-moremath v0.0.0-20210112150236-f10218a38794 // indirect
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Contributed archives hold text results and small JSON sidecars only
+	maxIngestFileSize  = 64 << 20
+	maxIngestTotalSize = 512 << 20
+
+	// The collector defaults to 20 samples; fewer than this is not a real run
+	minIngestSamples = 5
+)
+
+var versionDirPattern = regexp.MustCompile(`^go\d+\.\d+`)
+
+// benchmarkFuncPattern matches a top-level benchmark in a suite source file
+var benchmarkFuncPattern = regexp.MustCompile(`(?m)^func (Benchmark\w*)\(\w+ \*testing\.B\)`)
+
+// ingestArchive validates a community-submitted results archive and, if it
+// passes and outputDir is set, exports it into outputDir and regenerates the
+// platform index.
+//
+// The archive (.tar.gz, .tgz or .zip) must contain one platform's collector
+// output, i.e. go<version>/ directories with <timestamp>.txt result files and
+// their _metadata.json sidecars, as produced by:
+//
+//	tar czf results.tar.gz -C results/stable linux-arm64
+//
+// Every run must include the required benchmarks, see suiteBenchmarks.
+func ingestArchive(archivePath, outputDir, expectedSHA string, required []string, opts ExportOptions) error {
+	fmt.Println("=== Validating Contributed Results ===")
+	fmt.Printf("Archive: %s\n", archivePath)
+
+	tmpDir, err := os.MkdirTemp("", "perfbench-validate-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }() // best-effort cleanup of scratch space
+
+	if err := extractArchive(archivePath, tmpDir); err != nil {
+		return fmt.Errorf("failed to extract archive: %w", err)
+	}
+
+	resultsDir, err := findResultsDir(tmpDir)
+	if err != nil {
+		return err
+	}
+
+	platform, problems := validateContribution(resultsDir, expectedSHA, required)
+	if len(problems) > 0 {
+		fmt.Printf("\n✗ Contribution rejected (%d problem(s)):\n", len(problems))
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		return fmt.Errorf("contribution failed validation")
+	}
+	fmt.Printf("✓ Validation passed (platform %s)\n", platform)
+	if outputDir == "" {
+		return nil
+	}
+
+	fmt.Println()
+	return exportAll(resultsDir, outputDir, platform, opts)
+}
+
+// extractArchive unpacks a .tar.gz/.tgz or .zip archive into destDir,
+// rejecting entries that would escape destDir or exceed the size limits.
+func extractArchive(archivePath, destDir string) error {
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(archivePath, destDir)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return extractTarGz(archivePath, destDir)
+	default:
+		return fmt.Errorf("unsupported archive format (want .tar.gz, .tgz or .zip): %s", archivePath)
+	}
+}
+
+func extractTarGz(archivePath, destDir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }() // read-only
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gz.Close() }() // read-only
+
+	tr := tar.NewReader(gz)
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+		default:
+			return fmt.Errorf("unsupported entry type for %s (only regular files are allowed)", hdr.Name)
+		}
+		total += hdr.Size
+		if err := writeArchiveEntry(destDir, hdr.Name, hdr.Size, total, hdr.ModTime, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(archivePath, destDir string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }() // read-only
+
+	var total int64
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		if !zf.Mode().IsRegular() {
+			return fmt.Errorf("unsupported entry type for %s (only regular files are allowed)", zf.Name)
+		}
+		size := int64(zf.UncompressedSize64)
+		total += size
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = writeArchiveEntry(destDir, zf.Name, size, total, zf.Modified, rc)
+		_ = rc.Close() // read-only
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArchiveEntry writes one archive member below destDir after checking
+// its path and size. total is the running uncompressed size of the archive.
+// The original mtime is kept because export derives collected_at from it.
+func writeArchiveEntry(destDir, name string, size, total int64, modTime time.Time, r io.Reader) error {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("archive entry escapes destination: %s", name)
+	}
+	if ext := filepath.Ext(clean); ext != ".txt" && ext != ".json" {
+		// Skip stray files (READMEs, .DS_Store) rather than rejecting the archive
+		return nil
+	}
+	if size > maxIngestFileSize {
+		return fmt.Errorf("archive entry too large: %s (%d bytes)", name, size)
+	}
+	if total > maxIngestTotalSize {
+		return fmt.Errorf("archive exceeds %d bytes uncompressed", maxIngestTotalSize)
+	}
+
+	target := filepath.Join(destDir, clean)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	// LimitReader guards against headers that understate the real size
+	n, err := io.Copy(out, io.LimitReader(r, maxIngestFileSize+1))
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if n > maxIngestFileSize {
+		return fmt.Errorf("archive entry too large: %s", name)
+	}
+	if !modTime.IsZero() {
+		return os.Chtimes(target, modTime, modTime)
+	}
+	return nil
+}
+
+// findResultsDir locates the single directory holding go<version>/ subdirs.
+func findResultsDir(root string) (string, error) {
+	candidates := make(map[string]bool)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && versionDirPattern.MatchString(d.Name()) {
+			candidates[filepath.Dir(path)] = true
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan archive: %w", err)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("archive contains no go<version>/ result directories")
+	case 1:
+		for dir := range candidates {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("archive must contain results for exactly one platform, found %d", len(candidates))
+}
+
+// validateContribution checks every version in resultsDir and returns the
+// detected platform plus a list of problems (empty when the data is accepted).
+func validateContribution(resultsDir, expectedSHA string, required []string) (string, []string) {
+	var problems []string
+	platforms := make(map[string]bool)
+
+	entries, err := os.ReadDir(resultsDir)
+	if err != nil {
+		return "", []string{fmt.Sprintf("failed to read results: %v", err)}
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !versionDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		version := strings.TrimPrefix(entry.Name(), "go")
+		mainFiles := mainResultFiles(filepath.Join(resultsDir, entry.Name()))
+		if len(mainFiles) == 0 {
+			problems = append(problems, fmt.Sprintf("go%s: no main result file", version))
+			continue
+		}
+
+		vd, err := parseBenchmarkFile(mainFiles[0], version)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("go%s: %v", version, err))
+			continue
+		}
+		for _, p := range validateVersionData(vd, expectedSHA, required) {
+			problems = append(problems, fmt.Sprintf("go%s: %s", version, p))
+		}
+		if vd.Metadata.System.OS != "" && vd.Metadata.System.Arch != "" {
+			platforms[vd.Metadata.System.OS+"-"+vd.Metadata.System.Arch] = true
+		}
+	}
+
+	var platform string
+	switch len(platforms) {
+	case 0:
+		problems = append(problems, "could not detect platform (missing goos:/goarch: headers)")
+	case 1:
+		for p := range platforms {
+			platform = p
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("results span %d platforms", len(platforms)))
+	}
+
+	return platform, problems
+}
+
+// validateVersionData checks one parsed version for schema, completeness
+// against the required top-level benchmarks, plausible values, and benchmark
+// source SHA.
+func validateVersionData(vd *VersionData, expectedSHA string, required []string) []string {
+	var problems []string
+
+	// Schema: go test headers must be present
+	if vd.Metadata.System.OS == "" || vd.Metadata.System.Arch == "" || vd.Metadata.System.CPU == "" {
+		problems = append(problems, "missing goos/goarch/cpu headers")
+	}
+
+	// Source SHA: results from different benchmark code are not comparable
+	switch sha := vd.Metadata.BenchmarkSourceSHA; {
+	case sha == "":
+		problems = append(problems, "missing benchmark_source_sha (collect with an up-to-date collect_benchmarks.py)")
+	case expectedSHA != "" && sha != expectedSHA:
+		problems = append(problems, fmt.Sprintf("benchmark source %s does not match %s", shortSHA(sha), shortSHA(expectedSHA)))
+	}
+
+	// Profiled runs are slower on contended paths than the published ones
+	if vd.Metadata.ContentionProfile {
+		problems = append(problems, "collected with --contention-profile; submit a run without it")
+	}
+
+	// Completeness: every benchmark the suite defines must be present
+	present := make(map[string]bool)
+	for name := range vd.Benchmarks {
+		present[benchmarkBaseName(name)] = true
+	}
+	var missing []string
+	for _, name := range required {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		problems = append(problems, fmt.Sprintf("%d suite benchmark(s) missing: %s", len(missing), strings.Join(missing, ", ")))
+	}
+
+	// Plausibility
+	names := make([]string, 0, len(vd.Benchmarks))
+	for name := range vd.Benchmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b := vd.Benchmarks[name]
+		if b.NsPerOp <= 0 {
+			problems = append(problems, fmt.Sprintf("%s: non-positive ns/op", name))
+		}
+		if b.Samples < minIngestSamples {
+			problems = append(problems, fmt.Sprintf("%s: only %d sample(s), need at least %d", name, b.Samples, minIngestSamples))
+		}
+	}
+
+	return problems
+}
+
+// collectedPackages are the packages under the benchmarks directory that
+// every collector run covers, DEFAULT_TEST_PACKAGES in collect_benchmarks.py.
+// Others, such as core, are never collected and can't be required.
+var collectedPackages = []string{"runtime", "stdlib", "networking", "database"}
+
+// suiteBenchmarks returns the top-level benchmarks that collectedPackages in
+// benchmarksDir define, sorted. Files behind a build constraint (-tags
+// iouring, !linux, go1.26, ...) are skipped: a default collector run does
+// not build them, so contributions can't be required to include their
+// benchmarks.
+func suiteBenchmarks(benchmarksDir string) ([]string, error) {
+	var names []string
+	for _, pkg := range collectedPackages {
+		root := filepath.Join(benchmarksDir, pkg)
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				if path == root && errors.Is(err, fs.ErrNotExist) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(d.Name(), "_test.go") {
+				return nil
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if hasBuildConstraint(string(src)) {
+				return nil
+			}
+			for _, m := range benchmarkFuncPattern.FindAllStringSubmatch(string(src), -1) {
+				names = append(names, m[1])
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan benchmark sources: %w", err)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no benchmarks defined in %s", benchmarksDir)
+	}
+	sort.Strings(names)
+	return slices.Compact(names), nil
+}
+
+// hasBuildConstraint reports whether a Go source file has a //go:build line
+// before its package clause
+func hasBuildConstraint(src string) bool {
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "//go:build"):
+			return true
+		case strings.HasPrefix(line, "package "):
+			return false
+		}
+	}
+	return false
+}
+
+// benchmarkSourceSHA returns the last commit touching benchmarksDir in the
+// local checkout, matching what collect_benchmarks.py records.
+func benchmarkSourceSHA(benchmarksDir string) (string, error) {
+	out, err := exec.Command("git", "-C", benchmarksDir, "log", "-1", "--format=%H", "--", ".").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine benchmark source SHA in %s: %w", benchmarksDir, err)
+	}
+	sha := strings.TrimSpace(string(out))
+	if sha == "" {
+		return "", fmt.Errorf("no git history for %s", benchmarksDir)
+	}
+	return sha, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
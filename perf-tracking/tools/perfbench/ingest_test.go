@@ -0,0 +1,251 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSourceSHA = "0123456789abcdef0123456789abcdef01234567"
+
+// writeTestArchive builds a .tar.gz with the given name -> content entries.
+func writeTestArchive(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(files[name])),
+			ModTime: time.Date(2026, 1, 26, 21, 55, 10, 0, time.UTC),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("failed to write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatalf("failed to write entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to close gzip: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+}
+
+// go126Results is a trimmed linux-amd64 go1.26 run: five samples, spread
+// around the published medians, of a few benchmarks the suite defines
+func go126Results() string {
+	medians := []struct {
+		name        string
+		ns          float64
+		bytes, allc int
+	}{
+		{"BenchmarkAESCTR/Size1KB", 307.4, 512, 1},
+		{"BenchmarkGCThroughput", 65414.2, 155277, 2000},
+		{"BenchmarkIOReadAll/Size1KB", 532.73, 2224, 5},
+		{"BenchmarkIOReadAll/Size1MB", 485878, 2228016, 25},
+		{"BenchmarkSwissMapLarge", 10.36, 0, 0},
+		{"BenchmarkTCPConnect/Sequential", 34328.4, 988, 21},
+	}
+	var sb strings.Builder
+	sb.WriteString("goos: linux\ngoarch: amd64\npkg: github.com/astavonin/go-optimization-guide/perf-tracking/benchmarks/stdlib\n")
+	sb.WriteString("cpu: Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz\n")
+	for _, m := range medians {
+		for i := 0; i < minIngestSamples; i++ {
+			ns := m.ns * (1 + float64(i-2)/200)
+			fmt.Fprintf(&sb, "%s-8\t1000000\t%.2f ns/op\t%d B/op\t%d allocs/op\n", m.name, ns, m.bytes, m.allc)
+		}
+	}
+	sb.WriteString("PASS\n")
+	return sb.String()
+}
+
+// writeTestSuite writes benchmark sources defining the benchmarks of
+// go126Results, plus one behind a build tag, and returns their directory
+func writeTestSuite(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "benchmarks")
+	files := map[string]string{
+		"stdlib/crypto_test.go": "package stdlib\n\nimport \"testing\"\n\n" +
+			"func BenchmarkAESCTR(b *testing.B) {}\n\nfunc benchmarkHelper(b *testing.B) {}\n",
+		"stdlib/io_test.go": "package stdlib\n\nimport \"testing\"\n\nfunc BenchmarkIOReadAll(b *testing.B) {}\n",
+		"stdlib/randread_iouring_test.go": "//go:build linux && iouring\n\npackage stdlib\n\nimport \"testing\"\n\n" +
+			"func BenchmarkIOUringOnly(b *testing.B) {}\n",
+		"runtime/gc_test.go": "package runtime\n\nimport \"testing\"\n\n" +
+			"func BenchmarkGCThroughput(b *testing.B) {}\n\nfunc BenchmarkSwissMapLarge(b *testing.B) {}\n",
+		"networking/tcp_test.go":  "package networking\n\nimport \"testing\"\n\nfunc BenchmarkTCPConnect(b *testing.B) {}\n",
+		"networking/doc.go":       "package networking\n\n// func BenchmarkNotATest(b *testing.B) {}\n",
+		"core/allocation_test.go": "package core\n\nimport \"testing\"\n\nfunc BenchmarkNotCollected(b *testing.B) {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSuiteBenchmarks(t *testing.T) {
+	required, err := suiteBenchmarks(writeTestSuite(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "BenchmarkAESCTR,BenchmarkGCThroughput,BenchmarkIOReadAll,BenchmarkSwissMapLarge,BenchmarkTCPConnect"
+	if got := strings.Join(required, ","); got != want {
+		t.Errorf("suiteBenchmarks = %s, want %s", got, want)
+	}
+
+	// The real suite: registry entries no source defines any more must not
+	// be required
+	required, err = suiteBenchmarks(filepath.Join("..", "..", "benchmarks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defined := make(map[string]bool)
+	for _, name := range required {
+		defined[name] = true
+	}
+	for _, name := range []string{"BenchmarkIOReadAll", "BenchmarkGCThroughput", "BenchmarkTCPConnect"} {
+		if !defined[name] {
+			t.Errorf("%s is not found in the benchmark sources", name)
+		}
+	}
+	for _, name := range []string{"BenchmarkReadAll", "BenchmarkRegexpMatch", "BenchmarkAltGzip"} {
+		if defined[name] {
+			t.Errorf("%s is required but no suite source defines it", name)
+		}
+	}
+	// core is in the tree but the collector never runs it
+	for _, name := range []string{"BenchmarkSmallAllocation", "BenchmarkMapAllocation", "BenchmarkGCPressure"} {
+		if defined[name] {
+			t.Errorf("%s from core is required but never collected", name)
+		}
+	}
+
+	// The packages must be the ones the collector runs
+	src, err := os.ReadFile(filepath.Join("..", "collect_benchmarks.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = fmt.Sprintf("DEFAULT_TEST_PACKAGES = [\"%s\"]", strings.Join(collectedPackages, `", "`))
+	if !strings.Contains(string(src), want) {
+		t.Errorf("collect_benchmarks.py does not set %s", want)
+	}
+}
+
+func TestIngestArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "results.tar.gz")
+	sidecar := fmt.Sprintf(`{"benchmark_source_sha": %q, "system": {"logical_cores": 8}}`, testSourceSHA)
+	writeTestArchive(t, archivePath, map[string]string{
+		"linux-amd64/go1.26/2026-01-26_21-55-10.txt":           go126Results(),
+		"linux-amd64/go1.26/2026-01-26_21-55-10_metadata.json": sidecar,
+		"linux-amd64/README.md":                                "ignored",
+	})
+	required, err := suiteBenchmarks(writeTestSuite(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an output directory the archive is only validated
+	if err := ingestArchive(archivePath, "", testSourceSHA, required, ExportOptions{}); err != nil {
+		t.Fatalf("validating without export failed: %v", err)
+	}
+
+	outputDir := filepath.Join(tmpDir, "data")
+	if err := ingestArchive(archivePath, outputDir, testSourceSHA, required, ExportOptions{}); err != nil {
+		t.Fatalf("ingestArchive failed: %v", err)
+	}
+
+	for _, f := range []string{"linux-amd64/go1.26.json", "linux-amd64/index.json", "platforms.json"} {
+		if _, err := os.Stat(filepath.Join(outputDir, f)); err != nil {
+			t.Errorf("expected %s to be written: %v", f, err)
+		}
+	}
+
+	// Mismatched source SHA is rejected and nothing is exported
+	rejectedDir := filepath.Join(tmpDir, "rejected")
+	if err := ingestArchive(archivePath, rejectedDir, "ffffffffffff", required, ExportOptions{}); err == nil {
+		t.Error("expected SHA mismatch to be rejected")
+	}
+	if _, err := os.Stat(rejectedDir); !os.IsNotExist(err) {
+		t.Error("rejected contribution must not write output")
+	}
+
+	// So is a run missing a benchmark the suite defines
+	if err := ingestArchive(archivePath, "", testSourceSHA, append(required, "BenchmarkStartup"), ExportOptions{}); err == nil {
+		t.Error("expected a run missing BenchmarkStartup to be rejected")
+	}
+}
+
+func TestValidateVersionData(t *testing.T) {
+	vd := &VersionData{
+		Metadata: VersionMetadata{
+			System:            SystemInfo{OS: "linux", Arch: "amd64", CPU: "Test CPU"},
+			ContentionProfile: true,
+		},
+		Benchmarks: map[string]Benchmark{
+			"BenchmarkSmallAllocation-4": {NsPerOp: 0, Samples: 20},
+			"BenchmarkMapCreation-4":     {NsPerOp: 10, Samples: 2},
+		},
+	}
+
+	required := []string{"BenchmarkGCLatency", "BenchmarkMapCreation", "BenchmarkSmallAllocation"}
+	problems := strings.Join(validateVersionData(vd, testSourceSHA, required), "\n")
+	for _, want := range []string{
+		"missing benchmark_source_sha",
+		"collected with --contention-profile",
+		"suite benchmark(s) missing: BenchmarkGCLatency",
+		"BenchmarkSmallAllocation-4: non-positive ns/op",
+		"BenchmarkMapCreation-4: only 2 sample(s)",
+	} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("expected problem %q in:\n%s", want, problems)
+		}
+	}
+	if strings.Contains(problems, "BenchmarkSmallAllocation,") {
+		t.Errorf("present benchmark reported missing:\n%s", problems)
+	}
+	if strings.Contains(problems, "BenchmarkAltGzip") {
+		t.Errorf("optional alternatives benchmark reported missing:\n%s", problems)
+	}
+}
+
+func TestExtractArchiveRejectsTraversal(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "evil.tar.gz")
+	writeTestArchive(t, archivePath, map[string]string{"../escape.txt": "x"})
+
+	if err := extractArchive(archivePath, filepath.Join(tmpDir, "out")); err == nil {
+		t.Error("expected path traversal to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "escape.txt")); !os.IsNotExist(err) {
+		t.Error("traversal entry was written outside the destination")
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LedgerEntry is one compare run in the regression ledger, a JSONL file
+// compare -ledger appends to, one entry per line, oldest first
+type LedgerEntry struct {
+	RecordedAt          string            `json:"recorded_at"` // RFC 3339, when compare ran
+	Baseline            LedgerRun         `json:"baseline"`
+	Target              LedgerRun         `json:"target"`
+	Verdict             string            `json:"verdict"` // as in ComparisonSummary
+	GeomeanDeltaPercent float64           `json:"geomean_delta_percent"`
+	Benchmarks          []LedgerBenchmark `json:"benchmarks"` // every compared benchmark, by name
+}
+
+// LedgerRun identifies one side of a ledger entry
+type LedgerRun struct {
+	GoVersion string `json:"go_version"`
+	CommitSha string `json:"commit_sha,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"` // when the run was collected
+}
+
+// LedgerBenchmark is the verdict on one benchmark of a ledger entry
+type LedgerBenchmark struct {
+	Benchmark    string  `json:"benchmark"`
+	Verdict      string  `json:"verdict"` // "regressed", "improved" or "unchanged"
+	DeltaPercent float64 `json:"delta_percent"`
+	PValue       float64 `json:"p_value,omitempty"`
+	GateFailed   bool    `json:"gate_failed,omitempty"` // failed -fail-on-regression in ns/op or a metric
+}
+
+// benchmarkVerdict classifies c the way summarizeComparisons counts it
+func benchmarkVerdict(c Comparison) string {
+	switch {
+	case !c.significant():
+		return "unchanged"
+	case c.DeltaPercent > 0:
+		return "regressed"
+	default:
+		return "improved"
+	}
+}
+
+// newLedgerEntry records a comparison of baseline and target. commit, when
+// set, replaces the target's commit SHA, since raw results rarely carry one.
+func newLedgerEntry(baseline, target Metadata, commit string, comparisons []Comparison, failures []GateFailure, now time.Time) LedgerEntry {
+	summary := summarizeComparisons(comparisons)
+	entry := LedgerEntry{
+		RecordedAt:          now.UTC().Format(time.RFC3339),
+		Baseline:            LedgerRun{baseline.GoVersion, baseline.CommitSha, baseline.Timestamp},
+		Target:              LedgerRun{target.GoVersion, target.CommitSha, target.Timestamp},
+		Verdict:             summary.Verdict,
+		GeomeanDeltaPercent: summary.GeomeanDeltaPercent,
+	}
+	if commit != "" {
+		entry.Target.CommitSha = commit
+	}
+
+	failed := make(map[string]bool, len(failures))
+	for _, f := range failures {
+		failed[f.Benchmark] = true
+	}
+	for _, c := range comparisons {
+		b := LedgerBenchmark{
+			Benchmark:    c.Benchmark,
+			Verdict:      benchmarkVerdict(c),
+			DeltaPercent: c.DeltaPercent,
+			GateFailed:   failed[c.Benchmark],
+		}
+		if c.tested() {
+			b.PValue = c.PValue
+		}
+		entry.Benchmarks = append(entry.Benchmarks, b)
+	}
+	slices.SortFunc(entry.Benchmarks, func(a, b LedgerBenchmark) int {
+		return strings.Compare(a.Benchmark, b.Benchmark)
+	})
+	return entry
+}
+
+// appendLedger appends entry to the ledger at path as one line, creating
+// the file and its parent directories
+func appendLedger(path string, entry LedgerEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	// One write per entry, so concurrent appends don't interleave lines
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readLedger reads every entry of the ledger at path, oldest first
+func readLedger(path string) ([]LedgerEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }() // read-only
+
+	var entries []LedgerEntry
+	dec := json.NewDecoder(f)
+	for {
+		var e LedgerEntry
+		if err := dec.Decode(&e); errors.Is(err, io.EOF) {
+			return entries, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: entry %d: %w", path, len(entries)+1, err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// BenchmarkHistory is what the ledger knows about one benchmark
+type BenchmarkHistory struct {
+	Benchmark     string
+	Runs          int          // ledger entries that compared it
+	Regressions   int          // of which it regressed
+	LastRegressed *LedgerEntry // newest entry it regressed in, nil if never
+	LastDelta     float64      // its ns/op change in LastRegressed
+	RunsSince     int          // entries comparing it after LastRegressed
+}
+
+// benchmarkHistories summarizes entries per benchmark passing filter,
+// most recently regressed first, then those that never regressed by name
+func benchmarkHistories(entries []LedgerEntry, filter BenchmarkFilter) []BenchmarkHistory {
+	byName := make(map[string]*BenchmarkHistory)
+	for i := range entries {
+		for _, b := range entries[i].Benchmarks {
+			if !filter.Match(b.Benchmark) {
+				continue
+			}
+			h := byName[b.Benchmark]
+			if h == nil {
+				h = &BenchmarkHistory{Benchmark: b.Benchmark}
+				byName[b.Benchmark] = h
+			}
+			h.Runs++
+			h.RunsSince++
+			if b.Verdict == "regressed" {
+				h.Regressions++
+				h.LastRegressed = &entries[i]
+				h.LastDelta = b.DeltaPercent
+				h.RunsSince = 0
+			}
+		}
+	}
+
+	histories := make([]BenchmarkHistory, 0, len(byName))
+	for _, h := range byName {
+		histories = append(histories, *h)
+	}
+	slices.SortFunc(histories, func(a, b BenchmarkHistory) int {
+		switch {
+		case a.LastRegressed == nil && b.LastRegressed == nil:
+		case a.LastRegressed == nil:
+			return 1
+		case b.LastRegressed == nil:
+			return -1
+		default:
+			if c := cmp.Compare(b.LastRegressed.RecordedAt, a.LastRegressed.RecordedAt); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.Benchmark, b.Benchmark)
+	})
+	return histories
+}
+
+// ledgerRunLabel names a ledger run by Go version and short commit
+func ledgerRunLabel(r LedgerRun) string {
+	label := r.GoVersion
+	if sha := r.CommitSha; sha != "" {
+		label += "@" + sha[:min(len(sha), 12)]
+	}
+	return label
+}
+
+// printHistory prints when each benchmark last regressed, and with events
+// every regression of the selected benchmarks in ledger order instead
+func printHistory(w io.Writer, entries []LedgerEntry, filter BenchmarkFilter, events bool) {
+	if events {
+		fmt.Fprintf(w, "%-20s %-30s %9s  %s\n", "Recorded", "Benchmark", "Change", "Baseline -> Target")
+		for _, e := range entries {
+			for _, b := range e.Benchmarks {
+				if b.Verdict == "regressed" && filter.Match(b.Benchmark) {
+					fmt.Fprintf(w, "%-20s %-30s %+8.1f%%  %s -> %s\n", e.RecordedAt, b.Benchmark, b.DeltaPercent,
+						ledgerRunLabel(e.Baseline), ledgerRunLabel(e.Target))
+				}
+			}
+		}
+		return
+	}
+
+	histories := benchmarkHistories(entries, filter)
+	fmt.Fprintf(w, "%d comparison runs in the ledger\n\n", len(entries))
+	fmt.Fprintf(w, "%-30s %-20s %9s %-24s %11s %6s\n", "Benchmark", "Last regressed", "Change", "Target", "Regressions", "Since")
+	never := 0
+	for _, h := range histories {
+		if h.LastRegressed == nil {
+			never++
+			continue
+		}
+		fmt.Fprintf(w, "%-30s %-20s %+8.1f%% %-24s %5d of %-2d %6d\n", h.Benchmark, h.LastRegressed.RecordedAt,
+			h.LastDelta, ledgerRunLabel(h.LastRegressed.Target), h.Regressions, h.Runs, h.RunsSince)
+	}
+	if never > 0 {
+		fmt.Fprintf(w, "\n%d benchmarks never regressed\n", never)
+	}
+}
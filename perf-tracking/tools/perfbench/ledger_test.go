@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLedgerRoundTrip(t *testing.T) {
+	base := extractBenchmarks([]string{
+		"BenchmarkMapIteration-8 \t 1000\t 100 ns/op",
+		"BenchmarkGCLatency-8 \t 1000\t 100 ns/op",
+	})
+	target := extractBenchmarks([]string{
+		"BenchmarkMapIteration-8 \t 1000\t 120 ns/op",
+		"BenchmarkGCLatency-8 \t 1000\t 101 ns/op",
+	})
+	comparisons := compareResults(base, target, ratioPolicy{})
+	failures := []GateFailure{{Benchmark: "BenchmarkMapIteration", Metric: "ns/op", DeltaPercent: 20, Threshold: 5}}
+
+	path := filepath.Join(t.TempDir(), "perf", "regressions.jsonl")
+	now := time.Date(2025, 8, 12, 10, 0, 0, 0, time.UTC)
+	for i, commit := range []string{"", "0123456789abcdef"} {
+		entry := newLedgerEntry(Metadata{GoVersion: "1.24"}, Metadata{GoVersion: "1.25", CommitSha: "fromresult"},
+			commit, comparisons, failures, now.Add(time.Duration(i)*time.Hour))
+		if err := appendLedger(path, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := readLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("read %d entries, want 2", len(entries))
+	}
+	if got := entries[0].Target.CommitSha; got != "fromresult" {
+		t.Errorf("target commit without -ledger-commit = %q, want fromresult", got)
+	}
+	if got := entries[1].Target.CommitSha; got != "0123456789abcdef" {
+		t.Errorf("target commit with -ledger-commit = %q", got)
+	}
+	e := entries[1]
+	if e.RecordedAt != "2025-08-12T11:00:00Z" || e.Verdict != "regressed" || len(e.Benchmarks) != 2 {
+		t.Fatalf("entry = %+v", e)
+	}
+	// Sorted by name; single samples are judged by threshold alone
+	gc, mapIter := e.Benchmarks[0], e.Benchmarks[1]
+	if gc.Benchmark != "BenchmarkGCLatency" || gc.Verdict != "unchanged" || gc.GateFailed {
+		t.Errorf("GCLatency = %+v", gc)
+	}
+	if mapIter.Verdict != "regressed" || !mapIter.GateFailed || mapIter.DeltaPercent != 20 || mapIter.PValue != 0 {
+		t.Errorf("MapIteration = %+v", mapIter)
+	}
+
+	if err := os.WriteFile(path, []byte("{\"verdict\":\"neutral\"}\n{not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readLedger(path); err == nil || !strings.Contains(err.Error(), "entry 2") {
+		t.Errorf("readLedger of a corrupt ledger: %v, want an error naming entry 2", err)
+	}
+}
+
+func TestBenchmarkHistories(t *testing.T) {
+	entry := func(at, commit string, verdicts map[string]string) LedgerEntry {
+		e := LedgerEntry{RecordedAt: at, Target: LedgerRun{GoVersion: "1.25", CommitSha: commit}}
+		for _, name := range []string{"BenchmarkGCLatency", "BenchmarkMapIteration", "BenchmarkJSONDecode"} {
+			if v, ok := verdicts[name]; ok {
+				e.Benchmarks = append(e.Benchmarks, LedgerBenchmark{Benchmark: name, Verdict: v, DeltaPercent: 7})
+			}
+		}
+		return e
+	}
+	entries := []LedgerEntry{
+		entry("2025-08-01T00:00:00Z", "aaa", map[string]string{
+			"BenchmarkGCLatency": "regressed", "BenchmarkMapIteration": "regressed", "BenchmarkJSONDecode": "unchanged",
+		}),
+		entry("2025-08-02T00:00:00Z", "bbb", map[string]string{
+			"BenchmarkGCLatency": "improved", "BenchmarkMapIteration": "regressed",
+		}),
+		entry("2025-08-03T00:00:00Z", "ccc", map[string]string{
+			"BenchmarkGCLatency": "unchanged", "BenchmarkMapIteration": "unchanged", "BenchmarkJSONDecode": "improved",
+		}),
+	}
+
+	histories := benchmarkHistories(entries, BenchmarkFilter{})
+	if len(histories) != 3 {
+		t.Fatalf("got %d histories, want 3", len(histories))
+	}
+	want := []struct {
+		name                     string
+		runs, regressions, since int
+		commit                   string
+	}{
+		{"BenchmarkMapIteration", 3, 2, 1, "bbb"},
+		{"BenchmarkGCLatency", 3, 1, 2, "aaa"},
+		{"BenchmarkJSONDecode", 2, 0, 2, ""},
+	}
+	for i, w := range want {
+		h := histories[i]
+		commit := ""
+		if h.LastRegressed != nil {
+			commit = h.LastRegressed.Target.CommitSha
+		}
+		if h.Benchmark != w.name || h.Runs != w.runs || h.Regressions != w.regressions || h.RunsSince != w.since || commit != w.commit {
+			t.Errorf("histories[%d] = %+v (commit %q), want %+v", i, h, commit, w)
+		}
+	}
+
+	filter, err := parseBenchmarkFilter("GC", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printHistory(&out, entries, filter, true)
+	if got := strings.Count(out.String(), "BenchmarkGCLatency"); got != 1 || strings.Contains(out.String(), "MapIteration") {
+		t.Errorf("-events -bench GC output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,623 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// command is a perfbench subcommand. run gets the arguments after the
+// command name and exits the process on failure.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands are the perfbench subcommands in the order the usage lists them
+var commands = []command{
+	{"compare", "Compare a target run with a baseline, or several runs as a matrix", runCompare},
+	{"export", "Export raw go test results as web JSON, one file or a results directory", runExport},
+	{"index", "Recompute statistics and index.json from archived raw results", runIndex},
+	{"validate", "Validate a contributed results archive and optionally export it", runValidate},
+	{"benchfmt", "Convert a result to the Go benchmark format for benchstat", runBenchfmt},
+	{"history", "Show when each benchmark last regressed, from a compare -ledger file", runHistory},
+	{"snippets", "Generate per-benchmark JSON and markdown fragments for the guide's articles", runSnippets},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(1)
+	}
+	name, args := os.Args[1], os.Args[2:]
+	switch name {
+	case "-h", "-help", "--help":
+		usage(os.Stdout)
+		return
+	case "help":
+		// help <command> prints the command's flags
+		if len(args) == 0 {
+			usage(os.Stdout)
+			return
+		}
+		name, args = args[0], []string{"-h"}
+	}
+	for _, c := range commands {
+		if c.name == name {
+			c.run(args)
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "perfbench: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(1)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: perfbench <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'perfbench <command> -h' for the flags of a command.")
+}
+
+// newFlagSet returns the flag set of a subcommand. Its usage lists the
+// synopses, one invocation per element, then description and the flags.
+func newFlagSet(name, description string, synopses ...string) *flag.FlagSet {
+	fs := flag.NewFlagSet("perfbench "+name, flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintln(out, "Usage:")
+		for _, s := range synopses {
+			fmt.Fprintf(out, "  perfbench %s %s\n", name, s)
+		}
+		fmt.Fprintf(out, "\n%s\n\nFlags:\n", description)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// missingArgs prints the usage of fs and exits when a required flag is
+// empty
+func missingArgs(fs *flag.FlagSet, required ...string) {
+	for _, v := range required {
+		if v == "" {
+			fs.Usage()
+			os.Exit(1)
+		}
+	}
+}
+
+// addFilterFlags registers -bench and -category on fs. The returned function
+// parses them once fs is parsed, exiting on an invalid value.
+func addFilterFlags(fs *flag.FlagSet, verb string) func() BenchmarkFilter {
+	benchPattern := fs.String("bench", "", "Only "+verb+" benchmarks whose full name matches this regexp, e.g. 'BenchmarkTLS.*'")
+	categories := fs.String("category", "", "Only "+verb+" benchmarks in these comma-separated categories: runtime, stdlib, networking, alternatives, uncategorized")
+	return func() BenchmarkFilter {
+		filter, err := parseBenchmarkFilter(*benchPattern, *categories)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return filter
+	}
+}
+
+// addExportFlags registers the ExportOptions flags on fs, shared by export,
+// index and validate. The returned function resolves them once fs is
+// parsed, exiting on an invalid value.
+func addExportFlags(fs *flag.FlagSet) func() ExportOptions {
+	cpuOverride := fs.String("cpu", "", "CPU identifier used as fallback when benchmark files lack a cpu: line")
+	onDuplicate := fs.String("on-duplicate", string(DuplicateKeepNewest), "How to resolve JSON files claiming the same version: keep-newest, merge (pool samples) or error")
+	strict := fs.Bool("strict", false, "Fail the export when plausibility checks flag a benchmark")
+	anonymize := fs.Bool("anonymize", false, "Strip hostnames, usernames in paths and serial-like ids from exported metadata")
+	keepRaw := fs.Int("keep-raw", 0, "Archive the N newest raw result files per version under <platform>/raw/ and reference them from the JSON")
+	repoURL := fs.String("repo-url", defaultRepoURL, "Repository web URL used for benchmark source links in index.json")
+	repoBranch := fs.String("repo-branch", defaultRepoBranch, "Branch substituted for {branch} in -source-path")
+	sourcePath := fs.String("source-path", defaultSourcePath, "Path between -repo-url and source files, e.g. \"-/blob/{branch}\" for GitLab")
+	filter := addFilterFlags(fs, "export")
+	return func() ExportOptions {
+		duplicatePolicy, err := parseDuplicatePolicy(*onDuplicate)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts := ExportOptions{
+			CPUFallback: *cpuOverride,
+			Anonymize:   *anonymize,
+			Strict:      *strict,
+			OnDuplicate: duplicatePolicy,
+			Repository:  RepoOptions{URL: *repoURL, Branch: *repoBranch, SourcePath: *sourcePath},
+			KeepRaw:     *keepRaw,
+			Filter:      filter(),
+		}
+		if _, err := opts.Repository.repositoryInfo(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return opts
+	}
+}
+
+func runExport(args []string) {
+	fs := newFlagSet("export",
+		"Exports raw go test output as the web JSON the dashboard reads. With -input, one file is\n"+
+			"exported as -version to -output. With -results-dir, every go<version>/ directory in it is\n"+
+			"exported under -output-dir/<platform>/ and the platform's index.json is rebuilt.",
+		"-input <file> -version <ver> -output <file> [flags]",
+		"-results-dir <dir> -output-dir <dir> [flags]")
+	input := fs.String("input", "", "Raw benchmark .txt file to export")
+	version := fs.String("version", "", "Go version string of -input")
+	output := fs.String("output", "", "Output JSON file for -input")
+	resultsDir := fs.String("results-dir", "", "Results directory with go<version>/ subdirectories to export")
+	outputDir := fs.String("output-dir", "", "Web data directory for -results-dir")
+	platform := fs.String("platform", "linux-amd64", "Platform identifier used when auto-detection from files fails (with -results-dir)")
+	exportOpts := addExportFlags(fs)
+	_ = fs.Parse(args)
+
+	if *resultsDir != "" {
+		if *input != "" {
+			fmt.Println("Error: -input and -results-dir are mutually exclusive")
+			os.Exit(1)
+		}
+		missingArgs(fs, *outputDir)
+		if err := exportAll(*resultsDir, *outputDir, *platform, exportOpts()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	missingArgs(fs, *input, *version, *output)
+	if err := exportVersion(*input, *version, *output, exportOpts()); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runIndex(args []string) {
+	fs := newFlagSet("index",
+		"Recomputes the statistics of every version and rebuilds index.json from the raw results\n"+
+			"archived under <platform>/raw/ of an existing web data tree (see export -keep-raw), so\n"+
+			"changes to the analysis apply to history without re-running benchmarks.",
+		"-data-dir <dir> -output-dir <dir> [flags]")
+	dataDir := fs.String("data-dir", "", "Existing web data tree with <platform>/raw archives")
+	outputDir := fs.String("output-dir", "", "Directory the recomputed tree is written to")
+	exportOpts := addExportFlags(fs)
+	_ = fs.Parse(args)
+
+	missingArgs(fs, *dataDir, *outputDir)
+	if err := reanalyze(*dataDir, *outputDir, exportOpts()); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runSnippets(args []string) {
+	fs := newFlagSet("snippets",
+		"Renders each benchmark's latest release result and its change from the release before\n"+
+			"into snippets.json, keyed by benchmark name, and one markdown fragment per benchmark\n"+
+			"under -output-dir/<platform>/, for the docs build to embed instead of pasted numbers.",
+		"-data-dir <dir> -output-dir <dir> [flags]")
+	dataDir := fs.String("data-dir", "", "Web data tree with platforms.json, as export -results-dir writes it")
+	outputDir := fs.String("output-dir", "", "Directory the snippets are written to")
+	platform := fs.String("platform", "", "Only generate snippets for this platform, e.g. linux-amd64 (default: all)")
+	parseFilter := addFilterFlags(fs, "render")
+	_ = fs.Parse(args)
+	filter := parseFilter()
+
+	missingArgs(fs, *dataDir, *outputDir)
+	if err := generateSnippets(*dataDir, *outputDir, *platform, filter); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runValidate(args []string) {
+	fs := newFlagSet("validate",
+		"Checks a contributed results archive: its layout, that every run used the expected\n"+
+			"benchmark sources, and the plausibility of the results. With -output-dir, an accepted\n"+
+			"archive is also exported into the platform layout and the index is rebuilt.",
+		"-archive <file> [-output-dir <dir>] [flags]")
+	archive := fs.String("archive", "", "Contributed results archive, .tar.gz or .zip")
+	outputDir := fs.String("output-dir", "", "Web data directory to export an accepted archive to")
+	sourceSHA := fs.String("source-sha", "", "Expected benchmark source SHA (default: last commit touching -benchmarks-dir)")
+	benchmarksDir := fs.String("benchmarks-dir", "../../benchmarks", "Benchmark sources: the expected SHA and the benchmarks every run must include")
+	exportOpts := addExportFlags(fs)
+	_ = fs.Parse(args)
+
+	missingArgs(fs, *archive)
+	expected := *sourceSHA
+	if expected == "" {
+		sha, err := benchmarkSourceSHA(*benchmarksDir)
+		if err != nil {
+			fmt.Printf("Error: %v (pass -source-sha explicitly)\n", err)
+			os.Exit(1)
+		}
+		expected = sha
+	}
+	required, err := suiteBenchmarks(*benchmarksDir)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ingestArchive(*archive, *outputDir, expected, required, exportOpts()); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runBenchfmt(args []string) {
+	fs := newFlagSet("benchfmt",
+		"Writes a result JSON (or raw go test output) as a Go benchmark format file, with the\n"+
+			"metadata as configuration lines, for benchstat and the other golang.org/x/perf tools.",
+		"-input <file> -output <file>")
+	input := fs.String("input", "", "Result JSON or raw go test output")
+	output := fs.String("output", "", "Benchmark format file to write")
+	_ = fs.Parse(args)
+
+	missingArgs(fs, *input, *output)
+	if err := convertToBenchfmt(*input, *output); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runHistory(args []string) {
+	fs := newFlagSet("history",
+		"Reads a regression ledger written by compare -ledger and shows, for every benchmark,\n"+
+			"when it last regressed, against which target, and how many runs have passed since.",
+		"-ledger <file> [flags]")
+	ledger := fs.String("ledger", "", "Regression ledger (JSONL) written by compare -ledger")
+	events := fs.Bool("events", false, "List every regression in the order it was recorded instead")
+	parseFilter := addFilterFlags(fs, "show")
+	_ = fs.Parse(args)
+	filter := parseFilter()
+
+	missingArgs(fs, *ledger)
+	entries, err := readLedger(*ledger)
+	if err != nil {
+		fmt.Printf("Error reading ledger: %v\n", err)
+		os.Exit(1)
+	}
+	printHistory(os.Stdout, entries, filter, *events)
+}
+
+func runCompare(args []string) {
+	fs := newFlagSet("compare",
+		"Compares a target run with a baseline, each a result JSON or raw go test output, and\n"+
+			"reports the change of every benchmark both ran. With -inputs, any number of runs are\n"+
+			"compared against the first as a matrix.",
+		"-baseline <file>[,<file>...]|-baseline-latest <results-dir> -target <file>[,<file>...] [flags]",
+		"-inputs <file>,<file>[,<file>...] [flags]")
+	baseline := fs.String("baseline", "", "Baseline result: JSON or raw go test output, or comma-separated runs of one configuration whose samples are pooled, e.g. run1.txt,run2.txt,run3.txt")
+	baselineLatest := fs.String("baseline-latest", "", "Results directory (as for export -results-dir) whose newest Go version's latest run is the baseline, instead of -baseline")
+	target := fs.String("target", "", "Target result: JSON or raw go test output, or comma-separated runs to pool like -baseline")
+	inputs := fs.String("inputs", "", "Comma-separated result files to compare against the first as a matrix, e.g. go1.23.json,go1.24.json,go1.25.json")
+	output := fs.String("output", "", "Output comparison file (JSON)")
+	summaryJSON := fs.String("summary-json", "", "Write a compact verdict (counts, worst regression, geomean) to this file")
+	ledger := fs.String("ledger", "", "Append this run, with both commits and every benchmark's verdict, to a JSONL regression ledger (see history)")
+	ledgerCommit := fs.String("ledger-commit", "", "Commit SHA recorded for the target in -ledger, e.g. $GITHUB_SHA (default: the target's commit_sha)")
+	noColor := fs.Bool("no-color", false, "Disable ANSI colors in the comparison table")
+	sortBy := fs.String("sort", sortName, "Order of the comparison table: delta (largest change first), name or category")
+	display := fs.String("unit", displayNsPerOp, "Unit of the Baseline and Target columns for -format text or markdown: ns (time per op) or ops (operations per second)")
+	top := fs.Int("top", 0, "Show only the N benchmarks with the largest ns/op change, as -delta measures it, in the comparison table (0: all)")
+	deltaPolicy := fs.String("delta", policyRatio, "How ns/op changes are measured and shown: ratio (percent of baseline), difference (target minus baseline) or log (log2 of target/baseline)")
+	ascii := fs.Bool("ascii", false, "Draw delta bars with ASCII characters instead of unicode blocks")
+	force := fs.Bool("force", false, "Compare even if baseline and target were collected on different machines")
+	format := fs.String("format", formatText, "Comparison output format: text, markdown (GitHub-flavored, for PR comments), gh-annotations (GitHub Actions workflow commands), csv or jsonl (one comparison per line)")
+	sourceRoot := fs.String("source-root", "../../..", "Repository root that benchmark source files are resolved against (for -format gh-annotations)")
+	failOnRegression := fs.Bool("fail-on-regression", false, "Exit with status 2 when a benchmark is significantly slower than baseline beyond its threshold")
+	threshold := fs.String("threshold", "5%", "Default regression threshold for -fail-on-regression, e.g. 5% or 2.5")
+	thresholdsFile := fs.String("thresholds", "", "JSON file with per-category and per-benchmark regression thresholds (for -fail-on-regression)")
+	minEffect := fs.String("min-effect", "0", "Default minimum ns/op slowdown for -fail-on-regression, e.g. 2ns or 1.5us, on top of the percentage threshold (0: none)")
+	noise := fs.String("noise", "", "index.json from export -results-dir whose per-benchmark max CV sets a noise band; changes within it are not significant")
+	metricThresholds := fs.String("metric-thresholds", "", "Also gate B/op, allocs/op, MB/s or custom metrics, e.g. allocs/op=0,B/op=10%,p99-ns=20% (for -fail-on-regression; overrides -thresholds)")
+	expectationsFile := fs.String("check-expectations", "", "Expectations JSON listing documented improvements; report which of them the comparison shows")
+	parseFilter := addFilterFlags(fs, "compare")
+	_ = fs.Parse(args)
+	filter := parseFilter()
+
+	switch *format {
+	case formatText, formatMarkdown, formatGitHubAnnotations, formatCSV, formatJSONL:
+	default:
+		fmt.Printf("Error: invalid -format %q (want text, markdown, gh-annotations, csv or jsonl)\n", *format)
+		os.Exit(1)
+	}
+	order, err := parseSortOrder(*sortBy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *top < 0 {
+		fmt.Printf("Error: invalid -top %d (want 0 or more)\n", *top)
+		os.Exit(1)
+	}
+	policy, err := parseComparisonPolicy(*deltaPolicy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *inputs != "" && policy.name() != policyRatio {
+		fmt.Println("Error: -inputs compares by ratio only")
+		os.Exit(1)
+	}
+	switch *display {
+	case displayNsPerOp:
+	case displayOpsPerSec:
+		if *inputs != "" || (*format != formatText && *format != formatMarkdown) {
+			fmt.Println("Error: -unit ops supports a -baseline/-target comparison with -format text or markdown")
+			os.Exit(1)
+		}
+	default:
+		fmt.Printf("Error: invalid -unit %q (want ns or ops)\n", *display)
+		os.Exit(1)
+	}
+
+	var bands map[string]float64
+	if *noise != "" {
+		if bands, err = loadNoiseBands(*noise); err != nil {
+			fmt.Printf("Error reading noise bands: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// N-way comparison against the first input
+	if *inputs != "" {
+		if *baseline != "" || *baselineLatest != "" || *target != "" || *failOnRegression || *summaryJSON != "" || *ledger != "" || *expectationsFile != "" {
+			fmt.Println("Error: -inputs cannot be combined with -baseline, -baseline-latest, -target, -fail-on-regression, -summary-json, -ledger or -check-expectations")
+			os.Exit(1)
+		}
+		if *format != formatText && *format != formatMarkdown {
+			fmt.Println("Error: -inputs supports -format text or markdown")
+			os.Exit(1)
+		}
+		var paths []string
+		for p := range strings.SplitSeq(*inputs, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				paths = append(paths, p)
+			}
+		}
+		if err := compareMatrix(paths, filter, bands, *format, *output, *force); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *baseline != "" && *baselineLatest != "" {
+		fmt.Println("Error: -baseline and -baseline-latest are mutually exclusive")
+		os.Exit(1)
+	}
+	if (*baseline == "" && *baselineLatest == "") || *target == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Markdown, annotations, CSV and JSONL on stdout are meant to be consumed
+	// as is, so progress and gate messages go to stderr
+	status := io.Writer(os.Stdout)
+	if *format != formatText {
+		status = os.Stderr
+	}
+
+	// Resolve thresholds up front so a bad config fails before the comparison
+	var thresholds RegressionThresholds
+	if *failOnRegression {
+		def, err := parseThresholdPercent(*threshold)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		minNs, err := parseMinEffect(*minEffect)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		thresholds = RegressionThresholds{Default: def, MinEffectNs: MinEffects{Default: minNs}}
+		if *thresholdsFile != "" {
+			if thresholds, err = loadThresholds(*thresholdsFile, def, minNs); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *metricThresholds != "" {
+			metrics, err := parseMetricThresholds(*metricThresholds)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if thresholds.Metrics == nil {
+				thresholds.Metrics = make(map[string]float64)
+			}
+			maps.Copy(thresholds.Metrics, metrics)
+		}
+	}
+
+	var expectations []Expectation
+	if *expectationsFile != "" {
+		if expectations, err = loadExpectations(*expectationsFile); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var latestVersion string
+	if *baselineLatest != "" {
+		targetFile, _, _ := strings.Cut(*target, ",")
+		if *baseline, latestVersion, err = latestBaseline(*baselineLatest, targetFile); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(status, "Baseline: go%s (%s)\n", latestVersion, *baseline)
+	}
+
+	// Read baseline and target, each either a result JSON or raw go test
+	// output, pooling the samples of several runs of one side
+	baseResult, err := loadPooledResults(*baseline)
+	if err != nil {
+		fmt.Printf("Error reading baseline: %v\n", err)
+		os.Exit(1)
+	}
+	// A raw result without a go-version line is named after its file; the
+	// directory it was found in names its version better
+	if latestVersion != "" && baseResult.Metadata.GoVersion == filepath.Base(*baseline) {
+		baseResult.Metadata.GoVersion = latestVersion
+	}
+	targetResult, err := loadPooledResults(*target)
+	if err != nil {
+		fmt.Printf("Error reading target: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Cross-machine comparisons measure the hardware, not the change
+	if mismatches := machineMismatches(baseResult, targetResult); len(mismatches) > 0 {
+		label := "Error"
+		if *force {
+			label = "Warning"
+		}
+		fmt.Fprintf(status, "%s: baseline and target were collected on different machines:\n", label)
+		for _, m := range mismatches {
+			fmt.Fprintf(status, "  - %s\n", m)
+		}
+		if !*force {
+			fmt.Fprintln(status, "Use -force to compare anyway.")
+			os.Exit(1)
+		}
+	}
+	// So do comparisons of a profiled run with a normal one
+	if baseResult.Metadata.ContentionProfile != targetResult.Metadata.ContentionProfile {
+		label := "Error"
+		if *force {
+			label = "Warning"
+		}
+		fmt.Fprintf(status, "%s: contention profiling (--contention-profile) is %s in the baseline but %s in the target;\n",
+			label, onOff(baseResult.Metadata.ContentionProfile), onOff(targetResult.Metadata.ContentionProfile))
+		fmt.Fprintln(status, "  it slows contended paths, so ns/op differences include its overhead")
+		if !*force {
+			fmt.Fprintln(status, "Use -force to compare anyway.")
+			os.Exit(1)
+		}
+	}
+
+	// Extract benchmark statistics
+	baseStats := extractBenchmarks(baseResult.Benchmarks)
+	targetStats := extractBenchmarks(targetResult.Benchmarks)
+	filterBenchmarks(baseStats, filter)
+	filterBenchmarks(targetStats, filter)
+
+	// Compare
+	comparisons := compareResults(baseStats, targetStats, policy)
+	applyNoiseBands(comparisons, bands)
+	drift := benchmarkDrift(baseStats, targetStats)
+	sortComparisons(comparisons, order)
+
+	var failures []GateFailure
+	if *failOnRegression {
+		failures = gateRegressions(comparisons, thresholds)
+	}
+
+	// Print results
+	switch *format {
+	case formatMarkdown:
+		writeMarkdownComparison(os.Stdout, comparisons, baseResult.Metadata, targetResult.Metadata, *display)
+		writeMarkdownDrift(os.Stdout, drift)
+	case formatGitHubAnnotations:
+		writeGitHubAnnotations(os.Stdout, comparisons, failures, newSourceLocator(*sourceRoot))
+	case formatCSV:
+		if err := writeCSVComparison(os.Stdout, comparisons); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+			os.Exit(1)
+		}
+	case formatJSONL:
+		if err := writeJSONLComparison(os.Stdout, comparisons); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSONL: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		style := barStyle{ASCII: *ascii, Color: !*noColor && colorSupported()}
+		printComparisons(comparisons, baseResult.Metadata, targetResult.Metadata, style, *top, *display)
+		printDrift(drift)
+	}
+	if *expectationsFile != "" {
+		results, skipped := checkExpectations(expectations, comparisons, baseResult.Metadata, targetResult.Metadata)
+		printExpectations(status, results, skipped, baseResult.Metadata, targetResult.Metadata)
+	}
+
+	// Save to file if requested
+	if *output != "" {
+		outputData := struct {
+			Baseline    Metadata     `json:"baseline"`
+			Target      Metadata     `json:"target"`
+			Comparisons []Comparison `json:"comparisons"`
+			BenchmarkDrift
+		}{
+			Baseline:       baseResult.Metadata,
+			Target:         targetResult.Metadata,
+			Comparisons:    comparisons,
+			BenchmarkDrift: drift,
+		}
+
+		jsonData, err := json.MarshalIndent(outputData, "", "  ")
+		if err != nil {
+			fmt.Printf("Error generating JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Create output directory if needed
+		if err := os.MkdirAll(filepath.Dir(*output), 0755); err != nil {
+			fmt.Printf("Error creating output directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := os.WriteFile(*output, jsonData, 0644); err != nil {
+			fmt.Printf("Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(status, "\nComparison saved to: %s\n", *output)
+	}
+
+	if *summaryJSON != "" {
+		if err := writeSummary(*summaryJSON, summarizeComparisons(comparisons)); err != nil {
+			fmt.Printf("Error writing summary: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(status, "Summary saved to: %s\n", *summaryJSON)
+	}
+
+	if *ledger != "" {
+		entry := newLedgerEntry(baseResult.Metadata, targetResult.Metadata, *ledgerCommit, comparisons, failures, time.Now())
+		if err := appendLedger(*ledger, entry); err != nil {
+			fmt.Printf("Error appending to ledger: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(status, "Appended to ledger: %s\n", *ledger)
+	}
+
+	if *failOnRegression {
+		if len(failures) > 0 {
+			fmt.Fprintf(status, "\nFAIL: %d benchmark(s) regressed beyond threshold:\n", len(failures))
+			for _, f := range failures {
+				if f.MinEffectNs > 0 {
+					fmt.Fprintf(status, "  - %s %s: %+.1f%%, %+.2f ns (threshold %.1f%%, minimum %.2f ns)\n",
+						f.Benchmark, f.Metric, f.DeltaPercent, f.EffectNs, f.Threshold, f.MinEffectNs)
+					continue
+				}
+				fmt.Fprintf(status, "  - %s %s: %+.1f%% (threshold %.1f%%)\n", f.Benchmark, f.Metric, f.DeltaPercent, f.Threshold)
+			}
+			os.Exit(exitRegression)
+		}
+		fmt.Fprintln(status, "\nPASS: no regressions beyond threshold")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// significanceLevel is the p-value below which a comparison is reported as
+// statistically significant
+const significanceLevel = 0.05
+
+// exactMannWhitneyLimit is the largest combined sample count for which
+// mannWhitneyU enumerates the exact distribution of U instead of using the
+// normal approximation
+const exactMannWhitneyLimit = 50
+
+// mannWhitneyU runs a two-sided Mann-Whitney U test on two independent
+// samples and returns the p-value of the hypothesis that both come from the
+// same distribution. Unlike a t-test it assumes nothing about the shape of
+// the distribution, which suits benchmark timings with their long tail of
+// slow outliers. Without ties the p-value is exact for small samples;
+// otherwise it uses the normal approximation with tie and continuity
+// correction. Returns 1 when either sample is empty.
+func mannWhitneyU(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	// Rank the pooled samples, giving tied values their average rank
+	type value struct {
+		v     float64
+		fromX bool
+	}
+	pooled := make([]value, 0, n1+n2)
+	for _, v := range x {
+		pooled = append(pooled, value{v, true})
+	}
+	for _, v := range y {
+		pooled = append(pooled, value{v, false})
+	}
+	sort.Slice(pooled, func(i, j int) bool { return pooled[i].v < pooled[j].v })
+
+	var rankSumX, tieTerm float64
+	ties := false
+	for i := 0; i < len(pooled); {
+		j := i
+		for j < len(pooled) && pooled[j].v == pooled[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // ranks i+1..j averaged
+		for k := i; k < j; k++ {
+			if pooled[k].fromX {
+				rankSumX += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+
+	u := rankSumX - float64(n1*(n1+1))/2
+	if !ties && n1+n2 <= exactMannWhitneyLimit {
+		return exactMannWhitneyP(n1, n2, u)
+	}
+
+	m1, m2 := float64(n1), float64(n2)
+	n := m1 + m2
+	mean := m1 * m2 / 2
+	variance := m1 * m2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1 // every value is the same
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactMannWhitneyP returns the two-sided p-value of U for samples of n1 and
+// n2 values without ties, counting the rank assignments that give each U.
+func exactMannWhitneyP(n1, n2 int, u float64) float64 {
+	maxU := n1 * n2
+	// counts[i][u] is the number of ways i values of x and j of y (for the
+	// current j) produce U == u; built up one y value at a time
+	counts := make([][]float64, n1+1)
+	for i := range counts {
+		counts[i] = make([]float64, maxU+1)
+	}
+	for i := range counts {
+		counts[i][0] = 1
+	}
+	for j := 1; j <= n2; j++ {
+		next := make([][]float64, n1+1)
+		next[0] = make([]float64, maxU+1)
+		next[0][0] = 1
+		for i := 1; i <= n1; i++ {
+			next[i] = make([]float64, maxU+1)
+			for v := 0; v <= maxU; v++ {
+				// The largest value is from x (adds j to U) or from y
+				if v >= j {
+					next[i][v] += next[i-1][v-j]
+				}
+				next[i][v] += counts[i][v]
+			}
+		}
+		counts = next
+	}
+
+	dist := counts[n1]
+	var total float64
+	for _, c := range dist {
+		total += c
+	}
+	// U is symmetric around n1*n2/2: take the tail beyond the observed
+	// distance from the center on both sides
+	lo := math.Min(u, float64(maxU)-u)
+	var tail float64
+	for v := 0; float64(v) <= lo; v++ {
+		tail += dist[v]
+	}
+	return math.Min(1, 2*tail/total)
+}
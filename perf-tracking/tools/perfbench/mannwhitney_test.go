@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		// U = 0: 2 of the C(10,5) = 252 rank assignments are as extreme
+		{"separated exact", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252},
+		// U = 1: 4 of 252
+		{"one overlap exact", []float64{1, 2, 3, 4, 6}, []float64{5, 7, 8, 9, 10}, 4.0 / 252},
+		// U = 6: P(U <= 6) = 24/70 on each side
+		{"interleaved exact", []float64{1, 3, 5, 7}, []float64{2, 4, 6, 8}, 48.0 / 70},
+		// With ties: normal approximation, z = (12.5-0.5)/sqrt(22.5) ≈ 2.53
+		{"separated with ties", []float64{1, 1, 2, 2, 3}, []float64{4, 4, 5, 5, 6}, 0.0114},
+		{"identical", []float64{5, 5, 5}, []float64{5, 5, 5}, 1},
+		{"empty", nil, []float64{1, 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mannWhitneyU(tt.x, tt.y)
+			if math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("mannWhitneyU = %.5f, want %.5f", got, tt.want)
+			}
+			if swapped := mannWhitneyU(tt.y, tt.x); math.Abs(swapped-got) > 1e-9 {
+				t.Errorf("not symmetric: %.5f vs %.5f", got, swapped)
+			}
+		})
+	}
+}
+
+func TestMannWhitneyULargeSamples(t *testing.T) {
+	// Past exactMannWhitneyLimit the normal approximation must agree on
+	// clearly different and clearly identical distributions
+	x := make([]float64, 40)
+	y := make([]float64, 40)
+	for i := range x {
+		x[i] = 100 + float64(i%10)
+		y[i] = 105 + float64(i%10)
+	}
+	if p := mannWhitneyU(x, y); p >= significanceLevel {
+		t.Errorf("shifted samples: p = %.4f, want < %v", p, significanceLevel)
+	}
+	if p := mannWhitneyU(x, x); p < 0.9 {
+		t.Errorf("same samples: p = %.4f, want ≈1", p)
+	}
+}
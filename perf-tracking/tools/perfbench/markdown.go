@@ -0,0 +1,178 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+// Comparison output formats for -format
+const (
+	formatText     = "text"
+	formatMarkdown = "markdown"
+)
+
+// writeMarkdownComparison writes comparisons as GitHub-flavored markdown
+// suitable for a PR comment: a one-line verdict, a table of the significant
+// changes (regressions first, worst first) and the insignificant ones
+// collapsed in a <details> block. A significant change of B/op, allocs/op,
+// MB/s or a custom metric alone also counts as a change. Baseline and Target
+// are shown in the display unit.
+func writeMarkdownComparison(w io.Writer, comparisons []Comparison, baseMetadata, targetMetadata Metadata, display string) {
+	summary := summarizeComparisons(comparisons)
+
+	var changed, unchanged []Comparison
+	anyRegressed := false
+	for _, c := range comparisons {
+		regressed, improved := markdownChange(c)
+		anyRegressed = anyRegressed || regressed
+		if regressed || improved {
+			changed = append(changed, c)
+		} else {
+			unchanged = append(unchanged, c)
+		}
+	}
+	slices.SortFunc(changed, func(a, b Comparison) int {
+		return cmp.Compare(b.DeltaPercent, a.DeltaPercent)
+	})
+	slices.SortFunc(unchanged, func(a, b Comparison) int {
+		return strings.Compare(a.Benchmark, b.Benchmark)
+	})
+
+	fmt.Fprintf(w, "### Benchmark Comparison\n\n")
+	fmt.Fprintf(w, "Baseline: `%s` · Target: `%s`\n\n", markdownVersion(baseMetadata), markdownVersion(targetMetadata))
+	if display == displayOpsPerSec {
+		fmt.Fprintf(w, "Baseline and Target in operations per second; a positive change is faster.\n\n")
+	}
+
+	icon := "✅"
+	if anyRegressed {
+		icon = "⚠️"
+	}
+	fmt.Fprintf(w, "%s **%s**: %d regressed, %d improved, %d unchanged of %d benchmarks in ns/op (geomean %+.1f%%)\n",
+		icon, summary.Verdict, summary.Regressions, summary.Improvements, summary.Insignificant,
+		summary.Benchmarks, summary.GeomeanDeltaPercent)
+
+	if len(summary.Categories) > 1 {
+		fmt.Fprintf(w, "\nGeomean by category:")
+		for i, c := range summary.Categories {
+			sep := " "
+			if i > 0 {
+				sep = " · "
+			}
+			fmt.Fprintf(w, "%s%s %+.1f%% (%d)", sep, c.Category, c.GeomeanDeltaPercent, c.Benchmarks)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(changed) > 0 {
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, changed, display)
+	}
+	if len(unchanged) > 0 {
+		fmt.Fprintf(w, "\n<details>\n<summary>%d unchanged benchmarks</summary>\n\n", len(unchanged))
+		writeMarkdownTable(w, unchanged, display)
+		fmt.Fprintf(w, "\n</details>\n")
+	}
+}
+
+// writeMarkdownDrift writes the benchmarks only one side has as collapsed
+// lists, nothing when both sides ran the same suite
+func writeMarkdownDrift(w io.Writer, d BenchmarkDrift) {
+	for _, section := range []struct {
+		title string
+		names []string
+	}{
+		{"new benchmarks (target only)", d.Added},
+		{"removed benchmarks (baseline only)", d.Removed},
+	} {
+		if len(section.names) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n<details>\n<summary>%d %s</summary>\n\n", len(section.names), section.title)
+		for _, name := range section.names {
+			fmt.Fprintf(w, "- `%s`\n", name)
+		}
+		fmt.Fprintf(w, "\n</details>\n")
+	}
+}
+
+// markdownChange reports whether c got significantly worse or better in
+// ns/op or any other metric
+func markdownChange(c Comparison) (regressed, improved bool) {
+	if c.significant() {
+		regressed, improved = c.DeltaPercent > 0, c.DeltaPercent < 0
+	}
+	for _, m := range c.Metrics {
+		if m.Significant {
+			regressed = regressed || m.regressionPercent() > 0
+			improved = improved || m.regressionPercent() < 0
+		}
+	}
+	return regressed, improved
+}
+
+// writeMarkdownTable writes one row per comparison. Rows with a significant
+// regression of any metric get ⚠️, others with an improvement ✅ and changes
+// within the benchmark's noise band ≈; everything else is left unmarked. Custom metrics share one column listing their
+// significant changes.
+func writeMarkdownTable(w io.Writer, comparisons []Comparison, display string) {
+	fmt.Fprintln(w, "| | Benchmark | Baseline | Target | Change | p | B/op | allocs/op | MB/s | Other |")
+	fmt.Fprintln(w, "|---|---|---:|---:|---:|---:|---:|---:|---:|---|")
+	for _, c := range comparisons {
+		status := ""
+		if regressed, improved := markdownChange(c); regressed {
+			status = "⚠️"
+		} else if improved {
+			status = "✅"
+		} else if c.withinNoise() {
+			status = "≈"
+		}
+		pValue := fmt.Sprintf("n=%d+%d", c.BaselineSamples, c.TargetSamples)
+		if c.tested() {
+			pValue = fmt.Sprintf("%.3f", c.PValue)
+		}
+		base, target, change, unit := displayedValues(c, display)
+		var other []string
+		for _, unit := range customMetricUnits(c) {
+			if m := c.Metrics[unit]; m.Significant {
+				other = append(other, fmt.Sprintf("`%s` %s", unit, metricCell(m)))
+			}
+		}
+		fmt.Fprintf(w, "| %s | `%s` | %s%s | %s%s | %s | %s | %s | %s | %s | %s |\n",
+			status, c.Benchmark, unit.Format(base), markdownInterval(c.BaselineCI),
+			unit.Format(target), markdownInterval(c.TargetCI), change, pValue,
+			scaledMetricCell(c.Metrics["B/op"], units.Bytes), metricCell(c.Metrics["allocs/op"]),
+			scaledMetricCell(c.Metrics["MB/s"], units.Throughput),
+			strings.Join(other, ", "))
+	}
+}
+
+// markdownInterval renders a confidence interval as a suffix to its value,
+// the half width relative to the mean; empty without one
+func markdownInterval(i *Interval) string {
+	if i == nil {
+		return ""
+	}
+	return fmt.Sprintf(" ±%.1f%%", i.relativeHalfWidth())
+}
+
+// markdownVersion labels a result by its Go version, with the commit when known
+func markdownVersion(m Metadata) string {
+	label := m.GoVersion
+	if m.GoVersionFull != "" {
+		label = m.GoVersionFull
+	}
+	if m.CommitSha != "" {
+		sha := m.CommitSha
+		if len(sha) > 12 {
+			sha = sha[:12]
+		}
+		label += " @ " + sha
+	}
+	return label
+}
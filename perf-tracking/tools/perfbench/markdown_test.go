@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdownComparison(t *testing.T) {
+	tested := func(name string, base, target, p float64) Comparison {
+		return Comparison{
+			Benchmark: name, BaselineNs: base, TargetNs: target,
+			DeltaPercent:    (target - base) / base * 100,
+			BaselineSamples: 6, TargetSamples: 6, PValue: p,
+		}
+	}
+	comparisons := []Comparison{
+		tested("BenchmarkZeta", 100, 100.5, 0.5),
+		tested("BenchmarkFaster", 2000, 1000, 0.002),
+		tested("BenchmarkSlower", 100, 150, 0.002),
+		tested("BenchmarkNoisy", 100, 120, 0.3),
+		tested("BenchmarkWorse", 100, 110, 0.01),
+	}
+	base := Metadata{GoVersion: "1.24", GoVersionFull: "go1.24.0"}
+	target := Metadata{GoVersion: "1.25", CommitSha: "0123456789abcdef"}
+
+	var sb strings.Builder
+	writeMarkdownComparison(&sb, comparisons, base, target, displayNsPerOp)
+	out := sb.String()
+
+	for _, want := range []string{
+		"Baseline: `go1.24.0` · Target: `1.25 @ 0123456789ab`",
+		"⚠️ **regressed**: 2 regressed, 1 improved, 2 unchanged of 5 benchmarks",
+		"| ⚠️ | `BenchmarkSlower` | 100.00 ns | 150.00 ns | +50.0% | 0.002 |",
+		"| ✅ | `BenchmarkFaster` | 2.00 µs | 1.00 µs | -50.0% | 0.002 |",
+		"<summary>2 unchanged benchmarks</summary>",
+		"|  | `BenchmarkNoisy` | 100.00 ns | 120.00 ns | +20.0% | 0.300 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	// Changes worst first, then the collapsed unchanged rows by name
+	order := []string{"BenchmarkSlower", "BenchmarkWorse", "BenchmarkFaster", "<details>", "BenchmarkNoisy", "BenchmarkZeta", "</details>"}
+	last := -1
+	for _, s := range order {
+		i := strings.Index(out, s)
+		if i <= last {
+			t.Errorf("%s out of order:\n%s", s, out)
+		}
+		last = i
+	}
+
+	// An allocation regression alone still flags the row and the verdict
+	allocs := tested("BenchmarkAllocs", 100, 100, 0.9)
+	allocs.Metrics = map[string]*MetricDelta{"allocs/op": {Baseline: 1, Target: 2, DeltaPercent: 100, Significant: true}}
+	sb.Reset()
+	writeMarkdownComparison(&sb, []Comparison{allocs}, base, target, displayNsPerOp)
+	if out := sb.String(); !strings.Contains(out, "⚠️ **unchanged**") || !strings.Contains(out, "| ⚠️ | `BenchmarkAllocs` | 100.00 ns | 100.00 ns | +0.0% | 0.900 |  | +100% |  |") {
+		t.Errorf("allocation regression output:\n%s", out)
+	}
+
+	// Significant custom metric changes are listed in the Other column
+	pauses := tested("BenchmarkGCLatency", 100, 100, 0.9)
+	pauses.Metrics = map[string]*MetricDelta{
+		"pause-ns/gc":  {Baseline: 400, Target: 200, DeltaPercent: -50, Significant: true},
+		"gc-cycles/op": {Baseline: 3, Target: 3},
+	}
+	sb.Reset()
+	writeMarkdownComparison(&sb, []Comparison{pauses}, base, target, displayNsPerOp)
+	if out := sb.String(); !strings.Contains(out, "| ✅ | `BenchmarkGCLatency` | 100.00 ns | 100.00 ns | +0.0% | 0.900 |  |  |  | `pause-ns/gc` -50% |") {
+		t.Errorf("custom metric output:\n%s", out)
+	}
+
+	// With more than one category the geomean is broken down by category
+	if strings.Contains(out, "Geomean by category") {
+		t.Errorf("single category broken down:\n%s", out)
+	}
+	sb.Reset()
+	writeMarkdownComparison(&sb, []Comparison{tested("BenchmarkGCLatency", 100, 200, 0.002), allocs}, base, target, displayNsPerOp)
+	if out := sb.String(); !strings.Contains(out, "Geomean by category: runtime +100.0% (1) · uncategorized +0.0% (1)") {
+		t.Errorf("category geomeans missing:\n%s", out)
+	}
+
+	sb.Reset()
+	writeMarkdownDrift(&sb, BenchmarkDrift{Removed: []string{"BenchmarkOld", "BenchmarkGone"}})
+	if out := sb.String(); !strings.Contains(out, "<summary>2 removed benchmarks (baseline only)</summary>\n\n- `BenchmarkOld`\n- `BenchmarkGone`\n") || strings.Contains(out, "new benchmarks") {
+		t.Errorf("drift output:\n%s", out)
+	}
+	sb.Reset()
+	if writeMarkdownDrift(&sb, BenchmarkDrift{}); sb.Len() != 0 {
+		t.Errorf("drift output without drift:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	writeMarkdownComparison(&sb, comparisons[:1], base, target, displayNsPerOp)
+	if out := sb.String(); !strings.Contains(out, "✅ **unchanged**") || strings.Contains(out, "| ⚠️") {
+		t.Errorf("unchanged output:\n%s", out)
+	}
+}
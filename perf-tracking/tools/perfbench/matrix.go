@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+// Matrix is an N-way comparison (-inputs): every later input compared
+// against the first, for release-to-release trend reports
+type Matrix struct {
+	Versions []Metadata  `json:"versions"`
+	Rows     []MatrixRow `json:"rows"`
+	// Geomean holds, per later input, the geometric mean change of ns/op
+	// over the benchmarks it shares with the first; nil when it shares none
+	Geomean []*float64 `json:"geomean_delta_percent"`
+}
+
+// MatrixRow is one benchmark of the first input
+type MatrixRow struct {
+	Benchmark  string  `json:"benchmark"`
+	BaselineNs float64 `json:"baseline_ns"`
+	// Changes has one entry per later input; nil where it lacks the benchmark
+	Changes []*MatrixCell `json:"changes"`
+}
+
+// MatrixCell is the change of one benchmark against the first input
+type MatrixCell struct {
+	TargetNs     float64 `json:"target_ns"`
+	DeltaPercent float64 `json:"delta_percent"`
+	PValue       float64 `json:"p_value,omitempty"`
+	Significant  bool    `json:"significant"`
+}
+
+// buildMatrix compares results[1:] against results[0] with compareResults,
+// over the benchmarks filter matches, judging changes against the noise
+// bands when given
+func buildMatrix(results []BenchmarkResult, filter BenchmarkFilter, bands map[string]float64) Matrix {
+	m := Matrix{}
+	for _, r := range results {
+		m.Versions = append(m.Versions, r.Metadata)
+	}
+
+	base := extractBenchmarks(results[0].Benchmarks)
+	filterBenchmarks(base, filter)
+	rows := make(map[string]*MatrixRow, len(base))
+	for name, stats := range base {
+		rows[name] = &MatrixRow{
+			Benchmark:  name,
+			BaselineNs: medianNs(stats),
+			Changes:    make([]*MatrixCell, len(results)-1),
+		}
+	}
+	for i, r := range results[1:] {
+		var g geomean
+		comparisons := compareResults(base, extractBenchmarks(r.Benchmarks), ratioPolicy{})
+		applyNoiseBands(comparisons, bands)
+		for _, c := range comparisons {
+			rows[c.Benchmark].Changes[i] = &MatrixCell{
+				TargetNs:     c.TargetNs,
+				DeltaPercent: c.DeltaPercent,
+				PValue:       c.PValue,
+				Significant:  c.significant(),
+			}
+			g.add(c)
+		}
+		var delta *float64
+		if g.count > 0 {
+			d := (g.ratio() - 1) * 100
+			delta = &d
+		}
+		m.Geomean = append(m.Geomean, delta)
+	}
+
+	for _, row := range rows {
+		m.Rows = append(m.Rows, *row)
+	}
+	sort.Slice(m.Rows, func(i, j int) bool { return m.Rows[i].Benchmark < m.Rows[j].Benchmark })
+	return m
+}
+
+// matrixCell renders a change for the matrix tables: the delta when
+// significant, "~" when not, "-" when the input lacks the benchmark
+func matrixCell(c *MatrixCell) string {
+	switch {
+	case c == nil:
+		return "-"
+	case !c.Significant:
+		return "~"
+	}
+	return fmt.Sprintf("%+.1f%%", c.DeltaPercent)
+}
+
+// matrixGeomean renders a geomean change, "-" when there is none
+func matrixGeomean(g *float64) string {
+	if g == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", *g)
+}
+
+// matrixVersion labels a matrix column by its Go version
+func matrixVersion(m Metadata) string {
+	if m.GoVersion != "" {
+		return m.GoVersion
+	}
+	return m.GoVersionFull
+}
+
+func printMatrix(m Matrix) {
+	fmt.Printf("\n=== Benchmark Matrix (vs %s) ===\n\n", matrixVersion(m.Versions[0]))
+
+	fmt.Printf("%-40s %15s", "Benchmark", matrixVersion(m.Versions[0]))
+	for _, v := range m.Versions[1:] {
+		fmt.Printf(" %10s", matrixVersion(v))
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 56+11*(len(m.Versions)-1)))
+
+	for _, row := range m.Rows {
+		unit := units.Time(row.BaselineNs)
+		fmt.Printf("%-40s %12.2f %s", row.Benchmark, unit.Convert(row.BaselineNs), unit.Pad(2))
+		for _, c := range row.Changes {
+			fmt.Printf(" %10s", matrixCell(c))
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("%-40s %15s", "geomean", "")
+	for _, g := range m.Geomean {
+		fmt.Printf(" %10s", matrixGeomean(g))
+	}
+	fmt.Println()
+}
+
+// writeMarkdownMatrix writes m as a GitHub-flavored markdown table
+func writeMarkdownMatrix(w io.Writer, m Matrix) {
+	fmt.Fprintf(w, "### Benchmark Matrix\n\n")
+	fmt.Fprintf(w, "Changes against `%s`; `~` is not significant, `-` not measured.\n\n", markdownVersion(m.Versions[0]))
+
+	fmt.Fprintf(w, "| Benchmark | %s |", matrixVersion(m.Versions[0]))
+	for _, v := range m.Versions[1:] {
+		fmt.Fprintf(w, " %s |", matrixVersion(v))
+	}
+	fmt.Fprintf(w, "\n|---|---:|%s\n", strings.Repeat("---:|", len(m.Versions)-1))
+
+	for _, row := range m.Rows {
+		fmt.Fprintf(w, "| `%s` | %s |", row.Benchmark, units.Time(row.BaselineNs).Format(row.BaselineNs))
+		for _, c := range row.Changes {
+			fmt.Fprintf(w, " %s |", matrixCell(c))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprint(w, "| **geomean** | |")
+	for _, g := range m.Geomean {
+		fmt.Fprintf(w, " %s |", matrixGeomean(g))
+	}
+	fmt.Fprintln(w)
+}
+
+// compareMatrix loads the results in paths (JSON or raw go test output),
+// prints them as a matrix against the first one in format and writes it as
+// JSON to output when set. Like the pairwise comparison it refuses inputs
+// from different machines unless force is set.
+func compareMatrix(paths []string, filter BenchmarkFilter, bands map[string]float64, format, output string, force bool) error {
+	if len(paths) < 2 {
+		return fmt.Errorf("-inputs needs at least two result files, got %d", len(paths))
+	}
+	status := io.Writer(os.Stdout)
+	if format == formatMarkdown {
+		status = os.Stderr
+	}
+
+	var results []BenchmarkResult
+	for _, path := range paths {
+		r, err := loadBenchmarkResult(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		results = append(results, r)
+
+		// Cross-machine comparisons measure the hardware, not the change
+		mismatches := machineMismatches(results[0], r)
+		if len(mismatches) == 0 {
+			continue
+		}
+		label := "Error"
+		if force {
+			label = "Warning"
+		}
+		fmt.Fprintf(status, "%s: %s and %s were collected on different machines:\n", label, paths[0], path)
+		for _, m := range mismatches {
+			fmt.Fprintf(status, "  - %s\n", m)
+		}
+		if !force {
+			return fmt.Errorf("use -force to compare anyway")
+		}
+	}
+
+	m := buildMatrix(results, filter, bands)
+	if format == formatMarkdown {
+		writeMarkdownMatrix(os.Stdout, m)
+	} else {
+		printMatrix(m)
+	}
+
+	if output != "" {
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal matrix: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(status, "\nMatrix saved to: %s\n", output)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBuildMatrix(t *testing.T) {
+	result := func(version string, ns map[string][]float64) BenchmarkResult {
+		r := BenchmarkResult{Metadata: Metadata{GoVersion: version}}
+		for name, samples := range ns {
+			for _, v := range samples {
+				r.Benchmarks = append(r.Benchmarks, fmt.Sprintf("%s-8 1000 %.0f ns/op", name, v))
+			}
+		}
+		return r
+	}
+	results := []BenchmarkResult{
+		result("1.23", map[string][]float64{
+			"BenchmarkA": {100, 101, 99, 100},
+			"BenchmarkB": {200, 200, 200, 200},
+		}),
+		result("1.24", map[string][]float64{
+			"BenchmarkA": {90, 91, 89, 90},
+			"BenchmarkB": {201, 199, 200, 200},
+			"BenchmarkC": {50},
+		}),
+		result("1.25", map[string][]float64{
+			"BenchmarkB": {100, 100, 100, 100},
+		}),
+		result("1.26", map[string][]float64{"BenchmarkD": {1}}),
+	}
+
+	m := buildMatrix(results, BenchmarkFilter{}, nil)
+	if len(m.Versions) != 4 || m.Versions[3].GoVersion != "1.26" {
+		t.Fatalf("versions = %+v", m.Versions)
+	}
+	// Rows follow the first input: BenchmarkC is new in 1.24 and left out
+	var got []string
+	for _, row := range m.Rows {
+		cells := []string{row.Benchmark}
+		for _, c := range row.Changes {
+			cells = append(cells, matrixCell(c))
+		}
+		got = append(got, strings.Join(cells, " "))
+	}
+	want := "BenchmarkA -10.0% - -|BenchmarkB ~ -50.0% -"
+	if strings.Join(got, "|") != want {
+		t.Errorf("rows = %q, want %q", strings.Join(got, "|"), want)
+	}
+	if m.Rows[0].BaselineNs != 100 {
+		t.Errorf("baseline = %v, want the median 100", m.Rows[0].BaselineNs)
+	}
+
+	// geomean(0.9, 1) for 1.24, 0.5 for 1.25, nothing shared with 1.26
+	if g := m.Geomean[0]; g == nil || math.Abs(*g-(math.Sqrt(0.9)-1)*100) > 1e-9 {
+		t.Errorf("1.24 geomean = %v", g)
+	}
+	if g := m.Geomean[1]; g == nil || *g != -50 {
+		t.Errorf("1.25 geomean = %v, want -50", g)
+	}
+	if m.Geomean[2] != nil {
+		t.Errorf("1.26 geomean = %v, want none", *m.Geomean[2])
+	}
+
+	var sb strings.Builder
+	writeMarkdownMatrix(&sb, m)
+	for _, line := range []string{
+		"| Benchmark | 1.23 | 1.24 | 1.25 | 1.26 |",
+		"|---|---:|---:|---:|---:|",
+		"| `BenchmarkB` | 200.00 ns | ~ | -50.0% | - |",
+		"| **geomean** | | -5.1% | -50.0% | - |",
+	} {
+		if !strings.Contains(sb.String(), line+"\n") {
+			t.Errorf("markdown lacks %q:\n%s", line, sb.String())
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// loadNoiseBands reads the per-benchmark noise bands, in percent, from an
+// index.json written by export -results-dir: the MaxCV observed for each
+// benchmark across every exported version. A change within its benchmark's
+// band is indistinguishable from the run-to-run noise seen before.
+func loadNoiseBands(path string) (map[string]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var index IndexData
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(index.Benchmarks) == 0 {
+		return nil, fmt.Errorf("%s lists no benchmarks; want an index.json from export -results-dir", path)
+	}
+	bands := make(map[string]float64, len(index.Benchmarks))
+	for _, b := range index.Benchmarks {
+		if b.MaxCV > 0 {
+			bands[b.Name] = b.MaxCV * 100
+		}
+	}
+	return bands, nil
+}
+
+// applyNoiseBands sets the noise band of each comparison found in bands and
+// re-judges its significance: a change within the band no longer counts as
+// slower or faster, in the verdict, the gate or the tables.
+func applyNoiseBands(comparisons []Comparison, bands map[string]float64) {
+	for i := range comparisons {
+		c := &comparisons[i]
+		c.NoiseBandPercent = bands[c.Benchmark]
+		c.Significant = c.significant()
+	}
+}
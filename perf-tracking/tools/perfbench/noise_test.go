@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadNoiseBands(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.json")
+	index := `{"benchmarks": [
+		{"name": "BenchmarkTLSHandshake/TLS13", "reliability": "noisy", "max_cv": 0.12},
+		{"name": "BenchmarkSHA256", "reliability": "reliable", "max_cv": 0.01},
+		{"name": "BenchmarkNew", "reliability": "reliable", "max_cv": 0}
+	]}`
+	if err := os.WriteFile(path, []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bands, err := loadNoiseBands(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bands["BenchmarkTLSHandshake/TLS13"]; got != 12 {
+		t.Errorf("TLS13 band = %v, want 12", got)
+	}
+	if _, ok := bands["BenchmarkNew"]; ok {
+		t.Error("benchmark without a CV got a band")
+	}
+
+	// A comparison JSON or a version file is not an index
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte(`{"comparisons": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadNoiseBands(empty); err == nil {
+		t.Error("loadNoiseBands accepted a file without benchmarks")
+	}
+}
+
+func TestApplyNoiseBands(t *testing.T) {
+	tested := func(name string, delta float64) Comparison {
+		return Comparison{Benchmark: name, BaselineNs: 100, TargetNs: 100 + delta, DeltaPercent: delta,
+			BaselineSamples: 6, TargetSamples: 6, PValue: 0.002}
+	}
+	comparisons := []Comparison{
+		tested("BenchmarkTLSHandshake/TLS13", 8),
+		tested("BenchmarkTLSHandshake/TLS12", 20),
+		tested("BenchmarkSHA256", 3),
+	}
+	applyNoiseBands(comparisons, map[string]float64{
+		"BenchmarkTLSHandshake/TLS13": 12,
+		"BenchmarkTLSHandshake/TLS12": 12,
+	})
+
+	// +8% with p=0.002 is within the 12% band: no longer a regression
+	tls13 := comparisons[0]
+	if tls13.Significant || tls13.significant() || !tls13.withinNoise() || tls13.NoiseBandPercent != 12 {
+		t.Errorf("TLS13 = %+v, want within noise", tls13)
+	}
+	// Beyond the band, and without one, changes are judged as before
+	if !comparisons[1].Significant || comparisons[1].withinNoise() {
+		t.Errorf("TLS12 = %+v, want significant", comparisons[1])
+	}
+	if !comparisons[2].Significant || comparisons[2].NoiseBandPercent != 0 {
+		t.Errorf("SHA256 = %+v, want significant without band", comparisons[2])
+	}
+
+	failures := gateRegressions(comparisons, RegressionThresholds{Default: 5})
+	if len(failures) != 1 || failures[0].Benchmark != "BenchmarkTLSHandshake/TLS12" {
+		t.Errorf("failures = %+v, want only TLS12", failures)
+	}
+	if s := summarizeComparisons(comparisons); s.Regressions != 2 || s.Insignificant != 1 {
+		t.Errorf("summary = %+v, want 2 regressions and 1 insignificant", s)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Row orders for -sort
+const (
+	sortDelta    = "delta"    // largest absolute change, as the -delta policy measures it, first
+	sortName     = "name"     // benchmark name
+	sortCategory = "category" // benchmarkCategories order, then name
+)
+
+var sortOrders = []string{sortDelta, sortName, sortCategory}
+
+// parseSortOrder validates a -sort value
+func parseSortOrder(s string) (string, error) {
+	if !slices.Contains(sortOrders, s) {
+		return "", fmt.Errorf("invalid -sort %q (want %s)", s, strings.Join(sortOrders, ", "))
+	}
+	return s, nil
+}
+
+// compareMagnitude orders a before b when it moved more; ties go by name
+func compareMagnitude(a, b Comparison) int {
+	if c := cmp.Compare(math.Abs(b.change()), math.Abs(a.change())); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Benchmark, b.Benchmark)
+}
+
+// sortComparisons orders comparisons in place by one of sortOrders
+func sortComparisons(comparisons []Comparison, order string) {
+	switch order {
+	case sortDelta:
+		slices.SortFunc(comparisons, compareMagnitude)
+	case sortCategory:
+		slices.SortFunc(comparisons, func(a, b Comparison) int {
+			ca := slices.Index(benchmarkCategories, getBenchmarkCategory(a.Benchmark))
+			cb := slices.Index(benchmarkCategories, getBenchmarkCategory(b.Benchmark))
+			if c := cmp.Compare(ca, cb); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Benchmark, b.Benchmark)
+		})
+	default:
+		slices.SortFunc(comparisons, func(a, b Comparison) int {
+			return strings.Compare(a.Benchmark, b.Benchmark)
+		})
+	}
+}
+
+// largestMovers returns the n comparisons with the largest absolute change,
+// in their order in comparisons. n <= 0 keeps them all.
+func largestMovers(comparisons []Comparison, n int) []Comparison {
+	if n <= 0 || n >= len(comparisons) {
+		return comparisons
+	}
+	ranked := slices.SortedFunc(slices.Values(comparisons), compareMagnitude)
+	keep := make(map[string]bool, n)
+	for _, c := range ranked[:n] {
+		keep[c.Benchmark] = true
+	}
+	var movers []Comparison
+	for _, c := range comparisons {
+		if keep[c.Benchmark] {
+			movers = append(movers, c)
+		}
+	}
+	return movers
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortComparisons(t *testing.T) {
+	comparisons := []Comparison{
+		{Benchmark: "BenchmarkTLSHandshake", DeltaPercent: 3},
+		{Benchmark: "BenchmarkGCLatency", DeltaPercent: -20},
+		{Benchmark: "BenchmarkJSONDecode", DeltaPercent: 8},
+		{Benchmark: "BenchmarkAltGzip", DeltaPercent: -8},
+		{Benchmark: "BenchmarkTinyAlloc", DeltaPercent: 0.5},
+	}
+	names := func(cs []Comparison) string {
+		var s []string
+		for _, c := range cs {
+			s = append(s, strings.TrimPrefix(c.Benchmark, "Benchmark"))
+		}
+		return strings.Join(s, ",")
+	}
+
+	for _, tt := range []struct {
+		order string
+		want  string
+	}{
+		// Equal magnitudes go by name
+		{sortDelta, "GCLatency,AltGzip,JSONDecode,TLSHandshake,TinyAlloc"},
+		{sortName, "AltGzip,GCLatency,JSONDecode,TLSHandshake,TinyAlloc"},
+		{sortCategory, "GCLatency,TinyAlloc,JSONDecode,TLSHandshake,AltGzip"},
+	} {
+		sortComparisons(comparisons, tt.order)
+		if got := names(comparisons); got != tt.want {
+			t.Errorf("sort %s = %s, want %s", tt.order, got, tt.want)
+		}
+	}
+
+	// The movers keep the order they are given in
+	sortComparisons(comparisons, sortName)
+	if got, want := names(largestMovers(comparisons, 3)), "AltGzip,GCLatency,JSONDecode"; got != want {
+		t.Errorf("largestMovers(3) = %s, want %s", got, want)
+	}
+	if got := largestMovers(comparisons, 0); len(got) != len(comparisons) {
+		t.Errorf("largestMovers(0) kept %d of %d", len(got), len(comparisons))
+	}
+
+	if _, err := parseSortOrder("p-value"); err == nil {
+		t.Error("parseSortOrder(p-value) succeeded, want error")
+	}
+}
+
+func TestColorize(t *testing.T) {
+	color := barStyle{Color: true}
+	for _, tt := range []struct {
+		regression  float64
+		significant bool
+		style       barStyle
+		want        string
+	}{
+		{5, true, color, colorRed + "x" + colorReset},
+		{-5, true, color, colorGreen + "x" + colorReset},
+		{5, false, color, "x"},
+		{5, true, barStyle{}, "x"},
+	} {
+		if got := colorize("x", tt.regression, tt.significant, tt.style); got != tt.want {
+			t.Errorf("colorize(%v, %v, %+v) = %q, want %q", tt.regression, tt.significant, tt.style, got, tt.want)
+		}
+	}
+
+	// A throughput drop is colored as a regression
+	mbps := &MetricDelta{DeltaPercent: -10, HigherIsBetter: true, Significant: true}
+	if got, want := metricColumn(mbps, nil, color), colorRed+"    -10%"+colorReset; got != want {
+		t.Errorf("metricColumn = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxSampleSpread is the max/min ns/op ratio across samples beyond which a
+// run is considered broken rather than noisy (thermal throttling, a stalled
+// VM, or a benchmark whose loop was optimized away in some runs).
+const maxSampleSpread = 10.0
+
+// throughputBenchmarks call b.SetBytes, so every result line must carry MB/s.
+// A missing column means the benchmark was changed or the output is mangled.
+// TestThroughputBenchmarksComplete checks the list against the sources.
+var throughputBenchmarks = map[string]bool{
+	"BenchmarkSmallAllocation":       true,
+	"BenchmarkLargeAllocation":       true,
+	"BenchmarkGCPressure":            true,
+	"BenchmarkSmallAllocSpecialized": true,
+	"BenchmarkAllocScan":             true,
+	"BenchmarkSliceConcat":           true,
+	"BenchmarkGCThroughput":          true,
+	"BenchmarkAESCTR":                true,
+	"BenchmarkAESGCM":                true,
+	"BenchmarkSHA":                   true,
+	"BenchmarkJSONDecode":            true,
+	"BenchmarkJSONEncode":            true,
+	"BenchmarkBinaryEncode":          true,
+	"BenchmarkBase64":                true,
+	"BenchmarkHex":                   true,
+	"BenchmarkParseIntBytes":         true,
+	"BenchmarkBytesSearch":           true,
+	"BenchmarkArchive":               true,
+	"BenchmarkRandomRead":            true,
+	"BenchmarkCRC32":                 true,
+	"BenchmarkFNVHash":               true,
+	"BenchmarkIOReadAll":             true,
+	"BenchmarkTCPThroughput":         true,
+	"BenchmarkTLSThroughput":         true,
+	"BenchmarkHTTPStreaming":         true,
+	"BenchmarkAltJSONDecode":         true,
+	"BenchmarkAltGzip":               true,
+}
+
+// checkPlausibility flags samples that indicate a broken run rather than
+// ordinary noise. It returns nil when nothing looks wrong.
+func checkPlausibility(name string, samples []BenchmarkSample) []string {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var warnings []string
+	minNs, maxNs := samples[0].NsPerOp, samples[0].NsPerOp
+	minAllocs, maxAllocs := samples[0].AllocsPerOp, samples[0].AllocsPerOp
+	hasThroughput := false
+	zeroNs := false
+	for _, s := range samples {
+		if s.NsPerOp <= 0 {
+			zeroNs = true
+		}
+		minNs = min(minNs, s.NsPerOp)
+		maxNs = max(maxNs, s.NsPerOp)
+		minAllocs = min(minAllocs, s.AllocsPerOp)
+		maxAllocs = max(maxAllocs, s.AllocsPerOp)
+		if s.MBPerSec > 0 {
+			hasThroughput = true
+		}
+	}
+
+	if zeroNs {
+		warnings = append(warnings, "0 ns/op sample (benchmark body optimized away?)")
+	} else if maxNs/minNs > maxSampleSpread {
+		warnings = append(warnings, fmt.Sprintf("ns/op varies %.0fx across samples (%.2f-%.2f)", maxNs/minNs, minNs, maxNs))
+	}
+	if minAllocs != maxAllocs {
+		warnings = append(warnings, fmt.Sprintf("allocs/op changes between samples (%d-%d)", minAllocs, maxAllocs))
+	}
+	if throughputBenchmarks[benchmarkBaseName(name)] && !hasThroughput {
+		warnings = append(warnings, "missing MB/s although the benchmark calls SetBytes")
+	}
+	return warnings
+}
+
+// reportPlausibility prints plausibility warnings for versionData. In strict
+// mode any warning fails the export so broken data never reaches the site.
+func reportPlausibility(versionData *VersionData, strict bool) error {
+	var names []string
+	for name, b := range versionData.Benchmarks {
+		if len(b.Warnings) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		for _, w := range versionData.Benchmarks[name].Warnings {
+			fmt.Printf("  Warning: %s: %s\n", name, w)
+		}
+	}
+	if strict {
+		return fmt.Errorf("%d benchmark(s) failed plausibility checks", len(names))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCheckPlausibility(t *testing.T) {
+	sample := func(ns, mbps float64, allocs int64) BenchmarkSample {
+		return BenchmarkSample{NsPerOp: ns, MBPerSec: mbps, AllocsPerOp: allocs}
+	}
+
+	tests := []struct {
+		name    string
+		bench   string
+		samples []BenchmarkSample
+		want    []string // substrings, one per expected warning
+	}{
+		{
+			name:    "clean run",
+			bench:   "BenchmarkMapCreation-8",
+			samples: []BenchmarkSample{sample(100, 0, 1), sample(105, 0, 1)},
+		},
+		{
+			name:    "zero ns/op",
+			bench:   "BenchmarkMapCreation-8",
+			samples: []BenchmarkSample{sample(0, 0, 0), sample(100, 0, 0)},
+			want:    []string{"0 ns/op"},
+		},
+		{
+			name:    "10x spread",
+			bench:   "BenchmarkMapCreation-8",
+			samples: []BenchmarkSample{sample(10, 0, 0), sample(150, 0, 0)},
+			want:    []string{"varies 15x"},
+		},
+		{
+			name:    "allocs change",
+			bench:   "BenchmarkMapCreation-8",
+			samples: []BenchmarkSample{sample(100, 0, 2), sample(100, 0, 3)},
+			want:    []string{"allocs/op changes between samples (2-3)"},
+		},
+		{
+			name:    "missing throughput",
+			bench:   "BenchmarkCRC32/1KB-8",
+			samples: []BenchmarkSample{sample(100, 0, 0)},
+			want:    []string{"missing MB/s"},
+		},
+		{
+			name:    "throughput present",
+			bench:   "BenchmarkCRC32/1KB-8",
+			samples: []BenchmarkSample{sample(100, 9500.5, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkPlausibility(tt.bench, tt.samples)
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkPlausibility() = %v, want %d warning(s)", got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !strings.Contains(got[i], w) {
+					t.Errorf("warning %q does not contain %q", got[i], w)
+				}
+			}
+		})
+	}
+}
+
+func TestReportPlausibilityStrict(t *testing.T) {
+	vd := &VersionData{Benchmarks: map[string]Benchmark{
+		"BenchmarkA": {Warnings: []string{"0 ns/op sample"}},
+		"BenchmarkB": {},
+	}}
+	if err := reportPlausibility(vd, false); err != nil {
+		t.Errorf("non-strict mode returned error: %v", err)
+	}
+	if err := reportPlausibility(vd, true); err == nil {
+		t.Error("strict mode should fail on warnings")
+	}
+}
+
+// TestThroughputBenchmarksComplete keeps throughputBenchmarks in step with
+// the suites: every benchmark whose body calls SetBytes must be listed, and
+// every listed benchmark must still exist. SetBytes calls in shared helpers
+// are not seen, so such benchmarks are only checked for existence.
+func TestThroughputBenchmarksComplete(t *testing.T) {
+	funcStart := regexp.MustCompile(`(?m)^func `)
+	defined := make(map[string]bool)
+	for _, dir := range []string{"benchmarks", "alternatives"} {
+		err := filepath.WalkDir(filepath.Join("..", "..", dir), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			// Each top-level func runs up to the next one
+			for _, body := range funcStart.Split(string(src), -1)[1:] {
+				m := benchmarkFuncPattern.FindStringSubmatch("func " + body)
+				if m == nil {
+					continue
+				}
+				defined[m[1]] = true
+				if strings.Contains(body, "SetBytes(") && !throughputBenchmarks[m[1]] {
+					t.Errorf("%s (%s) calls SetBytes but is missing from throughputBenchmarks", m[1], path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name := range throughputBenchmarks {
+		if !defined[name] {
+			t.Errorf("throughputBenchmarks lists %s, which no benchmark source defines", name)
+		}
+	}
+}
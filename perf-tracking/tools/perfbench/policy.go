@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+// Comparison policies for -delta
+const (
+	policyRatio      = "ratio"      // percent of the baseline, as benchstat reports
+	policyDifference = "difference" // target minus baseline, in the measured unit
+	policyLog        = "log"        // log2(target/baseline)
+)
+
+var comparisonPolicies = []string{policyRatio, policyDifference, policyLog}
+
+// comparisonPolicy decides how the change from a baseline to a target value
+// is expressed. It drives the reported change, its column in the tables and
+// the -sort delta and -top rankings; significance, noise bands, gates and
+// geomeans stay in percent of the baseline whatever the policy.
+type comparisonPolicy interface {
+	name() string
+	// delta returns the change from base to target, both positive
+	delta(base, target float64) float64
+	// format renders a delta of values displayed in unit
+	format(delta float64, unit units.Unit) string
+}
+
+// ratioPolicy reports (target/base - 1) in percent. It reads naturally but
+// overstates changes of tiny baselines: 3 ns to 4 ns is +33%.
+type ratioPolicy struct{}
+
+func (ratioPolicy) name() string { return policyRatio }
+
+func (ratioPolicy) delta(base, target float64) float64 { return (target/base - 1) * 100 }
+
+func (ratioPolicy) format(delta float64, _ units.Unit) string { return fmt.Sprintf("%+.1f%%", delta) }
+
+// differencePolicy reports target - base, so a 1 ns change reads as 1 ns
+// whether the benchmark takes 3 ns or 3 µs
+type differencePolicy struct{}
+
+func (differencePolicy) name() string { return policyDifference }
+
+func (differencePolicy) delta(base, target float64) float64 { return target - base }
+
+func (differencePolicy) format(delta float64, unit units.Unit) string {
+	return fmt.Sprintf("%+.2f %s", unit.Convert(delta), unit.Symbol)
+}
+
+// logPolicy reports log2(target/base), symmetric where percentages are
+// not: twice as slow is +1.00 and twice as fast -1.00 (+100% and -50%)
+type logPolicy struct{}
+
+func (logPolicy) name() string { return policyLog }
+
+func (logPolicy) delta(base, target float64) float64 { return math.Log2(target / base) }
+
+func (logPolicy) format(delta float64, _ units.Unit) string { return fmt.Sprintf("%+.3f log2", delta) }
+
+// parseComparisonPolicy returns the policy named by a -delta value
+func parseComparisonPolicy(s string) (comparisonPolicy, error) {
+	switch s {
+	case policyRatio:
+		return ratioPolicy{}, nil
+	case policyDifference:
+		return differencePolicy{}, nil
+	case policyLog:
+		return logPolicy{}, nil
+	}
+	return nil, fmt.Errorf("invalid -delta %q (want %s)", s, strings.Join(comparisonPolicies, ", "))
+}
+
+// policyNamed returns the policy a comparison was made with, ratio for
+// comparisons made without one
+func policyNamed(s string) comparisonPolicy {
+	if p, err := parseComparisonPolicy(s); err == nil {
+		return p
+	}
+	return ratioPolicy{}
+}
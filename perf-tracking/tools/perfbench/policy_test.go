@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+func TestComparisonPolicies(t *testing.T) {
+	for _, tt := range []struct {
+		policy       string
+		base, target float64
+		delta        float64
+		formatted    string
+	}{
+		{policyRatio, 3, 4, 33.333333333333336, "+33.3%"},
+		{policyRatio, 3000, 2000, -33.33333333333333, "-33.3%"},
+		{policyDifference, 3, 4, 1, "+1.00 ns"},
+		{policyDifference, 3000, 2000, -1000, "-1000.00 ns"},
+		// Symmetric: twice as slow and twice as fast are equally far from 0
+		{policyLog, 3, 6, 1, "+1.000 log2"},
+		{policyLog, 6, 3, -1, "-1.000 log2"},
+	} {
+		p, err := parseComparisonPolicy(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		delta := p.delta(tt.base, tt.target)
+		if math.Abs(delta-tt.delta) > 1e-9 {
+			t.Errorf("%s delta(%v, %v) = %v, want %v", tt.policy, tt.base, tt.target, delta, tt.delta)
+		}
+		if got := p.format(delta, units.Nanosecond); got != tt.formatted {
+			t.Errorf("%s format(%v) = %q, want %q", tt.policy, delta, got, tt.formatted)
+		}
+	}
+
+	if _, err := parseComparisonPolicy("percent"); err == nil {
+		t.Error("parseComparisonPolicy(percent) succeeded, want error")
+	}
+	if got := policyNamed("").name(); got != policyRatio {
+		t.Errorf("policyNamed(\"\") = %s, want ratio", got)
+	}
+}
+
+func TestCompareResultsPolicy(t *testing.T) {
+	base := extractBenchmarks([]string{
+		"BenchmarkTinyAlloc-8 \t 1000\t 3 ns/op",
+		"BenchmarkJSONDecode-8 \t 1000\t 3000 ns/op",
+	})
+	target := extractBenchmarks([]string{
+		"BenchmarkTinyAlloc-8 \t 1000\t 4 ns/op",
+		"BenchmarkJSONDecode-8 \t 1000\t 3300 ns/op",
+	})
+
+	// By ratio the 1 ns change of the tiny benchmark dominates; by
+	// difference the 300 ns one does. DeltaPercent is the ratio either way.
+	for _, tt := range []struct {
+		policy comparisonPolicy
+		first  string
+	}{
+		{ratioPolicy{}, "BenchmarkTinyAlloc"},
+		{differencePolicy{}, "BenchmarkJSONDecode"},
+	} {
+		comparisons := compareResults(base, target, tt.policy)
+		sortComparisons(comparisons, sortDelta)
+		if comparisons[0].Benchmark != tt.first {
+			t.Errorf("%s: largest change %s, want %s", tt.policy.name(), comparisons[0].Benchmark, tt.first)
+		}
+		for _, c := range comparisons {
+			if c.DeltaPolicy != tt.policy.name() || c.Benchmark == "BenchmarkJSONDecode" && math.Abs(c.DeltaPercent-10) > 1e-9 {
+				t.Errorf("%s: %+v", tt.policy.name(), c)
+			}
+		}
+	}
+}
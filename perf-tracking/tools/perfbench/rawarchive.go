@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// rawDirName is the directory under each platform output dir that holds
+// archived raw results, laid out like the collector's results tree
+// (raw/go<version>/<timestamp>.txt plus _metadata.json and _gctrace.txt
+// sidecars and the _memprofile directory) so it can be fed back into export
+// -results-dir for re-analysis. Execution trace directories are too large to
+// archive and are left out.
+const rawDirName = "raw"
+
+// sidecarPaths map a result file to the runner's optional sidecar files
+var sidecarPaths = []func(benchFile string) string{runMetadataPath, gcTracePath}
+
+// sidecarDirs map a result file to the runner's optional sidecar
+// directories that are archived whole
+var sidecarDirs = []func(benchFile string) string{allocProfileDir}
+
+// archiveRawResults copies the keep newest of mainFiles (newest first, as
+// returned by mainResultFiles) and their sidecars into the platform's raw
+// archive, then prunes older archived runs beyond keep. It returns the
+// archived result files relative to platformDir, newest first.
+func archiveRawResults(mainFiles []string, platformDir, version string, keep int) ([]string, error) {
+	destDir := filepath.Join(platformDir, rawDirName, "go"+version)
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw archive dir: %w", err)
+	}
+
+	for _, src := range mainFiles[:min(keep, len(mainFiles))] {
+		dst := filepath.Join(destDir, filepath.Base(src))
+		if err := copyPreservingMtime(src, dst); err != nil {
+			return nil, err
+		}
+		for _, sidecar := range sidecarPaths {
+			if _, err := os.Stat(sidecar(src)); err == nil {
+				if err := copyPreservingMtime(sidecar(src), sidecar(dst)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, sidecar := range sidecarDirs {
+			if err := copyDir(sidecar(src), sidecar(dst)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Earlier exports may have archived runs that are no longer in the
+	// newest keep; the archive holds at most keep runs per version
+	archived := mainResultFiles(destDir)
+	for _, stale := range archived[min(keep, len(archived)):] {
+		if err := os.Remove(stale); err != nil {
+			return nil, fmt.Errorf("failed to prune %s: %w", stale, err)
+		}
+		for _, sidecar := range sidecarPaths {
+			if err := os.Remove(sidecar(stale)); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to prune %s: %w", sidecar(stale), err)
+			}
+		}
+		for _, sidecar := range sidecarDirs {
+			if err := os.RemoveAll(sidecar(stale)); err != nil {
+				return nil, fmt.Errorf("failed to prune %s: %w", sidecar(stale), err)
+			}
+		}
+	}
+	archived = archived[:min(keep, len(archived))]
+
+	rel := make([]string, len(archived))
+	for i, f := range archived {
+		r, err := filepath.Rel(platformDir, f)
+		if err != nil {
+			return nil, err
+		}
+		rel[i] = filepath.ToSlash(r)
+	}
+	return rel, nil
+}
+
+// copyDir copies the regular files of the src directory into dst with
+// copyPreservingMtime. A missing src is not an error: the sidecar is
+// optional.
+func copyDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := copyPreservingMtime(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyPreservingMtime copies src to dst and keeps src's mtime, which export
+// uses as collected_at and mainResultFiles uses to order runs. Copying a file
+// onto itself (re-exporting from the archive) is a no-op.
+func copyPreservingMtime(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(info, dstInfo) {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }() // read-only
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
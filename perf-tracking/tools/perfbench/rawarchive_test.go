@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExportAllKeepRaw(t *testing.T) {
+	tmpDir := t.TempDir()
+	versionDir := filepath.Join(tmpDir, "results", "go1.25")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatalf("failed to create results dir: %v", err)
+	}
+
+	results := "goos: linux\ngoarch: amd64\ncpu: Test CPU\n" +
+		"BenchmarkFoo-4   \t1000\t100.0 ns/op\t0 B/op\t0 allocs/op\n"
+	base := time.Date(2026, 1, 26, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"run1.txt", "run2.txt", "run3.txt"} {
+		path := filepath.Join(versionDir, name)
+		if err := os.WriteFile(path, []byte(results), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		mtime := base.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("failed to set mtime: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "run3_metadata.json"), []byte(`{"benchmark_source_sha": "abc"}`), 0644); err != nil {
+		t.Fatalf("failed to write sidecar: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "run3_gctrace.txt"), []byte("=== BenchmarkFoo\n"), 0644); err != nil {
+		t.Fatalf("failed to write gctrace sidecar: %v", err)
+	}
+	for _, run := range []string{"run2", "run3"} {
+		memprofileDir := filepath.Join(versionDir, run+"_memprofile")
+		if err := os.MkdirAll(memprofileDir, 0755); err != nil {
+			t.Fatalf("failed to create memprofile dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(memprofileDir, "BenchmarkFoo.pprof"), []byte("profile"), 0644); err != nil {
+			t.Fatalf("failed to write memprofile: %v", err)
+		}
+	}
+
+	outputDir := filepath.Join(tmpDir, "data")
+	platformDir := filepath.Join(outputDir, "linux-amd64")
+	readRawFiles := func() []string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(platformDir, "go1.25.json"))
+		if err != nil {
+			t.Fatalf("failed to read export: %v", err)
+		}
+		var vd VersionData
+		if err := json.Unmarshal(data, &vd); err != nil {
+			t.Fatalf("failed to parse export: %v", err)
+		}
+		return vd.RawFiles
+	}
+
+	if err := exportAll(filepath.Join(tmpDir, "results"), outputDir, "linux-amd64", ExportOptions{KeepRaw: 2}); err != nil {
+		t.Fatalf("exportAll failed: %v", err)
+	}
+	want := []string{"raw/go1.25/run3.txt", "raw/go1.25/run2.txt"}
+	if got := readRawFiles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("raw_files = %v, want %v", got, want)
+	}
+	for _, sidecar := range []string{"run3_metadata.json", "run3_gctrace.txt", "run3_memprofile/BenchmarkFoo.pprof", "run2_memprofile/BenchmarkFoo.pprof"} {
+		if _, err := os.Stat(filepath.Join(platformDir, "raw", "go1.25", sidecar)); err != nil {
+			t.Errorf("sidecar not archived: %v", err)
+		}
+	}
+	info, err := os.Stat(filepath.Join(platformDir, "raw", "go1.25", "run3.txt"))
+	if err != nil || !info.ModTime().Equal(base.Add(2*time.Hour)) {
+		t.Errorf("archived mtime not preserved: %v", err)
+	}
+
+	// Lowering the limit prunes older archived runs
+	if err := exportAll(filepath.Join(tmpDir, "results"), outputDir, "linux-amd64", ExportOptions{KeepRaw: 1}); err != nil {
+		t.Fatalf("exportAll failed: %v", err)
+	}
+	if got := readRawFiles(); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("raw_files = %v, want %v", got, want[:1])
+	}
+	if _, err := os.Stat(filepath.Join(platformDir, "raw", "go1.25", "run2.txt")); !os.IsNotExist(err) {
+		t.Error("stale archived run was not pruned")
+	}
+	if _, err := os.Stat(filepath.Join(platformDir, "raw", "go1.25", "run2_memprofile")); !os.IsNotExist(err) {
+		t.Error("stale archived memprofile directory was not pruned")
+	}
+
+	// Re-exporting from the archive itself must not clobber it
+	if err := exportAll(filepath.Join(platformDir, "raw"), outputDir, "linux-amd64", ExportOptions{KeepRaw: 1}); err != nil {
+		t.Fatalf("exportAll from archive failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(platformDir, "raw", "go1.25", "run3.txt"))
+	if err != nil || string(data) != results {
+		t.Errorf("archived file changed when re-exported from itself: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// reanalyze regenerates a complete web-data tree in outputDir from the raw
+// results archived under dataDir/<platform>/raw (see --keep-raw), so changes
+// to the statistics (means, CVs, percentiles, reliability) apply to every
+// version without re-running old toolchains. Versions that have no raw
+// archive are copied unchanged and reported. dataDir itself is not modified.
+func reanalyze(dataDir, outputDir string, opts ExportOptions) error {
+	fmt.Println("=== Re-analyzing Archived Results ===")
+
+	absData, err := filepath.Abs(dataDir)
+	if err != nil {
+		return err
+	}
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return err
+	}
+	if absData == absOutput {
+		return fmt.Errorf("--output-dir must differ from --data-dir; re-analysis writes a fresh tree")
+	}
+
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	var platforms []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if files, _ := filepath.Glob(filepath.Join(dataDir, entry.Name(), "go*.json")); len(files) > 0 {
+			platforms = append(platforms, entry.Name())
+		}
+	}
+	if len(platforms) == 0 {
+		return fmt.Errorf("no platform directories with go*.json files in %s", dataDir)
+	}
+
+	var stale []string
+	for _, platform := range platforms {
+		fmt.Printf("\n--- %s ---\n", platform)
+		kept, err := reanalyzePlatform(filepath.Join(dataDir, platform), outputDir, platform, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", platform, err)
+		}
+		for _, v := range kept {
+			stale = append(stale, fmt.Sprintf("%s/go%s", platform, v))
+		}
+	}
+
+	if len(stale) > 0 {
+		fmt.Printf("\nNot re-analyzed (no raw archive, copied as-is): %s\n", strings.Join(stale, ", "))
+	}
+	fmt.Printf("✓ Re-analysis complete: %s\n", outputDir)
+	return nil
+}
+
+// reanalyzePlatform re-exports one platform's archived raw results and copies
+// the versions without an archive. It returns the copied versions.
+func reanalyzePlatform(platformDir, outputDir, platform string, opts ExportOptions) ([]string, error) {
+	rawDir := filepath.Join(platformDir, rawDirName)
+	archived := make(map[string]bool)
+	keep := 0
+	if entries, err := os.ReadDir(rawDir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+				continue
+			}
+			if n := len(mainResultFiles(filepath.Join(rawDir, entry.Name()))); n > 0 {
+				archived[strings.TrimPrefix(entry.Name(), "go")] = true
+				keep = max(keep, n)
+			}
+		}
+	}
+
+	// Copy versions that can't be recomputed before exporting, so the
+	// index rebuilt by exportAll covers them too
+	outPlatformDir := filepath.Join(outputDir, platform)
+	jsonFiles, err := filepath.Glob(filepath.Join(platformDir, "go*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var kept []string
+	for _, f := range jsonFiles {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var vd VersionData
+		if err := json.Unmarshal(data, &vd); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(f), err)
+		}
+		if archived[vd.Version] {
+			continue
+		}
+		if err := os.MkdirAll(outPlatformDir, 0755); err != nil {
+			return nil, err
+		}
+		if err := copyPreservingMtime(f, filepath.Join(outPlatformDir, filepath.Base(f))); err != nil {
+			return nil, err
+		}
+		kept = append(kept, vd.Version)
+	}
+	sort.Strings(kept)
+
+	if len(archived) == 0 {
+		fmt.Println("  No raw archive; copying exported versions unchanged")
+		return kept, rebuildIndex(outPlatformDir, outputDir, platform, opts)
+	}
+
+	// Carry the whole archive into the new tree so it can be re-analyzed again
+	opts.KeepRaw = keep
+	return kept, exportAll(rawDir, outputDir, platform, opts)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReanalyze(t *testing.T) {
+	tmpDir := t.TempDir()
+	versionDir := filepath.Join(tmpDir, "results", "go1.25")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatalf("failed to create results dir: %v", err)
+	}
+	results := "goos: linux\ngoarch: amd64\ncpu: Test CPU\n" +
+		"BenchmarkFoo-4   \t1000\t100.0 ns/op\t0 B/op\t0 allocs/op\n" +
+		"BenchmarkFoo-4   \t1000\t110.0 ns/op\t0 B/op\t0 allocs/op\n" +
+		"BenchmarkFoo-4   \t1000\t130.0 ns/op\t0 B/op\t0 allocs/op\n"
+	if err := os.WriteFile(filepath.Join(versionDir, "run1.txt"), []byte(results), 0644); err != nil {
+		t.Fatalf("failed to write results: %v", err)
+	}
+
+	dataDir := filepath.Join(tmpDir, "data")
+	if err := exportAll(filepath.Join(tmpDir, "results"), dataDir, "linux-amd64", ExportOptions{KeepRaw: 1}); err != nil {
+		t.Fatalf("exportAll failed: %v", err)
+	}
+
+	// A version exported before raw archiving existed
+	legacy, err := json.Marshal(VersionData{Version: "1.24", Benchmarks: map[string]Benchmark{
+		"BenchmarkFoo": {Name: "BenchmarkFoo", NsPerOp: 90},
+	}})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "linux-amd64", "go1.24.json"), legacy, 0644); err != nil {
+		t.Fatalf("failed to write legacy version: %v", err)
+	}
+
+	if err := reanalyze(dataDir, dataDir, ExportOptions{}); err == nil {
+		t.Error("expected re-analysis into the source tree to be refused")
+	}
+
+	outputDir := filepath.Join(tmpDir, "reanalyzed")
+	if err := reanalyze(dataDir, outputDir, ExportOptions{}); err != nil {
+		t.Fatalf("reanalyze failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "linux-amd64", "go1.25.json"))
+	if err != nil {
+		t.Fatalf("re-analyzed version missing: %v", err)
+	}
+	var vd VersionData
+	if err := json.Unmarshal(data, &vd); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if foo := vd.Benchmarks["BenchmarkFoo"]; foo.NsPerOpP50 != 110 || foo.Samples != 3 {
+		t.Errorf("BenchmarkFoo = %+v, want p50 110 over 3 samples", foo)
+	}
+	if len(vd.RawFiles) != 1 {
+		t.Errorf("raw_files = %v, want the archive carried over", vd.RawFiles)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "linux-amd64", "raw", "go1.25", "run1.txt")); err != nil {
+		t.Errorf("raw archive not carried over: %v", err)
+	}
+
+	data, err = os.ReadFile(filepath.Join(outputDir, "linux-amd64", "index.json"))
+	if err != nil {
+		t.Fatalf("index missing: %v", err)
+	}
+	var idx IndexData
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatalf("failed to parse index: %v", err)
+	}
+	if len(idx.Versions) != 2 {
+		t.Errorf("index versions = %+v, want legacy 1.24 and re-analyzed 1.25", idx.Versions)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "platforms.json")); err != nil {
+		t.Errorf("platforms.json missing: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultRepoURL    = "https://github.com/astavonin/go-optimization-guide"
+	defaultRepoBranch = "main"
+
+	// GitHub layout; GitLab uses "-/blob/{branch}", Gitea "src/branch/{branch}"
+	defaultSourcePath = "blob/{branch}"
+)
+
+// RepoOptions describes where benchmark sources are browsable, so forks and
+// private deployments get working source links in the dashboard.
+type RepoOptions struct {
+	URL        string // repository web URL, e.g. https://github.com/user/fork
+	Branch     string // substituted for {branch} in SourcePath
+	SourcePath string // path between URL and the source file, e.g. "blob/{branch}"
+}
+
+// repositoryInfo resolves the options into the index's repository block,
+// filling unset fields with the upstream defaults.
+func (r RepoOptions) repositoryInfo() (RepositoryInfo, error) {
+	repoURL := strings.TrimRight(r.URL, "/")
+	if repoURL == "" {
+		repoURL = defaultRepoURL
+	}
+	branch := r.Branch
+	if branch == "" {
+		branch = defaultRepoBranch
+	}
+	sourcePath := r.SourcePath
+	if sourcePath == "" {
+		sourcePath = defaultSourcePath
+	}
+
+	// The dashboard only renders https links
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return RepositoryInfo{}, fmt.Errorf("invalid repository URL %q (want https://host/path)", r.URL)
+	}
+	sourcePath = strings.Trim(strings.ReplaceAll(sourcePath, "{branch}", branch), "/")
+
+	return RepositoryInfo{URL: repoURL, SourcePath: sourcePath}, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestRepositoryInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    RepoOptions
+		want    RepositoryInfo
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: RepositoryInfo{URL: defaultRepoURL, SourcePath: "blob/main"},
+		},
+		{
+			name: "fork on another branch",
+			opts: RepoOptions{URL: "https://github.com/someone/fork/", Branch: "perf"},
+			want: RepositoryInfo{URL: "https://github.com/someone/fork", SourcePath: "blob/perf"},
+		},
+		{
+			name: "gitlab layout",
+			opts: RepoOptions{URL: "https://gitlab.example.com/team/guide", SourcePath: "/-/blob/{branch}/"},
+			want: RepositoryInfo{URL: "https://gitlab.example.com/team/guide", SourcePath: "-/blob/main"},
+		},
+		{name: "http rejected", opts: RepoOptions{URL: "http://example.com/repo"}, wantErr: true},
+		{name: "javascript rejected", opts: RepoOptions{URL: "javascript:alert(1)"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.repositoryInfo()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("repositoryInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("repositoryInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/astavonin/go-optimization-guide/perf-tracking/tools/perfbench/units"
+)
+
+// Snippet is the generated summary of one benchmark on one platform that
+// articles embed instead of hand-pasted numbers: its latest release
+// result and the change from the release before
+type Snippet struct {
+	Benchmark   string   `json:"benchmark"`
+	Description string   `json:"description,omitempty"`
+	Platform    string   `json:"platform"`
+	Tags        []string `json:"tags,omitempty"`
+	Reliability string   `json:"reliability,omitempty"`
+
+	Version     string  `json:"version"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	Display     string  `json:"display"` // NsPerOp in its display unit, e.g. "1.23 µs"
+	BytesPerOp  int64   `json:"bytes_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+
+	// The previous release, when it ran the benchmark too
+	PreviousVersion string   `json:"previous_version,omitempty"`
+	PreviousNsPerOp float64  `json:"previous_ns_per_op,omitempty"`
+	DeltaPercent    *float64 `json:"delta_percent,omitempty"` // ns/op change from PreviousVersion
+	Trend           string   `json:"trend,omitempty"`         // "faster", "slower" or "unchanged"
+}
+
+// buildSnippets summarizes the benchmarks passing filter in the latest
+// release version of an exported platform directory, as export -results-dir
+// writes it, against the release before. Pre-release versions are skipped so
+// articles don't quote numbers that will change.
+func buildSnippets(platformDir string, filter BenchmarkFilter) ([]Snippet, error) {
+	data, err := os.ReadFile(filepath.Join(platformDir, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	var index IndexData
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(platformDir, "index.json"), err)
+	}
+
+	var releases []VersionInfo
+	for _, v := range index.Versions {
+		if !v.Provisional && !isPreRelease(v.Version) {
+			releases = append(releases, v)
+		}
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("%s lists no release versions", platformDir)
+	}
+	slices.SortFunc(releases, func(a, b VersionInfo) int { return compareVersionStrings(a.Version, b.Version) })
+
+	latest, err := readVersionData(filepath.Join(platformDir, releases[len(releases)-1].File))
+	if err != nil {
+		return nil, err
+	}
+	var previous *VersionData
+	if len(releases) > 1 {
+		if previous, err = readVersionData(filepath.Join(platformDir, releases[len(releases)-2].File)); err != nil {
+			return nil, err
+		}
+	}
+
+	info := make(map[string]BenchmarkInfo, len(index.Benchmarks))
+	for _, b := range index.Benchmarks {
+		info[b.Name] = b
+	}
+
+	var snippets []Snippet
+	for name, b := range latest.Benchmarks {
+		if !filter.Match(name) {
+			continue
+		}
+		s := Snippet{
+			Benchmark:   name,
+			Description: getBenchmarkDescription(name),
+			Platform:    filepath.Base(platformDir),
+			Tags:        getBenchmarkTags(name),
+			Reliability: info[name].Reliability,
+			Version:     latest.Version,
+			NsPerOp:     b.NsPerOp,
+			Display:     units.Time(b.NsPerOp).Format(b.NsPerOp),
+			BytesPerOp:  b.BytesPerOp,
+			AllocsPerOp: b.AllocsPerOp,
+		}
+		if previous != nil {
+			if p, ok := previous.Benchmarks[name]; ok && p.NsPerOp > 0 {
+				delta := (b.NsPerOp/p.NsPerOp - 1) * 100
+				s.PreviousVersion = previous.Version
+				s.PreviousNsPerOp = p.NsPerOp
+				s.DeltaPercent = &delta
+				s.Trend = snippetTrend(delta, info[name].MaxCV*100)
+			}
+		}
+		snippets = append(snippets, s)
+	}
+	slices.SortFunc(snippets, func(a, b Snippet) int { return strings.Compare(a.Benchmark, b.Benchmark) })
+	return snippets, nil
+}
+
+// readVersionData reads an exported version JSON
+func readVersionData(path string) (*VersionData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vd VersionData
+	if err := json.Unmarshal(data, &vd); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &vd, nil
+}
+
+// snippetTrend words a change of deltaPercent. Exported results keep no
+// samples to test, so a change within the benchmark's noise band (its max
+// CV, in percent) or the comparison noise threshold reads as unchanged.
+func snippetTrend(deltaPercent, noiseBand float64) string {
+	switch {
+	case math.Abs(deltaPercent) <= max(noiseBand, noiseThresholdPercent):
+		return "unchanged"
+	case deltaPercent < 0:
+		return "faster"
+	default:
+		return "slower"
+	}
+}
+
+// markdown renders s as a one-line fragment for an article, e.g.
+//
+//	**1.23 µs/op**, 64 B/op, 1 allocs/op on Go 1.26 (linux-amd64), 12.5% faster than Go 1.25
+func (s Snippet) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s/op**, %d B/op, %d allocs/op on Go %s (%s)", s.Display, s.BytesPerOp, s.AllocsPerOp, s.Version, s.Platform)
+	switch s.Trend {
+	case "":
+	case "unchanged":
+		fmt.Fprintf(&b, ", on par with Go %s", s.PreviousVersion)
+	default:
+		fmt.Fprintf(&b, ", %.1f%% %s than Go %s", math.Abs(*s.DeltaPercent), s.Trend, s.PreviousVersion)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// snippetFile is the markdown fragment path of a benchmark below the output
+// directory: one directory level per sub-benchmark, so
+// BenchmarkIOReadAll/1KB is BenchmarkIOReadAll/1KB.md
+func snippetFile(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = strings.Map(func(r rune) rune {
+			if r == '-' || r == '_' || r == '.' || r == '=' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+				return r
+			}
+			return '_'
+		}, p)
+		if parts[i] == "." || parts[i] == ".." {
+			parts[i] = "_"
+		}
+	}
+	return filepath.Join(parts...) + ".md"
+}
+
+// writeSnippets writes snippets.json, every snippet keyed by benchmark
+// name, and a markdown fragment per benchmark to outputDir
+func writeSnippets(outputDir string, snippets []Snippet) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	byName := make(map[string]Snippet, len(snippets))
+	for _, s := range snippets {
+		byName[s.Benchmark] = s
+		path := filepath.Join(outputDir, snippetFile(s.Benchmark))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create snippet directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(s.markdown()), 0644); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(byName, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, "snippets.json"), append(data, '\n'), 0644)
+}
+
+// generateSnippets writes the snippets of every platform listed in the
+// platforms.json of dataDir, or only of platform when set, to
+// outputDir/<platform>/
+func generateSnippets(dataDir, outputDir, platform string, filter BenchmarkFilter) error {
+	data, err := os.ReadFile(filepath.Join(dataDir, "platforms.json"))
+	if err != nil {
+		return err
+	}
+	var platforms PlatformsData
+	if err := json.Unmarshal(data, &platforms); err != nil {
+		return fmt.Errorf("parsing %s: %w", filepath.Join(dataDir, "platforms.json"), err)
+	}
+
+	found := false
+	for _, p := range platforms.Platforms {
+		if platform != "" && p.Name != platform {
+			continue
+		}
+		found = true
+		snippets, err := buildSnippets(filepath.Join(dataDir, p.Name), filter)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		if err := writeSnippets(filepath.Join(outputDir, p.Name), snippets); err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		version := "-"
+		if len(snippets) > 0 {
+			version = "go" + snippets[0].Version
+		}
+		fmt.Printf("  %s: %d snippets (%s)\n", p.Name, len(snippets), version)
+	}
+	if !found {
+		return fmt.Errorf("platform %q is not listed in %s", platform, filepath.Join(dataDir, "platforms.json"))
+	}
+	return nil
+}
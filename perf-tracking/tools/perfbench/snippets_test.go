@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSnippets(t *testing.T) {
+	dataDir := t.TempDir()
+	write := func(name string, v any) {
+		path := filepath.Join(dataDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	version := func(v string, benchmarks ...Benchmark) VersionData {
+		vd := VersionData{Version: v, Benchmarks: make(map[string]Benchmark)}
+		for _, b := range benchmarks {
+			vd.Benchmarks[b.Name] = b
+		}
+		return vd
+	}
+
+	write("platforms.json", PlatformsData{Platforms: []PlatformInfo{{Name: "linux-amd64"}}})
+	write("linux-amd64/index.json", IndexData{
+		// Out of order, with a pre-release that must not be quoted
+		Versions: []VersionInfo{
+			{Version: "1.25", File: "go1.25.json"},
+			{Version: "1.24", File: "go1.24.json"},
+			{Version: "1.26rc1", File: "go1.26rc1.json", Provisional: true},
+		},
+		Benchmarks: []BenchmarkInfo{
+			{Name: "BenchmarkIOReadAll/Size1MB", Reliability: "reliable", MaxCV: 0.01},
+			{Name: "BenchmarkGCLatency", Reliability: "noisy", MaxCV: 0.08},
+		},
+	})
+	write("linux-amd64/go1.24.json", version("1.24",
+		Benchmark{Name: "BenchmarkIOReadAll/Size1MB", NsPerOp: 1e6},
+		Benchmark{Name: "BenchmarkGCLatency", NsPerOp: 100e3}))
+	write("linux-amd64/go1.25.json", version("1.25",
+		Benchmark{Name: "BenchmarkIOReadAll/Size1MB", NsPerOp: 480e3, BytesPerOp: 2 << 20, AllocsPerOp: 25},
+		Benchmark{Name: "BenchmarkGCLatency", NsPerOp: 95e3},
+		Benchmark{Name: "BenchmarkTinyAlloc", NsPerOp: 12}))
+	write("linux-amd64/go1.26rc1.json", version("1.26rc1",
+		Benchmark{Name: "BenchmarkIOReadAll/Size1MB", NsPerOp: 1}))
+
+	outputDir := filepath.Join(t.TempDir(), "snippets")
+	if err := generateSnippets(dataDir, outputDir, "", BenchmarkFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "linux-amd64", "snippets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snippets map[string]Snippet
+	if err := json.Unmarshal(data, &snippets); err != nil {
+		t.Fatal(err)
+	}
+	if len(snippets) != 3 {
+		t.Fatalf("got %d snippets, want 3", len(snippets))
+	}
+
+	readAll := snippets["BenchmarkIOReadAll/Size1MB"]
+	if readAll.Version != "1.25" || readAll.PreviousVersion != "1.24" || readAll.Display != "480.00 µs" ||
+		readAll.DeltaPercent == nil || *readAll.DeltaPercent != -52 || readAll.Trend != "faster" || readAll.Reliability != "reliable" {
+		t.Errorf("IOReadAll snippet = %+v", readAll)
+	}
+	// -5% is within GCLatency's 8% noise band
+	if gc := snippets["BenchmarkGCLatency"]; gc.Trend != "unchanged" {
+		t.Errorf("GCLatency trend = %q, want unchanged", gc.Trend)
+	}
+	// New in the latest release: nothing to compare with
+	if tiny := snippets["BenchmarkTinyAlloc"]; tiny.DeltaPercent != nil || tiny.Trend != "" {
+		t.Errorf("TinyAlloc snippet = %+v", tiny)
+	}
+
+	md, err := os.ReadFile(filepath.Join(outputDir, "linux-amd64", "BenchmarkIOReadAll", "Size1MB.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "**480.00 µs/op**, 2097152 B/op, 25 allocs/op on Go 1.25 (linux-amd64), 52.0% faster than Go 1.24\n"
+	if string(md) != want {
+		t.Errorf("markdown = %q, want %q", md, want)
+	}
+
+	if err := generateSnippets(dataDir, outputDir, "windows-amd64", BenchmarkFilter{}); err == nil {
+		t.Error("unknown platform succeeded, want error")
+	}
+}
+
+func TestSnippetFile(t *testing.T) {
+	for name, want := range map[string]string{
+		"BenchmarkGCLatency":         "BenchmarkGCLatency.md",
+		"BenchmarkIOReadAll/Size1KB": filepath.Join("BenchmarkIOReadAll", "Size1KB.md"),
+		"BenchmarkLookup/Keys=8/Map": filepath.Join("BenchmarkLookup", "Keys=8", "Map.md"),
+		"BenchmarkX/../etc/a b:c":    filepath.Join("BenchmarkX", "_", "etc", "a_b_c.md"),
+	} {
+		if got := snippetFile(name); got != want {
+			t.Errorf("snippetFile(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Flat comparison output formats for loading results into spreadsheets,
+// DuckDB or log pipelines
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+// csvColumns are the fixed leading columns of writeCSVComparison
+var csvColumns = []string{
+	"benchmark", "group", "variant", "category",
+	"baseline_ns", "target_ns", "delta_percent",
+	"baseline_allocs", "target_allocs",
+	"baseline_samples", "target_samples", "p_value", "significant",
+	"noise_band_percent",
+	"baseline_ci_low_ns", "baseline_ci_high_ns", "target_ci_low_ns", "target_ci_high_ns",
+	"delta_policy", "delta",
+}
+
+// writeCSVComparison writes comparisons as CSV with a header row, one row
+// per comparison. Every metric unit any comparison carries gets
+// "<unit> baseline", "<unit> target" and "<unit> delta_percent" columns
+// after the fixed ones, sorted by unit; they are empty on rows without that
+// metric, as are p_value for untested rows, noise_band_percent when no
+// band is known and the confidence interval bounds of a side with fewer
+// than two samples.
+func writeCSVComparison(w io.Writer, comparisons []Comparison) error {
+	var metricUnits []string
+	for _, c := range comparisons {
+		for unit := range c.Metrics {
+			if !slices.Contains(metricUnits, unit) {
+				metricUnits = append(metricUnits, unit)
+			}
+		}
+	}
+	slices.Sort(metricUnits)
+
+	cw := csv.NewWriter(w)
+	header := slices.Clone(csvColumns)
+	for _, unit := range metricUnits {
+		header = append(header, unit+" baseline", unit+" target", unit+" delta_percent")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range comparisons {
+		pValue := ""
+		if c.tested() {
+			pValue = formatCSVFloat(c.PValue)
+		}
+		noiseBand := ""
+		if c.NoiseBandPercent > 0 {
+			noiseBand = formatCSVFloat(c.NoiseBandPercent)
+		}
+		row := []string{
+			c.Benchmark, c.Group, strings.Join(c.Variant, "/"), getBenchmarkCategory(c.Benchmark),
+			formatCSVFloat(c.BaselineNs), formatCSVFloat(c.TargetNs), formatCSVFloat(c.DeltaPercent),
+			strconv.FormatInt(c.BaselineAllocs, 10), strconv.FormatInt(c.TargetAllocs, 10),
+			strconv.Itoa(c.BaselineSamples), strconv.Itoa(c.TargetSamples), pValue, strconv.FormatBool(c.Significant),
+			noiseBand,
+		}
+		for _, ci := range []*Interval{c.BaselineCI, c.TargetCI} {
+			if ci != nil {
+				row = append(row, formatCSVFloat(ci.Low), formatCSVFloat(ci.High))
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		row = append(row, policyNamed(c.DeltaPolicy).name(), formatCSVFloat(c.change()))
+		for _, unit := range metricUnits {
+			if m, ok := c.Metrics[unit]; ok {
+				row = append(row, formatCSVFloat(m.Baseline), formatCSVFloat(m.Target), formatCSVFloat(m.DeltaPercent))
+			} else {
+				row = append(row, "", "", "")
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCSVFloat formats v without an exponent, which spreadsheets would
+// otherwise have to guess at
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// writeJSONLComparison writes each comparison as one JSON object per line,
+// the same objects as the "comparisons" array of -output
+func writeJSONLComparison(w io.Writer, comparisons []Comparison) error {
+	enc := json.NewEncoder(w)
+	for _, c := range comparisons {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
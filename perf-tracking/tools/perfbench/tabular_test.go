@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func tabularComparisons() []Comparison {
+	return []Comparison{
+		{
+			Benchmark: "BenchmarkAESCTR/1KB", Group: "BenchmarkAESCTR", Variant: []string{"1KB"},
+			BaselineNs: 1200, TargetNs: 1000, DeltaPercent: -16.666666666666664,
+			Delta: -200, DeltaPolicy: policyDifference,
+			BaselineAllocs: 3, TargetAllocs: 2, BaselineSamples: 6, TargetSamples: 6,
+			PValue: 0.002, Significant: true, NoiseBandPercent: 4.5,
+			BaselineCI: &Interval{Low: 1150, High: 1250}, TargetCI: &Interval{Low: 980, High: 1020},
+			Metrics: map[string]*MetricDelta{"B/op": {Baseline: 96, Target: 64, DeltaPercent: -33.33333333333333, Significant: true}},
+		},
+		{
+			Benchmark: "BenchmarkGCLatency", Group: "BenchmarkGCLatency",
+			BaselineNs: 0.25, TargetNs: 0.25, BaselineSamples: 1, TargetSamples: 1,
+		},
+	}
+}
+
+func TestWriteCSVComparison(t *testing.T) {
+	var sb strings.Builder
+	if err := writeCSVComparison(&sb, tabularComparisons()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v\n%s", err, sb.String())
+	}
+	want := [][]string{
+		{"benchmark", "group", "variant", "category", "baseline_ns", "target_ns", "delta_percent",
+			"baseline_allocs", "target_allocs", "baseline_samples", "target_samples", "p_value", "significant",
+			"noise_band_percent", "baseline_ci_low_ns", "baseline_ci_high_ns", "target_ci_low_ns", "target_ci_high_ns",
+			"delta_policy", "delta", "B/op baseline", "B/op target", "B/op delta_percent"},
+		{"BenchmarkAESCTR/1KB", "BenchmarkAESCTR", "1KB", "stdlib", "1200", "1000", "-16.666666666666664",
+			"3", "2", "6", "6", "0.002", "true", "4.5", "1150", "1250", "980", "1020", "difference", "-200", "96", "64", "-33.33333333333333"},
+		// Untested, no noise band, no intervals and no B/op: those cells are
+		// empty. Without a policy the change is the ratio.
+		{"BenchmarkGCLatency", "BenchmarkGCLatency", "", "runtime", "0.25", "0.25", "0",
+			"0", "0", "1", "1", "", "false", "", "", "", "", "", "ratio", "0", "", "", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%s", len(records), len(want), sb.String())
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("record %d:\n got %q\nwant %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestWriteJSONLComparison(t *testing.T) {
+	comparisons := tabularComparisons()
+	var sb strings.Builder
+	if err := writeJSONLComparison(&sb, comparisons); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) != len(comparisons) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(comparisons), sb.String())
+	}
+	for i, line := range lines {
+		var c Comparison
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v", i+1, err)
+		}
+		if c.Benchmark != comparisons[i].Benchmark || c.TargetNs != comparisons[i].TargetNs {
+			t.Errorf("line %d = %+v, want %+v", i+1, c, comparisons[i])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package units picks human-friendly display units for benchmark values.
+//
+// Stored data always stays in canonical units (ns/op, B/op, MB/s as printed
+// by go test); these helpers only decide how to present it. Callers choose a
+// unit once per benchmark from the smallest value they will show, so every
+// value in a row or chart shares a unit and none drops below 1.
+package units
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Unit is a display unit and its size in the canonical unit.
+type Unit struct {
+	Symbol string
+	Scale  float64
+}
+
+var (
+	Nanosecond  = Unit{"ns", 1}
+	Microsecond = Unit{"µs", 1e3}
+	Millisecond = Unit{"ms", 1e6}
+	Second      = Unit{"s", 1e9}
+
+	Byte     = Unit{"B", 1}
+	Kilobyte = Unit{"KB", 1e3}
+	Megabyte = Unit{"MB", 1e6}
+	Gigabyte = Unit{"GB", 1e9}
+
+	// Throughput is canonical in MB/s, matching the testing package (1e6 bytes)
+	MegabytePerSec = Unit{"MB/s", 1}
+	GigabytePerSec = Unit{"GB/s", 1e3}
+
+	// Operation rates are canonical in ops/s, 1e9 / ns/op
+	OpPerSec     = Unit{"ops/s", 1}
+	KiloOpPerSec = Unit{"Kops/s", 1e3}
+	MegaOpPerSec = Unit{"Mops/s", 1e6}
+	GigaOpPerSec = Unit{"Gops/s", 1e9}
+)
+
+var (
+	timeUnits       = []Unit{Nanosecond, Microsecond, Millisecond, Second}
+	byteUnits       = []Unit{Byte, Kilobyte, Megabyte, Gigabyte}
+	throughputUnits = []Unit{MegabytePerSec, GigabytePerSec}
+	rateUnits       = []Unit{OpPerSec, KiloOpPerSec, MegaOpPerSec, GigaOpPerSec}
+)
+
+// Time returns the unit for displaying values whose smallest is minNs.
+func Time(minNs float64) Unit { return pick(timeUnits, minNs) }
+
+// Bytes returns the unit for displaying values whose smallest is minBytes.
+func Bytes(minBytes float64) Unit { return pick(byteUnits, minBytes) }
+
+// Throughput returns the unit for displaying values whose smallest is minMBPerSec.
+func Throughput(minMBPerSec float64) Unit { return pick(throughputUnits, minMBPerSec) }
+
+// Rate returns the unit for displaying values whose smallest is minOpsPerSec.
+func Rate(minOpsPerSec float64) Unit { return pick(rateUnits, minOpsPerSec) }
+
+// pick returns the largest unit in which v is still at least 1. Zero,
+// negative and non-finite values keep the canonical unit.
+func pick(candidates []Unit, v float64) Unit {
+	best := candidates[0]
+	if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return best
+	}
+	for _, u := range candidates[1:] {
+		if v < u.Scale {
+			break
+		}
+		best = u
+	}
+	return best
+}
+
+// Convert expresses a canonical value in u.
+func (u Unit) Convert(v float64) float64 {
+	return v / u.Scale
+}
+
+// Format renders a canonical value in u with two decimals, e.g. "1.25 ms".
+func (u Unit) Format(v float64) string {
+	return fmt.Sprintf("%.2f %s", u.Convert(v), u.Symbol)
+}
+
+// Pad returns the symbol padded to width runes, for aligned table columns.
+// fmt's width verbs count bytes, which misaligns multi-byte symbols like µs.
+func (u Unit) Pad(width int) string {
+	s := u.Symbol
+	for n := utf8.RuneCountInString(s); n < width; n++ {
+		s += " "
+	}
+	return s
+}
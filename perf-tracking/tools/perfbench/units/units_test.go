@@ -0,0 +1,68 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		ns   float64
+		want Unit
+	}{
+		{0, Nanosecond},
+		{-5, Nanosecond},
+		{math.Inf(1), Nanosecond},
+		{3.2, Nanosecond},
+		{999.99, Nanosecond},
+		{1000, Microsecond},
+		{15_300, Microsecond},
+		{2_500_000, Millisecond},
+		{4.2e9, Second},
+		{9e12, Second},
+	}
+	for _, tt := range tests {
+		if got := Time(tt.ns); got != tt.want {
+			t.Errorf("Time(%v) = %q, want %q", tt.ns, got.Symbol, tt.want.Symbol)
+		}
+	}
+}
+
+func TestBytesAndThroughput(t *testing.T) {
+	if got := Bytes(512); got != Byte {
+		t.Errorf("Bytes(512) = %q, want B", got.Symbol)
+	}
+	if got := Bytes(4096); got != Kilobyte {
+		t.Errorf("Bytes(4096) = %q, want KB", got.Symbol)
+	}
+	if got := Bytes(3e9); got != Gigabyte {
+		t.Errorf("Bytes(3e9) = %q, want GB", got.Symbol)
+	}
+	if got := Throughput(770.04); got != MegabytePerSec {
+		t.Errorf("Throughput(770.04) = %q, want MB/s", got.Symbol)
+	}
+	if got := Throughput(12_500); got != GigabytePerSec {
+		t.Errorf("Throughput(12500) = %q, want GB/s", got.Symbol)
+	}
+	if got := Rate(950); got != OpPerSec {
+		t.Errorf("Rate(950) = %q, want ops/s", got.Symbol)
+	}
+	if got := Rate(333_333_333); got != MegaOpPerSec {
+		t.Errorf("Rate(333333333) = %q, want Mops/s", got.Symbol)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Millisecond.Format(1_250_000); got != "1.25 ms" {
+		t.Errorf("Format = %q, want %q", got, "1.25 ms")
+	}
+	if got := Microsecond.Format(800); got != "0.80 µs" {
+		t.Errorf("Format = %q, want %q", got, "0.80 µs")
+	}
+	if got := Second.Pad(2); got != "s " {
+		t.Errorf("Pad = %q, want %q", got, "s ")
+	}
+	if got := Microsecond.Pad(2); got != "µs" {
+		t.Errorf("Pad = %q, want %q", got, "µs")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// splitBenchmarkName separates a full benchmark name into its group (the
+// top-level benchmark) and the sub-benchmark path, e.g.
+// "BenchmarkTLSHandshake/TLS13/Parallel_10" -> "BenchmarkTLSHandshake",
+// ["TLS13", "Parallel_10"].
+func splitBenchmarkName(name string) (string, []string) {
+	group := benchmarkBaseName(name)
+	idx := strings.Index(name, "/")
+	if idx == -1 {
+		return group, nil
+	}
+	return group, strings.Split(name[idx+1:], "/")
+}
+
+// variantDimensions collects, per group, the distinct values seen at each
+// sub-benchmark level. For TLS handshakes run as TLS12/TLS13 x Parallel_10/
+// Parallel_30 this yields [["TLS12", "TLS13"], ["Parallel_10", "Parallel_30"]].
+func variantDimensions(names []string) map[string][][]string {
+	seen := make(map[string][]map[string]bool)
+	for _, name := range names {
+		group, variant := splitBenchmarkName(name)
+		for level, value := range variant {
+			for len(seen[group]) <= level {
+				seen[group] = append(seen[group], make(map[string]bool))
+			}
+			seen[group][level][value] = true
+		}
+	}
+
+	dims := make(map[string][][]string, len(seen))
+	for group, levels := range seen {
+		for _, values := range levels {
+			list := make([]string, 0, len(values))
+			for v := range values {
+				list = append(list, v)
+			}
+			sort.Slice(list, func(i, j int) bool { return naturalLess(list[i], list[j]) })
+			dims[group] = append(dims[group], list)
+		}
+	}
+	return dims
+}
+
+// naturalLess orders strings with embedded numbers numerically, so
+// "Parallel_10" sorts before "Parallel_100" and "Size4KB" before "Size16KB".
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			if len(strings.TrimLeft(da, "0")) != len(strings.TrimLeft(db, "0")) {
+				return len(strings.TrimLeft(da, "0")) < len(strings.TrimLeft(db, "0"))
+			}
+			if da != db {
+				return strings.TrimLeft(da, "0") < strings.TrimLeft(db, "0")
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
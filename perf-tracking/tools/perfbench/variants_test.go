@@ -0,0 +1,49 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitBenchmarkName(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantGroup   string
+		wantVariant []string
+	}{
+		{"BenchmarkSmallAllocation", "BenchmarkSmallAllocation", nil},
+		{"BenchmarkSmallAllocation-16", "BenchmarkSmallAllocation", nil},
+		{"BenchmarkAESCTR/Size1KB", "BenchmarkAESCTR", []string{"Size1KB"}},
+		{"BenchmarkTLSHandshake/TLS13/Parallel_10", "BenchmarkTLSHandshake", []string{"TLS13", "Parallel_10"}},
+	}
+
+	for _, tt := range tests {
+		group, variant := splitBenchmarkName(tt.name)
+		if group != tt.wantGroup || !reflect.DeepEqual(variant, tt.wantVariant) {
+			t.Errorf("splitBenchmarkName(%q) = %q, %v; want %q, %v", tt.name, group, variant, tt.wantGroup, tt.wantVariant)
+		}
+	}
+}
+
+func TestVariantDimensions(t *testing.T) {
+	dims := variantDimensions([]string{
+		"BenchmarkTLSHandshake/TLS13/Parallel_30",
+		"BenchmarkTLSHandshake/TLS12/Parallel_10",
+		"BenchmarkTLSHandshake/TLS13/Parallel_10",
+		"BenchmarkTLSHandshake/TLS12/Parallel_100",
+		"BenchmarkAESCTR/Size64KB",
+		"BenchmarkAESCTR/Size1KB",
+		"BenchmarkSmallAllocation",
+	})
+
+	want := [][]string{{"TLS12", "TLS13"}, {"Parallel_10", "Parallel_30", "Parallel_100"}}
+	if got := dims["BenchmarkTLSHandshake"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("TLS dimensions = %v, want %v", got, want)
+	}
+	if got := dims["BenchmarkAESCTR"]; !reflect.DeepEqual(got, [][]string{{"Size1KB", "Size64KB"}}) {
+		t.Errorf("AESCTR dimensions = %v", got)
+	}
+	if _, ok := dims["BenchmarkSmallAllocation"]; ok {
+		t.Error("benchmark without sub-benchmarks should have no dimensions")
+	}
+}
@@ -10,7 +10,11 @@ sys.path.insert(0, str(Path(__file__).parent))
 from collect_benchmarks import (
     BenchmarkParser, BenchmarkResult, VARIANCE_WARNING,
     derive_original_output_file, parse_benchmark_file, merge_benchmark_results,
-    PackageSection, BenchmarkFile
+    PackageSection, BenchmarkFile, CpuAffinity, parse_cpu_list, format_cpu_list,
+    write_run_metadata, SystemTuner, parse_proc_cpuinfo, parse_meminfo, parse_pmset_batt,
+    BenchmarkRunner, ALTERNATIVES_PACKAGE, BUILD_TARGET_MODULE, summarize_build_runs,
+    gctrace_path, extract_gctrace_lines, exectrace_dir, memprofile_dir, benchmark_file,
+    CONTENTION_PROFILE_ENV, ALLOC_SIZE_CLASSES_ENV
 )
 
 
@@ -407,6 +411,243 @@ ok      test    1.0s
             output_path.unlink()
 
 
+def test_cpu_affinity():
+    """Test CPU list parsing and the pinning command prefix."""
+    assert parse_cpu_list("2,3") == [2, 3]
+    assert parse_cpu_list("4-7,9") == [4, 5, 6, 7, 9]
+    assert parse_cpu_list(" 3,1,3 ") == [1, 3]
+    assert format_cpu_list([2, 3, 5]) == "2,3,5"
+
+    for bad in ["", "7-4", "a"]:
+        try:
+            parse_cpu_list(bad)
+            assert False, f"Should have raised ValueError for {bad!r}"
+        except ValueError:
+            pass
+
+    taskset = CpuAffinity(method="taskset", cpus=[2, 3])
+    assert taskset.command_prefix() == ["taskset", "-c", "2,3"]
+    assert taskset.to_metadata() == {'method': 'taskset', 'cpus': [2, 3], 'isolated': False}
+
+    # cpuset pinning is inherited from the runner's cgroup, no prefix needed
+    cpuset = CpuAffinity(method="cpuset", cpus=[4, 5], cgroup="/sys/fs/cgroup/bench", isolated=True)
+    assert cpuset.command_prefix() == []
+    assert cpuset.to_metadata()['cgroup'] == "/sys/fs/cgroup/bench"
+    assert cpuset.to_metadata()['isolated'] is True
+
+    print("✓ CPU affinity test passed")
+
+
+def test_write_run_metadata():
+    """Test that run metadata is written next to the result file."""
+    import json
+
+    with tempfile.TemporaryDirectory() as tmp:
+        metadata = {'system': {'pinning': {'method': 'taskset', 'cpus': [2, 3], 'isolated': False}}}
+        path = write_run_metadata(Path(tmp), "2026-01-26_21-55-10", metadata)
+
+        assert path.name == "2026-01-26_21-55-10_metadata.json"
+        with open(path) as f:
+            assert json.load(f) == metadata
+
+    print("✓ Write run metadata test passed")
+
+
+def test_alternatives_package_location():
+    """Test that the alternatives group is opt-in and runs from its own module."""
+    script_dir = Path(__file__).parent.resolve()
+
+    runner = BenchmarkRunner(script_dir)
+    assert ALTERNATIVES_PACKAGE not in runner.test_packages
+    assert runner.package_location("stdlib") == (runner.benchmarks_dir, "./stdlib/")
+
+    runner = BenchmarkRunner(script_dir, alternatives=True)
+    assert runner.test_packages[-1] == ALTERNATIVES_PACKAGE
+    module_dir, pkg_path = runner.package_location(ALTERNATIVES_PACKAGE)
+    assert module_dir == script_dir.parent / "alternatives"
+    assert pkg_path == "./"
+    assert (module_dir / "go.mod").exists()
+
+    print("✓ Alternatives package location test passed")
+
+
+def test_build_metrics():
+    """Test the build metrics summary and that the build target module exists."""
+    metrics = summarize_build_runs([12.3456, 9.8765, 10.5], 13_607_145)
+    assert metrics['module'] == f"perf-tracking/{BUILD_TARGET_MODULE}"
+    assert metrics['wall_seconds'] == 10.5, "median of the cold builds"
+    assert metrics['wall_seconds_runs'] == [12.346, 9.877, 10.5]
+    assert metrics['binary_bytes'] == 13_607_145
+
+    script_dir = Path(__file__).parent.resolve()
+    runner = BenchmarkRunner(script_dir, build_metrics=False)
+    assert (runner.build_target_dir / "go.mod").exists()
+    assert runner.measure_build(Path("/nonexistent/go")) is None, "disabled runner must not build"
+
+    print("✓ Build metrics test passed")
+
+
+def test_gctrace():
+    """Test gctrace sidecar naming, line extraction and benchmark lookup."""
+    assert gctrace_path(Path("results/go1.25/2026-01-26_21-55-10.txt")) == \
+        Path("results/go1.25/2026-01-26_21-55-10_gctrace.txt")
+
+    output = (
+        "goos: linux\n"
+        "gc 1 @0.002s 5%: 0.027+0.64+0.008 ms clock, 0.027+0/0.15/0.46+0.008 ms cpu, 0->0->0 MB, 4 MB goal, 1 P (forced)\n"
+        "BenchmarkHTTPService/Sequential-16   \tgc 2 @0.004s 5%: 0.007+0.35+0.002 ms clock, 0.007+0/0.03/0.30+0.002 ms cpu, 4->4->1 MB, 4 MB goal, 1 P\n"
+        "    4375\t    118810 ns/op\n"
+        "PASS\n"
+    )
+    lines = extract_gctrace_lines(output)
+    assert len(lines) == 2
+    assert lines[0].startswith("gc 1 @0.002s")
+    assert lines[1].startswith("gc 2 @0.004s"), "gctrace printed after a benchmark name"
+
+    script_dir = Path(__file__).parent.resolve()
+    runner = BenchmarkRunner(script_dir)
+    assert runner.find_benchmark_package("BenchmarkHTTPService/Parallel") == "networking"
+    assert runner.find_benchmark_package("BenchmarkGCThroughput") == "runtime"
+    assert runner.find_benchmark_package("BenchmarkDoesNotExist") is None
+    assert runner.capture_gctrace(Path("/nonexistent/go"), Path("x.txt"), "1s") is None, \
+        "no --gctrace benchmarks must not run anything"
+
+    print("✓ GC trace test passed")
+
+
+def test_exectrace():
+    """Test execution trace naming, which perfbench decodes back to benchmark names."""
+    result_file = Path("results/go1.25/2026-01-26_21-55-10.txt")
+    trace_dir = exectrace_dir(result_file)
+    assert trace_dir == Path("results/go1.25/2026-01-26_21-55-10_exectrace")
+    assert benchmark_file(trace_dir, "BenchmarkHTTPService", ".trace") == trace_dir / "BenchmarkHTTPService.trace"
+    assert benchmark_file(trace_dir, "BenchmarkHTTPService/Ramp/Clients_16", ".trace") == \
+        trace_dir / "BenchmarkHTTPService%2FRamp%2FClients_16.trace"
+
+    runner = BenchmarkRunner(Path(__file__).parent.resolve())
+    assert runner.capture_exectrace(Path("/nonexistent/go"), result_file, "1s") is None, \
+        "no --trace benchmarks must not run anything"
+
+    print("✓ Execution trace test passed")
+
+
+def test_memprofile():
+    """Test memory profile naming and that the mode is opt-in."""
+    result_file = Path("results/go1.25/2026-01-26_21-55-10.txt")
+    assert memprofile_dir(result_file) == Path("results/go1.25/2026-01-26_21-55-10_memprofile")
+
+    runner = BenchmarkRunner(Path(__file__).parent.resolve())
+    assert runner.capture_memprofile(Path("/nonexistent/go"), result_file, "1s") is None, \
+        "no --memprofile benchmarks must not run anything"
+
+    print("✓ Memory profile test passed")
+
+
+def test_contention_profile():
+    """Test that --contention-profile is recorded and matches the benchmarks' variable."""
+    script_dir = Path(__file__).parent.resolve()
+    assert 'contention_profile' not in BenchmarkRunner(script_dir).run_metadata()
+    assert BenchmarkRunner(script_dir, contention_profile=True).run_metadata()['contention_profile'] is True
+
+    source = (script_dir.parent / "benchmarks" / "internal" / "contention" / "contention.go").read_text()
+    assert f'const Env = "{CONTENTION_PROFILE_ENV}"' in source
+
+    print("✓ Contention profile test passed")
+
+
+def test_size_classes():
+    """Test that --size-classes is recorded and matches the benchmark's variable."""
+    script_dir = Path(__file__).parent.resolve()
+    assert 'size_classes' not in BenchmarkRunner(script_dir).run_metadata()
+    assert BenchmarkRunner(script_dir, size_classes="all").run_metadata()['size_classes'] == "all"
+
+    source = (script_dir.parent / "benchmarks" / "runtime" / "sizeclass_test.go").read_text()
+    assert f'allocSizeClassesEnv = "{ALLOC_SIZE_CLASSES_ENV}"' in source
+
+    print("✓ Size classes test passed")
+
+
+def test_system_tuner():
+    """Test that tuning applies sysfs knobs, records them, and restores originals."""
+    with tempfile.TemporaryDirectory() as tmp:
+        cpu_root = Path(tmp)
+        (cpu_root / "intel_pstate").mkdir()
+        (cpu_root / "intel_pstate" / "no_turbo").write_text("0\n")
+        for cpu in ("cpu0", "cpu1"):
+            (cpu_root / cpu / "cpufreq").mkdir(parents=True)
+            (cpu_root / cpu / "cpufreq" / "scaling_governor").write_text("powersave\n")
+            for idx, latency in enumerate(["0", "2", "85"]):
+                state = cpu_root / cpu / "cpuidle" / f"state{idx}"
+                state.mkdir(parents=True)
+                (state / "latency").write_text(f"{latency}\n")
+                (state / "disable").write_text("0\n")
+
+        tuner = SystemTuner(disable_turbo=True, performance_governor=True,
+                            disable_idle_states=True, sysfs_cpu=cpu_root)
+        assert tuner.requested()
+        assert tuner.apply() == []
+
+        assert (cpu_root / "intel_pstate" / "no_turbo").read_text().strip() == "1"
+        assert (cpu_root / "cpu1" / "cpufreq" / "scaling_governor").read_text().strip() == "performance"
+        # POLL state (latency 0) stays enabled, deeper states are disabled
+        assert (cpu_root / "cpu0" / "cpuidle" / "state0" / "disable").read_text().strip() == "0"
+        assert (cpu_root / "cpu0" / "cpuidle" / "state2" / "disable").read_text().strip() == "1"
+        assert tuner.to_metadata() == {
+            'turbo_disabled': True,
+            'governor': 'performance',
+            'idle_states_disabled': True,
+        }
+
+        tuner.restore()
+        assert (cpu_root / "intel_pstate" / "no_turbo").read_text().strip() == "0"
+        assert (cpu_root / "cpu1" / "cpufreq" / "scaling_governor").read_text().strip() == "powersave"
+        assert (cpu_root / "cpu0" / "cpuidle" / "state2" / "disable").read_text().strip() == "0"
+
+    # Missing knobs are reported as warnings and not recorded
+    with tempfile.TemporaryDirectory() as tmp:
+        tuner = SystemTuner(disable_turbo=True, sysfs_cpu=Path(tmp))
+        assert len(tuner.apply()) == 1
+        assert tuner.to_metadata() == {}
+
+    assert not SystemTuner().requested()
+
+    print("✓ System tuner test passed")
+
+
+def test_system_info_parsers():
+    """Test the native system info parsers."""
+    cpuinfo = """processor\t: 0
+physical id\t: 0
+core id\t\t: 0
+cpu MHz\t\t: 2900.123
+
+processor\t: 1
+physical id\t: 0
+core id\t\t: 0
+cpu MHz\t\t: 3100.000
+
+processor\t: 2
+physical id\t: 0
+core id\t\t: 1
+cpu MHz\t\t: 2900.000
+"""
+    info = parse_proc_cpuinfo(cpuinfo)
+    # "cpu MHz" is the current clock and must not pass for the base clock
+    assert info == {'logical_cores': 3, 'physical_cores': 2}, info
+
+    # ARM cpuinfo has no core topology
+    assert parse_proc_cpuinfo("processor\t: 0\nBogoMIPS\t: 50.00\n") == {'logical_cores': 1}
+
+    assert parse_meminfo("MemTotal:       16384 kB\nMemFree:  1 kB\n") == 16384 * 1024
+    assert parse_meminfo("") is None
+
+    assert parse_pmset_batt("Now drawing from 'AC Power'\n") == "ac"
+    assert parse_pmset_batt("Now drawing from 'Battery Power'\n -InternalBattery-0") == "battery"
+    assert parse_pmset_batt("") is None
+
+    print("✓ System info parsers test passed")
+
+
 if __name__ == "__main__":
     print("Running collect_benchmarks.py tests...\n")
 
@@ -422,6 +663,17 @@ if __name__ == "__main__":
         test_parse_empty_file()
         test_merge_benchmark_results()
         test_merge_preserves_order()
+        test_cpu_affinity()
+        test_write_run_metadata()
+        test_alternatives_package_location()
+        test_build_metrics()
+        test_gctrace()
+        test_exectrace()
+        test_memprofile()
+        test_contention_profile()
+        test_size_classes()
+        test_system_tuner()
+        test_system_info_parsers()
 
         print("\n" + "="*60)
         print("All tests passed! ✓")